@@ -0,0 +1,127 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors shared by all packages. Failures are returned as, or wrap, one of
+// these so callers can classify them with errors.Is and errors.As instead
+// of matching messages.
+var (
+	ErrCurveMismatch            = errors.New("curve mismatch")
+	ErrUnknownCurve             = errors.New("unknown curve")
+	ErrUnexpectedCommitmentType = errors.New("unexpected commitment type")
+	ErrInvalidCommitment        = errors.New("invalid commitment")
+	ErrInvalidCiphertext        = errors.New("invalid ciphertext")
+	ErrProofVerification        = errors.New("proof does not verify")
+	ErrInvalidComplaint         = errors.New("invalid complaint")
+	ErrInvalidOpening           = errors.New("invalid opening")
+	ErrInvalidSignature         = errors.New("invalid signature")
+	ErrInvalidThreshold         = errors.New("invalid threshold")
+	ErrUnknownOperation         = errors.New("unknown transcript operation")
+	ErrUnexpectedProof          = errors.New("unexpected proof")
+	ErrInvalidRecipients        = errors.New("invalid recipients")
+	// ErrInvalidEncoding is wrapped by decoding failures that are not about
+	// one of the parts above, e.g. an unsupported wire version.
+	ErrInvalidEncoding = errors.New("invalid encoding")
+
+	// ErrMisbehavingDealer matches ErrInvalidProof and ErrInvalidDealing:
+	// the dealer sent something it could not have produced honestly.
+	ErrMisbehavingDealer = errors.New("misbehaving dealer")
+	// ErrMisbehavingSigner matches ErrInvalidSigShare.
+	ErrMisbehavingSigner = errors.New("misbehaving signer")
+	// ErrInsufficientQuorum matches ErrInsufficientDealings,
+	// ErrInsufficientOpenings and ErrInsufficientShares: the inputs are
+	// fine but there are not enough of them yet.
+	ErrInsufficientQuorum = errors.New("insufficient quorum")
+)
+
+// ErrInvalidProof reports a dealing whose zero knowledge proof does not
+// verify.
+type ErrInvalidProof struct {
+	Dealer NodeIndex
+	Err    error
+}
+
+func (e *ErrInvalidProof) Error() string {
+	return fmt.Sprintf("invalid proof in the dealing of dealer %d: %v", e.Dealer, e.Err)
+}
+
+func (e *ErrInvalidProof) Unwrap() error { return e.Err }
+
+func (e *ErrInvalidProof) Is(target error) bool { return target == ErrMisbehavingDealer }
+
+// ErrInvalidDealing reports a dealing that fails verification for any other
+// reason, e.g. a malformed ciphertext or a commitment that does not match
+// the transcript it builds on.
+type ErrInvalidDealing struct {
+	Dealer NodeIndex
+	Err    error
+}
+
+func (e *ErrInvalidDealing) Error() string {
+	return fmt.Sprintf("invalid dealing from dealer %d: %v", e.Dealer, e.Err)
+}
+
+func (e *ErrInvalidDealing) Unwrap() error { return e.Err }
+
+func (e *ErrInvalidDealing) Is(target error) bool { return target == ErrMisbehavingDealer }
+
+// MisbehavingDealer returns the dealer blamed by err, if any.
+func MisbehavingDealer(err error) (NodeIndex, bool) {
+	var proof *ErrInvalidProof
+	if errors.As(err, &proof) {
+		return proof.Dealer, true
+	}
+	var dealing *ErrInvalidDealing
+	if errors.As(err, &dealing) {
+		return dealing.Dealer, true
+	}
+	return 0, false
+}
+
+// ErrInvalidSigShare reports a signature share that does not verify
+// against the transcripts of the signature.
+type ErrInvalidSigShare struct {
+	Signer NodeIndex
+	Err    error
+}
+
+func (e *ErrInvalidSigShare) Error() string {
+	return fmt.Sprintf("invalid signature share of node %d: %v", e.Signer, e.Err)
+}
+
+func (e *ErrInvalidSigShare) Unwrap() error { return e.Err }
+
+func (e *ErrInvalidSigShare) Is(target error) bool { return target == ErrMisbehavingSigner }
+
+type ErrInsufficientDealings struct {
+	Have, Need int
+}
+
+func (e *ErrInsufficientDealings) Error() string {
+	return fmt.Sprintf("insufficient dealings: have %d, need %d", e.Have, e.Need)
+}
+
+func (e *ErrInsufficientDealings) Is(target error) bool { return target == ErrInsufficientQuorum }
+
+type ErrInsufficientOpenings struct {
+	Have, Need int
+}
+
+func (e *ErrInsufficientOpenings) Error() string {
+	return fmt.Sprintf("insufficient openings: have %d, need %d", e.Have, e.Need)
+}
+
+func (e *ErrInsufficientOpenings) Is(target error) bool { return target == ErrInsufficientQuorum }
+
+type ErrInsufficientShares struct {
+	Have, Need int
+}
+
+func (e *ErrInsufficientShares) Error() string {
+	return fmt.Sprintf("insufficient signature shares: have %d, need %d", e.Have, e.Need)
+}
+
+func (e *ErrInsufficientShares) Is(target error) bool { return target == ErrInsufficientQuorum }
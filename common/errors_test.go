@@ -0,0 +1,44 @@
+package common
+
+import (
+	"errors"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestErrorsClassify(t *testing.T) {
+	proof := pkgerrors.Wrap(&ErrInvalidProof{Dealer: 3, Err: ErrProofVerification}, "verifying dealings")
+	assert.True(t, errors.Is(proof, ErrMisbehavingDealer))
+	assert.True(t, errors.Is(proof, ErrProofVerification))
+	assert.False(t, errors.Is(proof, ErrInsufficientQuorum))
+	dealer, ok := MisbehavingDealer(proof)
+	assert.True(t, ok)
+	assert.Equal(t, NodeIndex(3), dealer)
+
+	dealing := &ErrInvalidDealing{Dealer: 5, Err: ErrCurveMismatch}
+	assert.True(t, errors.Is(dealing, ErrMisbehavingDealer))
+	assert.True(t, errors.Is(dealing, ErrCurveMismatch))
+	dealer, ok = MisbehavingDealer(dealing)
+	assert.True(t, ok)
+	assert.Equal(t, NodeIndex(5), dealer)
+
+	_, ok = MisbehavingDealer(ErrCurveMismatch)
+	assert.False(t, ok)
+	assert.False(t, errors.Is(ErrCurveMismatch, ErrMisbehavingDealer))
+
+	share := pkgerrors.Wrap(&ErrInvalidSigShare{Signer: 2, Err: ErrInvalidSignature}, "combining")
+	assert.True(t, errors.Is(share, ErrMisbehavingSigner))
+	assert.True(t, errors.Is(share, ErrInvalidSignature))
+	assert.False(t, errors.Is(share, ErrMisbehavingDealer))
+	_, ok = MisbehavingDealer(share)
+	assert.False(t, ok)
+
+	for _, err := range []error{&ErrInsufficientDealings{1, 2}, &ErrInsufficientOpenings{1, 2}, &ErrInsufficientShares{1, 2}} {
+		assert.True(t, errors.Is(err, ErrInsufficientQuorum))
+		assert.False(t, errors.Is(err, ErrMisbehavingDealer))
+	}
+	var insufficient *ErrInsufficientDealings
+	assert.True(t, errors.As(pkgerrors.WithStack(&ErrInsufficientDealings{Have: 1, Need: 3}), &insufficient))
+	assert.Equal(t, 3, insufficient.Need)
+}
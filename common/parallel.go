@@ -0,0 +1,89 @@
+package common
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+type parallelismKey struct{}
+
+// WithParallelism returns a context that bounds the worker pools of the
+// *Context functions to n goroutines. With n == 1 the work runs in order on
+// the calling goroutine.
+func WithParallelism(ctx context.Context, n int) context.Context {
+	if n < 1 {
+		n = 1
+	}
+	return context.WithValue(ctx, parallelismKey{}, n)
+}
+
+// Parallelism returns the worker pool bound carried by ctx, GOMAXPROCS if
+// none was set.
+func Parallelism(ctx context.Context) int {
+	if n, ok := ctx.Value(parallelismKey{}).(int); ok {
+		return n
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// Sequential is the context the sequential functions pass to their
+// *Context variants.
+func Sequential() context.Context {
+	return WithParallelism(context.Background(), 1)
+}
+
+// ParallelFor calls f(0), ..., f(n-1) on at most Parallelism(ctx)
+// goroutines. Indexes are handed out in increasing order and no new ones
+// are started once f fails or ctx is done, so the returned error is the one
+// of the lowest failing index, as if the calls had run in order. If ctx
+// ends before all calls were made its error is returned.
+func ParallelFor(ctx context.Context, n int, f func(i int) error) error {
+	workers := Parallelism(ctx)
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := f(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var next, done int64
+	var failed int32
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&failed) == 0 && ctx.Err() == nil {
+				i := int(atomic.AddInt64(&next, 1) - 1)
+				if i >= n {
+					return
+				}
+				if errs[i] = f(i); errs[i] != nil {
+					atomic.StoreInt32(&failed, 1)
+				}
+				atomic.AddInt64(&done, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	if int(done) < n {
+		return ctx.Err()
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelForRunsEveryIndexWithinTheBound(t *testing.T) {
+	for _, workers := range []int{1, 3, 16} {
+		ctx := WithParallelism(context.Background(), workers)
+		assert.Equal(t, workers, Parallelism(ctx))
+		var running, maxRunning int32
+		seen := make([]int32, 100)
+		err := ParallelFor(ctx, len(seen), func(i int) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			atomic.AddInt32(&seen[i], 1)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+		assert.Nil(t, err)
+		assert.LessOrEqual(t, int(maxRunning), workers)
+		for i := range seen {
+			assert.Equal(t, int32(1), seen[i])
+		}
+	}
+}
+
+func TestParallelForReturnsTheFirstError(t *testing.T) {
+	errAt := func(i int) error { return errors.New(string(rune('a' + i))) }
+	for _, workers := range []int{1, 8} {
+		ctx := WithParallelism(context.Background(), workers)
+		err := ParallelFor(ctx, 50, func(i int) error {
+			if i == 7 || i == 30 {
+				return errAt(i)
+			}
+			return nil
+		})
+		assert.Equal(t, errAt(7), err)
+	}
+}
+
+func TestParallelForStopsWhenCanceled(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		ctx, cancel := context.WithCancel(WithParallelism(context.Background(), workers))
+		var calls int32
+		err := ParallelFor(ctx, 1000, func(i int) error {
+			if atomic.AddInt32(&calls, 1) == 10 {
+				cancel()
+			}
+			return nil
+		})
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Less(t, int(calls), 1000)
+	}
+	assert.Nil(t, ParallelFor(Sequential(), 0, func(int) error { return errors.New("not called") }))
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrSecretBoxClosed is returned when a closed SecretBox is used.
+var ErrSecretBoxClosed = errors.New("secret box is closed")
+
+// Zeroizer is implemented by values holding secret material that can be
+// wiped in place.
+type Zeroizer interface {
+	Zeroize()
+}
+
+// SecretBox owns a secret value and wipes it when closed. The value is only
+// reachable through Use, so it cannot be read after Close.
+type SecretBox[T Zeroizer] struct {
+	lock   sync.Mutex
+	value  T
+	closed bool
+}
+
+func NewSecretBox[T Zeroizer](value T) *SecretBox[T] {
+	return &SecretBox[T]{value: value}
+}
+
+// Use calls f with the secret. f must not retain the value after it returns.
+func (b *SecretBox[T]) Use(f func(value T) error) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.closed {
+		return ErrSecretBoxClosed
+	}
+	return f(b.value)
+}
+
+// Close wipes the secret. Closing an already closed box is a no-op.
+func (b *SecretBox[T]) Close() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if !b.closed {
+		b.value.Zeroize()
+		var zero T
+		b.value = zero
+		b.closed = true
+	}
+	return nil
+}
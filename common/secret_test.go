@@ -0,0 +1,29 @@
+package common
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testSecret struct {
+	value []byte
+}
+
+func (s *testSecret) Zeroize() {
+	for i := range s.value {
+		s.value[i] = 0
+	}
+}
+
+func TestSecretBoxWipesOnClose(t *testing.T) {
+	secret := &testSecret{value: []byte{1, 2, 3}}
+	box := NewSecretBox(secret)
+	assert.Nil(t, box.Use(func(s *testSecret) error {
+		assert.Equal(t, []byte{1, 2, 3}, s.value)
+		return nil
+	}))
+	assert.Nil(t, box.Close())
+	assert.Equal(t, []byte{0, 0, 0}, secret.value)
+	assert.Equal(t, ErrSecretBoxClosed, box.Use(func(s *testSecret) error { return nil }))
+	assert.Nil(t, box.Close())
+}
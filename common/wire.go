@@ -0,0 +1,115 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+	"io"
+)
+
+// WireVersion is the version of the encoding of dealings and their parts
+// exchanged between nodes. Decoders reject any other version.
+const WireVersion = 1
+
+var (
+	wireEncMode, _ = cbor.CanonicalEncOptions().EncMode()
+	wireDecMode, _ = cbor.DecOptions{
+		DupMapKey:   cbor.DupMapKeyEnforcedAPF,
+		IndefLength: cbor.IndefLengthForbidden,
+	}.DecMode()
+)
+
+// MarshalCanonical encodes v as canonical CBOR, so equal values always
+// produce equal bytes.
+func MarshalCanonical(v interface{}) ([]byte, error) {
+	return wireEncMode.Marshal(v)
+}
+
+// UnmarshalStrict decodes exactly one CBOR item into v. Trailing bytes,
+// duplicate map keys and encodings that MarshalCanonical would not produce
+// are rejected.
+func UnmarshalStrict(data []byte, v interface{}) error {
+	dec := wireDecMode.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.NumBytesRead() != len(data) {
+		return errors.New("trailing bytes after cbor item")
+	}
+	canonical, err := MarshalCanonical(v)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(canonical, data) {
+		return errors.New("non canonical cbor encoding")
+	}
+	return nil
+}
+
+// UnmarshalJSONStrict decodes exactly one JSON value into v, rejecting
+// unknown fields and trailing data.
+func UnmarshalJSONStrict(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("trailing data after json value")
+	}
+	return nil
+}
+
+// The types exchanged between nodes convert to and from a versioned wire
+// struct W and derive their CBOR and JSON encodings from it with the
+// helpers below. fromWire validates the decoded struct; it is where the
+// version, curve and field checks live.
+
+// MarshalWire encodes the wire form of a value as canonical CBOR.
+func MarshalWire[W any](w *W, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return MarshalCanonical(w)
+}
+
+// UnmarshalWire strictly decodes CBOR into the wire form and hands it to
+// fromWire.
+func UnmarshalWire[W any](data []byte, fromWire func(w *W) error) error {
+	var w W
+	if err := UnmarshalStrict(data, &w); err != nil {
+		return err
+	}
+	return fromWire(&w)
+}
+
+// MarshalWireJSON encodes the wire form of a value as JSON.
+func MarshalWireJSON[W any](w *W, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(w)
+}
+
+// UnmarshalWireJSON strictly decodes JSON into the wire form and hands it
+// to fromWire.
+func UnmarshalWireJSON[W any](data []byte, fromWire func(w *W) error) error {
+	var w W
+	if err := UnmarshalJSONStrict(data, &w); err != nil {
+		return err
+	}
+	return fromWire(&w)
+}
+
+// DeserializeWire decodes a new T from the CBOR produced by its Serialize.
+func DeserializeWire[T any, P interface {
+	*T
+	UnmarshalCBOR([]byte) error
+}](data []byte) (*T, error) {
+	v := P(new(T))
+	if err := v.UnmarshalCBOR(data); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -69,6 +69,10 @@ func createProofAssocData(ad []byte, receiverIndex common.NodeIndex, dealerIndex
 }
 
 func (c IDkgComplaintInternal) Verify(dealing *dealings.IDkgDealingInternal, dealerIndex, complainerIndex common.NodeIndex, complainerKey *mega.MEGaPublicKey, ad []byte) error {
+	curveType := dealing.Commitment.CurveType()
+	if c.sharedSecret.CurveType() != curveType || c.proof.CurveType() != curveType || complainerKey.CurveType() != curveType {
+		return common.ErrCurveMismatch
+	}
 	proofAssocData, err := createProofAssocData(ad, complainerIndex, dealerIndex, complainerKey)
 	if err != nil {
 		return err
@@ -90,11 +94,29 @@ func (c IDkgComplaintInternal) Verify(dealing *dealings.IDkgDealingInternal, dea
 		}
 		commitOpening = poly2.PedersenCommitmentOpening([2]curve.EccScalar{opening[0], opening[1]})
 	} else {
-		return errors.New("unexpected commitment type")
+		return common.ErrUnexpectedCommitmentType
 	}
 
 	if dealing.Commitment.CheckOpening(complainerIndex, commitOpening) {
-		return errors.New("invalid complaint")
+		return common.ErrInvalidComplaint
 	}
 	return nil
 }
+
+// VerifyComplaints verifies the complaints of one complainer against the
+// dealings they were raised for. The result holds an entry for every
+// complaint: nil if the complaint is valid, so the dealing must be opened,
+// and the reason it was rejected otherwise.
+func VerifyComplaints(complaints *btree.Map[common.NodeIndex, *IDkgComplaintInternal], verifiedDealings *btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal], complainerIndex common.NodeIndex, complainerKey *mega.MEGaPublicKey, ad []byte) *btree.Map[common.NodeIndex, error] {
+	var results btree.Map[common.NodeIndex, error]
+	complaints.Scan(func(dealerIndex common.NodeIndex, complaint *IDkgComplaintInternal) bool {
+		dealing, ok := verifiedDealings.Get(dealerIndex)
+		if !ok {
+			results.Set(dealerIndex, errors.Wrap(common.ErrInvalidComplaint, "complaint against an unknown dealing"))
+			return true
+		}
+		results.Set(dealerIndex, complaint.Verify(dealing, dealerIndex, complainerIndex, complainerKey, ad))
+		return true
+	})
+	return &results
+}
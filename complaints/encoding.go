@@ -0,0 +1,164 @@
+package complaints
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/PlatONnetwork/tecdsa/zk"
+	"github.com/pkg/errors"
+)
+
+var (
+	Complaint = idkgComplaint{}
+)
+
+type idkgComplaint struct{}
+
+// idkgComplaintWire is the versioned encoding of a complaint: the shared
+// secret the complainer computed, tagged with its curve, and the proof that
+// it was computed with the complainer's key.
+type idkgComplaintWire struct {
+	Version      uint8
+	CurveType    curve.EccCurveType
+	Proof        *zk.ProofOfDLogEquivalence
+	SharedSecret []byte
+}
+
+func (c IDkgComplaintInternal) wire() (*idkgComplaintWire, error) {
+	return &idkgComplaintWire{
+		Version:      common.WireVersion,
+		CurveType:    c.sharedSecret.CurveType(),
+		Proof:        c.proof,
+		SharedSecret: c.sharedSecret.SerializeTagged(),
+	}, nil
+}
+
+func (c *IDkgComplaintInternal) fromWire(w *idkgComplaintWire) error {
+	if w.Version != common.WireVersion {
+		return errors.Wrapf(common.ErrInvalidEncoding, "unsupported complaint version %d", w.Version)
+	}
+	if _, ok := curve.Lookup(w.CurveType); !ok {
+		return common.ErrUnknownCurve
+	}
+	if w.Proof == nil || w.Proof.CurveType() != w.CurveType {
+		return errors.Wrap(common.ErrInvalidComplaint, "invalid complaint proof")
+	}
+	sharedSecret, err := curve.Point.DeserializeTagged(w.CurveType, w.SharedSecret)
+	if err != nil {
+		return err
+	}
+	c.proof, c.sharedSecret = w.Proof, sharedSecret
+	return nil
+}
+
+// Deserialize decodes a complaint produced by Serialize. Whether the
+// complaint is justified is only known after Verify.
+func (idkgComplaint) Deserialize(data []byte) (*IDkgComplaintInternal, error) {
+	return common.DeserializeWire[IDkgComplaintInternal](data)
+}
+
+func (c IDkgComplaintInternal) Serialize() ([]byte, error) {
+	return c.MarshalCBOR()
+}
+
+func (c IDkgComplaintInternal) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(c.wire())
+}
+
+func (c *IDkgComplaintInternal) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, c.fromWire)
+}
+
+func (c IDkgComplaintInternal) MarshalJSON() ([]byte, error) {
+	return common.MarshalWireJSON(c.wire())
+}
+
+func (c *IDkgComplaintInternal) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalWireJSON(data, c.fromWire)
+}
+
+var (
+	Opening = idkgOpening{}
+)
+
+type idkgOpening struct{}
+
+// idkgOpeningWire is the versioned encoding of an opening. Mask is only set
+// for openings of Pedersen commitments.
+type idkgOpeningWire struct {
+	Version        uint8
+	CurveType      curve.EccCurveType
+	CommitmentType poly.PolynomialCommitmentType
+	Value          []byte
+	Mask           []byte `cbor:",omitempty" json:",omitempty"`
+}
+
+func (o IDkgOpeningInternal) wire() (*idkgOpeningWire, error) {
+	w := &idkgOpeningWire{Version: common.WireVersion}
+	switch opening := o.Opening.(type) {
+	case poly.SimpleCommitmentOpening:
+		w.CurveType, w.CommitmentType = opening[0].CurveType(), poly.Simple
+		w.Value = opening[0].Serialize()
+	case poly.PedersenCommitmentOpening:
+		w.CurveType, w.CommitmentType = opening[0].CurveType(), poly.Pedersen
+		w.Value, w.Mask = opening[0].Serialize(), opening[1].Serialize()
+	default:
+		return nil, errors.Wrap(common.ErrInvalidOpening, "unexpected opening type")
+	}
+	return w, nil
+}
+
+func (o *IDkgOpeningInternal) fromWire(w *idkgOpeningWire) error {
+	if w.Version != common.WireVersion {
+		return errors.Wrapf(common.ErrInvalidEncoding, "unsupported opening version %d", w.Version)
+	}
+	if _, ok := curve.Lookup(w.CurveType); !ok {
+		return common.ErrUnknownCurve
+	}
+	value, err := curve.Scalar.DeserializeCanonical(w.CurveType, w.Value)
+	if err != nil {
+		return err
+	}
+	switch w.CommitmentType {
+	case poly.Simple:
+		if w.Mask != nil {
+			return errors.Wrap(common.ErrInvalidOpening, "unexpected mask")
+		}
+		o.Opening = poly.SimpleCommitmentOpening{value}
+	case poly.Pedersen:
+		mask, err := curve.Scalar.DeserializeCanonical(w.CurveType, w.Mask)
+		if err != nil {
+			return err
+		}
+		o.Opening = poly.PedersenCommitmentOpening{value, mask}
+	default:
+		return errors.Wrap(common.ErrUnexpectedCommitmentType, "unknown commitment type")
+	}
+	return nil
+}
+
+// Deserialize decodes an opening produced by Serialize. VerifyOpening must
+// be called before the opening is used.
+func (idkgOpening) Deserialize(data []byte) (*IDkgOpeningInternal, error) {
+	return common.DeserializeWire[IDkgOpeningInternal](data)
+}
+
+func (o IDkgOpeningInternal) Serialize() ([]byte, error) {
+	return o.MarshalCBOR()
+}
+
+func (o IDkgOpeningInternal) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(o.wire())
+}
+
+func (o *IDkgOpeningInternal) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, o.fromWire)
+}
+
+func (o IDkgOpeningInternal) MarshalJSON() ([]byte, error) {
+	return common.MarshalWireJSON(o.wire())
+}
+
+func (o *IDkgOpeningInternal) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalWireJSON(data, o.fromWire)
+}
@@ -0,0 +1,116 @@
+package complaints
+
+import (
+	"encoding/json"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/dealings"
+	"github.com/PlatONnetwork/tecdsa/mega"
+	seed2 "github.com/PlatONnetwork/tecdsa/seed"
+	"github.com/PlatONnetwork/tecdsa/testutils/wiretest"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/btree"
+	"testing"
+)
+
+func TestComplaintsSurviveSerialization(t *testing.T) {
+	ad := []byte("assoc_data_test")
+	for _, curveType := range []curve.EccCurveType{curve.K256, curve.P256, curve.ED25519} {
+		rng := genRng()
+		sk := mega.PrivateKey.GeneratePrivateKey(curveType, rng)
+		pk := sk.PublicKey()
+		complainerIndex := common.NodeIndex(1)
+		otherKey := mega.PrivateKey.GeneratePrivateKey(curveType, rng).PublicKey()
+		publicKeys := []*mega.MEGaPublicKey{otherKey, pk}
+
+		verified := new(btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal])
+		for dealerIndex := common.NodeIndex(0); dealerIndex < 3; dealerIndex++ {
+			dealing, err := dealings.NewIDkgDealingInternal(&dealings.RandomSecret{}, curveType, seed2.FromRng(rng), 1, publicKeys, dealerIndex, ad)
+			assert.Nil(t, err)
+			verified.Set(dealerIndex, dealing)
+		}
+		// dealers 0 and 2 sent the complainer garbage
+		for _, dealerIndex := range []common.NodeIndex{0, 2} {
+			dealing, _ := verified.Get(dealerIndex)
+			dealing.Ciphertext.(*mega.MEGaCiphertextPair).CTexts[complainerIndex][0] = curve.Scalar.Random(curveType, rng)
+		}
+
+		generated, err := GenerateComplaints(verified, ad, complainerIndex, sk, pk, seed2.FromRng(rng))
+		assert.Nil(t, err)
+		assert.Equal(t, []common.NodeIndex{0, 2}, generated.Keys())
+
+		received := new(btree.Map[common.NodeIndex, *IDkgComplaintInternal])
+		generated.Scan(func(dealerIndex common.NodeIndex, complaint *IDkgComplaintInternal) bool {
+			bytes, err := complaint.Serialize()
+			assert.Nil(t, err)
+			decoded, err := Complaint.Deserialize(bytes)
+			assert.Nil(t, err)
+			again, err := decoded.Serialize()
+			assert.Nil(t, err)
+			assert.Equal(t, bytes, again)
+
+			js, err := json.Marshal(complaint)
+			assert.Nil(t, err)
+			var fromJson IDkgComplaintInternal
+			assert.Nil(t, json.Unmarshal(js, &fromJson))
+			again, err = fromJson.Serialize()
+			assert.Nil(t, err)
+			assert.Equal(t, bytes, again)
+
+			received.Set(dealerIndex, decoded)
+			return true
+		})
+		// a spurious complaint against the honest dealer, and one against a
+		// dealing the verifier never saw
+		honest, _ := verified.Get(1)
+		spurious, err := NewComplaintInternal(seed2.FromRng(rng), honest, 1, complainerIndex, sk, pk, ad)
+		assert.Nil(t, err)
+		received.Set(1, spurious)
+		received.Set(5, spurious)
+
+		results := VerifyComplaints(received, verified, complainerIndex, pk, ad)
+		assert.Equal(t, 4, results.Len())
+		for _, dealerIndex := range []common.NodeIndex{0, 2} {
+			err, ok := results.Get(dealerIndex)
+			assert.True(t, ok)
+			assert.Nil(t, err)
+		}
+		for _, dealerIndex := range []common.NodeIndex{1, 5} {
+			err, ok := results.Get(dealerIndex)
+			assert.True(t, ok)
+			assert.ErrorIs(t, err, common.ErrInvalidComplaint)
+		}
+		// the complaint is bound to the complainer and the dealer
+		results = VerifyComplaints(received, verified, 0, otherKey, ad)
+		err, _ = results.Get(0)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestComplaintDeserializationIsStrict(t *testing.T) {
+	curveType := curve.K256
+	rng := genRng()
+	sk := mega.PrivateKey.GeneratePrivateKey(curveType, rng)
+	pk := sk.PublicKey()
+	dealing, err := dealings.NewIDkgDealingInternal(&dealings.RandomSecret{}, curveType, seed2.FromRng(rng), 1, []*mega.MEGaPublicKey{pk}, 0, nil)
+	assert.Nil(t, err)
+	complaint, err := NewComplaintInternal(seed2.FromRng(rng), dealing, 0, 0, sk, pk, nil)
+	assert.Nil(t, err)
+	bytes, err := complaint.Serialize()
+	assert.Nil(t, err)
+
+	_, err = Complaint.Deserialize(append(bytes, 0))
+	assert.NotNil(t, err)
+	_, err = Complaint.Deserialize(bytes[:len(bytes)-1])
+	assert.NotNil(t, err)
+
+	invalid := map[string]func(w *idkgComplaintWire){
+		"version":       func(w *idkgComplaintWire) { w.Version = 2 },
+		"curve":         func(w *idkgComplaintWire) { w.CurveType = curve.P256 },
+		"unknown curve": func(w *idkgComplaintWire) { w.CurveType = curve.EccCurveType(42) },
+		"no proof":      func(w *idkgComplaintWire) { w.Proof = nil },
+		"point tag":     func(w *idkgComplaintWire) { w.SharedSecret[0] = curve.P256.Tag() },
+		"no secret":     func(w *idkgComplaintWire) { w.SharedSecret = nil },
+	}
+	wiretest.AssertRejected(t, bytes, Complaint.Deserialize, invalid)
+}
@@ -0,0 +1,66 @@
+package complaints
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/dealings"
+	"github.com/PlatONnetwork/tecdsa/mega"
+	"github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/pkg/errors"
+)
+
+// IDkgOpeningInternal is the share of a dealing that an opener decrypted
+// for itself, published so that a complainer can reconstruct the share the
+// dealer failed to send it.
+type IDkgOpeningInternal struct {
+	Opening poly.CommitmentOpening
+}
+
+// CreateOpening opens the opener's share of a dealing in response to a
+// complaint by complainerIndex. The complaint must have been verified.
+func CreateOpening(dealing *dealings.IDkgDealingInternal, dealerIndex, complainerIndex, openerIndex common.NodeIndex, openerKey *mega.MEGaPrivateKey, ad []byte) (*IDkgOpeningInternal, error) {
+	if openerIndex == complainerIndex {
+		return nil, errors.Wrap(common.ErrInvalidOpening, "the complainer cannot open its own share")
+	}
+	opening, err := dealings.CommitmentOpening.OpenDealing(dealing, ad, dealerIndex, openerIndex, openerKey, openerKey.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	return &IDkgOpeningInternal{Opening: opening}, nil
+}
+
+// VerifyOpening checks that an opening received from openerIndex is the
+// opener's share of the dealing.
+func VerifyOpening(opening *IDkgOpeningInternal, dealing *dealings.IDkgDealingInternal, complainerIndex, openerIndex common.NodeIndex) error {
+	if openerIndex == complainerIndex {
+		return errors.Wrap(common.ErrInvalidOpening, "the complainer cannot open its own share")
+	}
+	if opening == nil || opening.Opening == nil {
+		return errors.Wrap(common.ErrInvalidOpening, "empty opening")
+	}
+	var err error
+	switch o := opening.Opening.(type) {
+	case poly.SimpleCommitmentOpening:
+		err = dealing.Commitment.VerifyIs(poly.Simple, o[0].CurveType())
+	case poly.PedersenCommitmentOpening:
+		err = dealing.Commitment.VerifyIs(poly.Pedersen, o[0].CurveType())
+		if err == nil && o[1].CurveType() != o[0].CurveType() {
+			err = common.ErrCurveMismatch
+		}
+	default:
+		err = errors.Wrap(common.ErrInvalidOpening, "unexpected opening type")
+	}
+	if err != nil {
+		return err
+	}
+	if !dealing.Commitment.CheckOpening(openerIndex, opening.Opening) {
+		return common.ErrInvalidOpening
+	}
+	return nil
+}
+
+// Zeroize wipes the opened share.
+func (o *IDkgOpeningInternal) Zeroize() {
+	if o.Opening != nil {
+		o.Opening.Zeroize()
+	}
+}
@@ -0,0 +1,111 @@
+package complaints
+
+import (
+	"encoding/json"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/dealings"
+	"github.com/PlatONnetwork/tecdsa/mega"
+	"github.com/PlatONnetwork/tecdsa/poly"
+	seed2 "github.com/PlatONnetwork/tecdsa/seed"
+	"github.com/PlatONnetwork/tecdsa/testutils/wiretest"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/btree"
+	"testing"
+)
+
+func TestComplaintOpeningReconstructionLoop(t *testing.T) {
+	ad := []byte("assoc_data_test")
+	threshold := 2
+	for _, shares := range []dealings.SecretShares{&dealings.RandomSecret{}, &dealings.ReshareOfUnmaskedSecret{S1: curve.Scalar.Random(curve.P256, genRng())}} {
+		curveType := curve.P256
+		rng := genRng()
+		privateKeys := make([]*mega.MEGaPrivateKey, 4, 4)
+		publicKeys := make([]*mega.MEGaPublicKey, 4, 4)
+		for i := range privateKeys {
+			privateKeys[i] = mega.PrivateKey.GeneratePrivateKey(curveType, rng)
+			publicKeys[i] = privateKeys[i].PublicKey()
+		}
+		dealerIndex, complainerIndex := common.NodeIndex(0), common.NodeIndex(3)
+		dealing, err := dealings.NewIDkgDealingInternal(shares, curveType, seed2.FromRng(rng), threshold, publicKeys, dealerIndex, ad)
+		assert.Nil(t, err)
+		switch c := dealing.Ciphertext.(type) {
+		case *mega.MEGaCiphertextSingle:
+			c.CTexts[complainerIndex] = curve.Scalar.Random(curveType, rng)
+		case *mega.MEGaCiphertextPair:
+			c.CTexts[complainerIndex][0] = curve.Scalar.Random(curveType, rng)
+		}
+		verified := new(btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal])
+		verified.Set(dealerIndex, dealing)
+
+		generated, err := GenerateComplaints(verified, ad, complainerIndex, privateKeys[complainerIndex], publicKeys[complainerIndex], seed2.FromRng(rng))
+		assert.Nil(t, err)
+		results := VerifyComplaints(generated, verified, complainerIndex, publicKeys[complainerIndex], ad)
+		valid, _ := results.Get(dealerIndex)
+		assert.Nil(t, valid)
+
+		_, err = CreateOpening(dealing, dealerIndex, complainerIndex, complainerIndex, privateKeys[complainerIndex], ad)
+		assert.ErrorIs(t, err, common.ErrInvalidOpening)
+		assert.ErrorIs(t, VerifyOpening(nil, dealing, complainerIndex, 0), common.ErrInvalidOpening)
+
+		openings := new(btree.Map[common.NodeIndex, poly.CommitmentOpening])
+		for opener := common.NodeIndex(0); opener < complainerIndex; opener++ {
+			opening, err := CreateOpening(dealing, dealerIndex, complainerIndex, opener, privateKeys[opener], ad)
+			assert.Nil(t, err)
+			bytes, err := opening.Serialize()
+			assert.Nil(t, err)
+			received, err := Opening.Deserialize(bytes)
+			assert.Nil(t, err)
+			assert.Nil(t, VerifyOpening(received, dealing, complainerIndex, opener))
+			assert.ErrorIs(t, VerifyOpening(received, dealing, complainerIndex, opener+1), common.ErrInvalidOpening)
+			assert.ErrorIs(t, VerifyOpening(received, dealing, opener, opener), common.ErrInvalidOpening)
+			openings.Set(opener, received.Opening)
+		}
+		share, err := dealings.ReconstructShareFromOpenings(dealing, openings, complainerIndex)
+		assert.Nil(t, err)
+		assert.True(t, dealing.Commitment.CheckOpening(complainerIndex, share))
+	}
+}
+
+func TestOpeningDeserializationIsStrict(t *testing.T) {
+	curveType := curve.K256
+	rng := genRng()
+	value, mask := curve.Scalar.Random(curveType, rng), curve.Scalar.Random(curveType, rng)
+	simple := &IDkgOpeningInternal{Opening: poly.SimpleCommitmentOpening{value}}
+	pedersen := &IDkgOpeningInternal{Opening: poly.PedersenCommitmentOpening{value, mask}}
+	for _, opening := range []*IDkgOpeningInternal{simple, pedersen} {
+		bytes, err := opening.Serialize()
+		assert.Nil(t, err)
+		decoded, err := Opening.Deserialize(bytes)
+		assert.Nil(t, err)
+		assert.IsType(t, opening.Opening, decoded.Opening)
+		js, err := json.Marshal(opening)
+		assert.Nil(t, err)
+		var fromJson IDkgOpeningInternal
+		assert.Nil(t, json.Unmarshal(js, &fromJson))
+		again, err := fromJson.Serialize()
+		assert.Nil(t, err)
+		assert.Equal(t, bytes, again)
+		_, err = Opening.Deserialize(append(bytes, 0))
+		assert.NotNil(t, err)
+	}
+
+	invalid := map[string]func(w *idkgOpeningWire){
+		"version":       func(w *idkgOpeningWire) { w.Version = 2 },
+		"unknown curve": func(w *idkgOpeningWire) { w.CurveType = curve.EccCurveType(42) },
+		"order":         func(w *idkgOpeningWire) { w.Value = curve.GroupOrder.Bytes() },
+		"short value":   func(w *idkgOpeningWire) { w.Value = w.Value[1:] },
+		"no mask":       func(w *idkgOpeningWire) { w.Mask = nil },
+		"unknown type":  func(w *idkgOpeningWire) { w.CommitmentType = 3 },
+		"simple masked": func(w *idkgOpeningWire) { w.CommitmentType = poly.Simple },
+	}
+	bytes, err := pedersen.Serialize()
+	assert.Nil(t, err)
+	wiretest.AssertRejected(t, bytes, Opening.Deserialize, invalid)
+
+	// an opening of the wrong commitment type is rejected, not a panic
+	publicKeys := []*mega.MEGaPublicKey{mega.PrivateKey.GeneratePrivateKey(curveType, rng).PublicKey()}
+	dealing, err := dealings.NewIDkgDealingInternal(&dealings.RandomSecret{}, curveType, seed2.FromRng(rng), 1, publicKeys, 0, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, VerifyOpening(simple, dealing, 1, 0))
+}
@@ -0,0 +1,116 @@
+package curve
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+)
+
+// nativeBacked is implemented by the point types built on kryptology's
+// projective points, whose coordinates can be normalized in batches.
+type nativeBacked interface {
+	EccPoint
+	nativePoint() *native.EllipticPoint
+	withNative(pt *native.EllipticPoint) EccPoint
+}
+
+// BatchToAffine returns copies of points normalized to z = 1, paying for a
+// single field inversion instead of one per point (Montgomery's trick).
+func (p point) BatchToAffine(points []EccPoint) []EccPoint {
+	natives, ok := nativePoints(points)
+	if !ok {
+		result := make([]EccPoint, len(points))
+		for i, pt := range points {
+			result[i] = pt.Clone()
+		}
+		return result
+	}
+	affine := batchNormalize(natives)
+	result := make([]EccPoint, len(points))
+	for i, pt := range points {
+		result[i] = pt.(nativeBacked).withNative(affine[i])
+	}
+	return result
+}
+
+// BatchSerialize returns the compressed encoding of every point, the same
+// as calling Serialize on each of them.
+func (p point) BatchSerialize(points []EccPoint) [][]byte {
+	result := make([][]byte, len(points))
+	natives, ok := nativePoints(points)
+	if !ok {
+		for i, pt := range points {
+			result[i] = pt.Serialize()
+		}
+		return result
+	}
+	for i, pt := range batchNormalize(natives) {
+		result[i] = encodeAffine(pt, true)
+	}
+	return result
+}
+
+func nativePoints(points []EccPoint) ([]*native.EllipticPoint, bool) {
+	if len(points) == 0 {
+		return nil, false
+	}
+	natives := make([]*native.EllipticPoint, len(points))
+	for i, pt := range points {
+		nb, ok := pt.(nativeBacked)
+		if !ok || pt.CurveType() != points[0].CurveType() {
+			return nil, false
+		}
+		natives[i] = nb.nativePoint()
+	}
+	return natives, true
+}
+
+// batchNormalize converts projective points to affine form. Points at
+// infinity come out with all coordinates zero, like ToAffine does.
+func batchNormalize(points []*native.EllipticPoint) []*native.EllipticPoint {
+	one := new(native.Field).Set(points[0].Z).SetOne()
+	zero := new(native.Field).Set(points[0].Z).SetZero()
+
+	// prefix[i] is the product of the (nonzero) z of points[0..i)
+	zs := make([]*native.Field, len(points))
+	prefix := make([]*native.Field, len(points))
+	acc := new(native.Field).Set(one)
+	for i, pt := range points {
+		zs[i] = new(native.Field).Set(one).CMove(pt.Z, one, pt.Z.IsZero())
+		prefix[i] = new(native.Field).Set(acc)
+		acc.Mul(acc, zs[i])
+	}
+	inv, _ := new(native.Field).Set(acc).Invert(acc)
+
+	result := make([]*native.EllipticPoint, len(points))
+	for i := len(points) - 1; i >= 0; i-- {
+		zInv := new(native.Field).Set(inv).Mul(inv, prefix[i])
+		inv.Mul(inv, zs[i])
+
+		pt := points[i]
+		identity := pt.Z.IsZero()
+		out := new(native.EllipticPoint).Set(pt)
+		out.X.Mul(pt.X, zInv)
+		out.Y.Mul(pt.Y, zInv)
+		out.X.CMove(out.X, zero, identity)
+		out.Y.CMove(out.Y, zero, identity)
+		out.Z.CMove(one, zero, identity)
+		result[i] = out
+	}
+	return result
+}
+
+// encodeAffine encodes a point whose z coordinate is either one or zero.
+func encodeAffine(pt *native.EllipticPoint, compress bool) []byte {
+	xb := pt.X.Bytes()
+	yb := pt.Y.Bytes()
+	x := common.ReverseBytes(xb[:])
+	y := common.ReverseBytes(yb[:])
+	encode := Encode.ConditionalSelect(Encode.FromAffineCoordinates(x, y, compress), Encode.Identity(), pt.Z.IsZero())
+	size := CompressedEvenY.MessageLen(Encode.toSize())
+	if !compress {
+		size = Uncompressed.MessageLen(Encode.toSize())
+	}
+	result := make([]byte, size, size)
+	copy(result[0:encode.Len()], encode.AsBytes())
+	return result
+}
@@ -0,0 +1,497 @@
+package curve
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/pkg/errors"
+)
+
+var (
+	Codec = codec{}
+
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+)
+
+const (
+	pemPublicKeyType  = "PUBLIC KEY"
+	pemPrivateKeyType = "EC PRIVATE KEY"
+)
+
+// codec converts public points to and from the standard interchange formats:
+// SEC1, X.509 SubjectPublicKeyInfo (DER and PEM), JWK, hex and base64, and
+// scalars to and from their SEC1 octet strings, hex, base64 and, as
+// private keys, RFC 5915 DER and PEM and JWK.
+// The identity cannot be represented as a public key and is rejected, as
+// are curves with their own point encoding such as ed25519.
+type codec struct{}
+
+type subjectPublicKeyInfo struct {
+	Algorithm algorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.ObjectIdentifier
+}
+
+// ecPrivateKey is the RFC 5915 ECPrivateKey structure.
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	D   string `json:"d,omitempty"`
+}
+
+func checkSEC1(curve EccCurveType) error {
+	c := lookup(curve)
+	if c == nil {
+		return common.ErrUnknownCurve
+	}
+	if c.PointBytes != 0 {
+		return errors.New("curve has no SEC1 encoding")
+	}
+	return nil
+}
+
+func curveOID(curve EccCurveType) (asn1.ObjectIdentifier, error) {
+	if c := lookup(curve); c != nil && len(c.OID) != 0 {
+		return c.OID, nil
+	}
+	return nil, errors.New("curve has no X.509 identifier")
+}
+
+func curveFromOID(oid asn1.ObjectIdentifier) (EccCurveType, error) {
+	for _, curve := range RegisteredCurves() {
+		if c := lookup(curve); c != nil && c.OID.Equal(oid) {
+			return curve, nil
+		}
+	}
+	return 0, errors.New("unsupported named curve")
+}
+
+func jwkCurveName(curve EccCurveType) (string, error) {
+	if c := lookup(curve); c != nil && c.JWKName != "" {
+		return c.JWKName, nil
+	}
+	return "", errors.New("curve has no JWK name")
+}
+
+func curveFromJwkName(name string) (EccCurveType, error) {
+	for _, curve := range RegisteredCurves() {
+		if c := lookup(curve); c != nil && c.JWKName != "" && c.JWKName == name {
+			return curve, nil
+		}
+	}
+	return 0, errors.New("unsupported JWK curve")
+}
+
+// ToSEC1 returns the SEC1 encoding of the point, 0x02/0x03 || x when
+// compressed and 0x04 || x || y otherwise.
+func (c codec) ToSEC1(pt EccPoint, compressed bool) ([]byte, error) {
+	if err := checkSEC1(pt.CurveType()); err != nil {
+		return nil, err
+	}
+	if pt.IsInfinity() {
+		return nil, errors.New("cannot encode the identity")
+	}
+	if compressed {
+		return pt.Serialize(), nil
+	}
+	return pt.SerializeUncompressed(), nil
+}
+
+// FromSEC1 decodes a compressed or uncompressed SEC1 point. Encodings that
+// are not canonical, not on the curve or the identity are rejected.
+func (c codec) FromSEC1(curve EccCurveType, data []byte) (EccPoint, error) {
+	if err := checkSEC1(curve); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("invalid point, bytes is empty")
+	}
+	tag := Tag(data[0])
+	if !tag.IsCompressed() && tag != Uncompressed {
+		return nil, errors.New("invalid point, unsupported SEC1 tag")
+	}
+	if len(data) != tag.MessageLen(curve.FieldBytes()) {
+		return nil, errors.New("invalid point, unexpected length")
+	}
+	pt, err := Point.DeserializeAnyFormat(curve, data)
+	if err != nil {
+		return nil, err
+	}
+	if pt == nil || pt.IsInfinity() {
+		return nil, errors.New("invalid point")
+	}
+	encoded, err := c.ToSEC1(pt, tag.IsCompressed())
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(encoded, data) {
+		return nil, errors.New("invalid point, non canonical encoding")
+	}
+	return pt, nil
+}
+
+// ToDER returns the X.509 SubjectPublicKeyInfo of the point, with the
+// uncompressed SEC1 encoding as required by RFC 5480.
+func (c codec) ToDER(pt EccPoint) ([]byte, error) {
+	oid, err := curveOID(pt.CurveType())
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := c.ToSEC1(pt, false)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: algorithmIdentifier{
+			Algorithm:  oidPublicKeyECDSA,
+			Parameters: oid,
+		},
+		PublicKey: asn1.BitString{Bytes: encoded, BitLength: 8 * len(encoded)},
+	})
+}
+
+// FromDER parses an X.509 SubjectPublicKeyInfo. The curve is taken from
+// the named curve parameter.
+func (c codec) FromDER(der []byte) (EccPoint, error) {
+	var spki subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid SubjectPublicKeyInfo")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data after SubjectPublicKeyInfo")
+	}
+	if !spki.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, errors.New("not an elliptic curve public key")
+	}
+	curve, err := curveFromOID(spki.Algorithm.Parameters)
+	if err != nil {
+		return nil, err
+	}
+	if spki.PublicKey.BitLength != 8*len(spki.PublicKey.Bytes) {
+		return nil, errors.New("invalid public key bit string")
+	}
+	return c.FromSEC1(curve, spki.PublicKey.Bytes)
+}
+
+// ToPEM returns the SubjectPublicKeyInfo as a "PUBLIC KEY" PEM block.
+func (c codec) ToPEM(pt EccPoint) ([]byte, error) {
+	der, err := c.ToDER(pt)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemPublicKeyType, Bytes: der}), nil
+}
+
+// FromPEM parses the first "PUBLIC KEY" PEM block.
+func (c codec) FromPEM(data []byte) (EccPoint, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if block.Type != pemPublicKeyType {
+		return nil, errors.New("unexpected PEM block type " + block.Type)
+	}
+	return c.FromDER(block.Bytes)
+}
+
+// ToJWK returns the RFC 7517 JSON Web Key of the point.
+func (c codec) ToJWK(pt EccPoint) ([]byte, error) {
+	name, err := jwkCurveName(pt.CurveType())
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := c.ToSEC1(pt, false)
+	if err != nil {
+		return nil, err
+	}
+	size := pt.CurveType().FieldBytes()
+	return json.Marshal(jsonWebKey{
+		Kty: "EC",
+		Crv: name,
+		X:   base64.RawURLEncoding.EncodeToString(encoded[1 : 1+size]),
+		Y:   base64.RawURLEncoding.EncodeToString(encoded[1+size:]),
+	})
+}
+
+// FromJWK parses an EC JSON Web Key. Private key members are ignored.
+func (c codec) FromJWK(data []byte) (EccPoint, error) {
+	var jwk jsonWebKey
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, errors.Wrap(err, "invalid JWK")
+	}
+	if jwk.Kty != "EC" {
+		return nil, errors.New("JWK is not an EC key")
+	}
+	curve, err := curveFromJwkName(jwk.Crv)
+	if err != nil {
+		return nil, err
+	}
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid JWK x coordinate")
+	}
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid JWK y coordinate")
+	}
+	size := curve.FieldBytes()
+	if len(x) != size || len(y) != size {
+		return nil, errors.New("invalid JWK coordinate length")
+	}
+	encoded := make([]byte, 0, 1+2*size)
+	encoded = append(encoded, byte(Uncompressed))
+	encoded = append(encoded, x...)
+	encoded = append(encoded, y...)
+	return c.FromSEC1(curve, encoded)
+}
+
+// ToHex returns the hex of the compressed SEC1 encoding.
+func (c codec) ToHex(pt EccPoint) (string, error) {
+	encoded, err := c.ToSEC1(pt, true)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(encoded), nil
+}
+
+// FromHex decodes hex of a compressed or uncompressed SEC1 encoding.
+func (c codec) FromHex(curve EccCurveType, s string) (EccPoint, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return c.FromSEC1(curve, data)
+}
+
+// ToBase64 returns the standard base64 of the compressed SEC1 encoding.
+func (c codec) ToBase64(pt EccPoint) (string, error) {
+	encoded, err := c.ToSEC1(pt, true)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encoded), nil
+}
+
+// FromBase64 decodes standard base64 of a compressed or uncompressed SEC1
+// encoding.
+func (c codec) FromBase64(curve EccCurveType, s string) (EccPoint, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return c.FromSEC1(curve, data)
+}
+
+// ScalarToSEC1 returns the SEC1 octet string of the scalar, its big endian
+// encoding padded to the size of the group order.
+func (c codec) ScalarToSEC1(s EccScalar) ([]byte, error) {
+	if err := checkSEC1(s.CurveType()); err != nil {
+		return nil, err
+	}
+	return s.Serialize(), nil
+}
+
+// ScalarFromSEC1 decodes a SEC1 octet string, which must have exactly the
+// size of the group order and encode an integer below it.
+func (c codec) ScalarFromSEC1(curve EccCurveType, data []byte) (EccScalar, error) {
+	if err := checkSEC1(curve); err != nil {
+		return nil, err
+	}
+	return Scalar.DeserializeCanonical(curve, data)
+}
+
+// ScalarToHex returns the hex of the SEC1 octet string.
+func (c codec) ScalarToHex(s EccScalar) (string, error) {
+	encoded, err := c.ScalarToSEC1(s)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(encoded), nil
+}
+
+func (c codec) ScalarFromHex(curve EccCurveType, s string) (EccScalar, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return c.ScalarFromSEC1(curve, data)
+}
+
+// ScalarToBase64 returns the standard base64 of the SEC1 octet string.
+func (c codec) ScalarToBase64(s EccScalar) (string, error) {
+	encoded, err := c.ScalarToSEC1(s)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encoded), nil
+}
+
+func (c codec) ScalarFromBase64(curve EccCurveType, s string) (EccScalar, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return c.ScalarFromSEC1(curve, data)
+}
+
+func checkPrivateKey(s EccScalar) error {
+	if s.IsZero() == 1 {
+		return errors.New("the zero scalar is not a private key")
+	}
+	return nil
+}
+
+// PrivateKeyToDER returns the RFC 5915 ECPrivateKey of the scalar, with the
+// named curve and the public key.
+func (c codec) PrivateKeyToDER(s EccScalar) ([]byte, error) {
+	if err := checkPrivateKey(s); err != nil {
+		return nil, err
+	}
+	oid, err := curveOID(s.CurveType())
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := c.ScalarToSEC1(s)
+	if err != nil {
+		return nil, err
+	}
+	public, err := c.ToSEC1(Point.MulByG(s), false)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(ecPrivateKey{
+		Version:       1,
+		PrivateKey:    encoded,
+		NamedCurveOID: oid,
+		PublicKey:     asn1.BitString{Bytes: public, BitLength: 8 * len(public)},
+	})
+}
+
+// PrivateKeyFromDER parses an RFC 5915 ECPrivateKey. The named curve is
+// required, and the public key must match when present.
+func (c codec) PrivateKeyFromDER(der []byte) (EccScalar, error) {
+	var key ecPrivateKey
+	rest, err := asn1.Unmarshal(der, &key)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ECPrivateKey")
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("trailing data after ECPrivateKey")
+	}
+	if key.Version != 1 {
+		return nil, errors.New("unsupported ECPrivateKey version")
+	}
+	if len(key.NamedCurveOID) == 0 {
+		return nil, errors.New("ECPrivateKey without named curve")
+	}
+	curve, err := curveFromOID(key.NamedCurveOID)
+	if err != nil {
+		return nil, err
+	}
+	s, err := c.ScalarFromSEC1(curve, key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(s); err != nil {
+		return nil, err
+	}
+	if len(key.PublicKey.Bytes) != 0 {
+		public, err := c.FromSEC1(curve, key.PublicKey.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		if public.Equal(Point.MulByG(s)) != 1 {
+			return nil, errors.New("public key does not match the private key")
+		}
+	}
+	return s, nil
+}
+
+// PrivateKeyToPEM returns the ECPrivateKey as an "EC PRIVATE KEY" PEM block.
+func (c codec) PrivateKeyToPEM(s EccScalar) ([]byte, error) {
+	der, err := c.PrivateKeyToDER(s)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemPrivateKeyType, Bytes: der}), nil
+}
+
+// PrivateKeyFromPEM parses the first "EC PRIVATE KEY" PEM block.
+func (c codec) PrivateKeyFromPEM(data []byte) (EccScalar, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	if block.Type != pemPrivateKeyType {
+		return nil, errors.New("unexpected PEM block type " + block.Type)
+	}
+	return c.PrivateKeyFromDER(block.Bytes)
+}
+
+// PrivateKeyToJWK returns the JSON Web Key of the scalar, the JWK of its
+// public key with the private member d.
+func (c codec) PrivateKeyToJWK(s EccScalar) ([]byte, error) {
+	if err := checkPrivateKey(s); err != nil {
+		return nil, err
+	}
+	encoded, err := c.ScalarToSEC1(s)
+	if err != nil {
+		return nil, err
+	}
+	public, err := c.ToJWK(Point.MulByG(s))
+	if err != nil {
+		return nil, err
+	}
+	var jwk jsonWebKey
+	if err := json.Unmarshal(public, &jwk); err != nil {
+		return nil, err
+	}
+	jwk.D = base64.RawURLEncoding.EncodeToString(encoded)
+	return json.Marshal(jwk)
+}
+
+// PrivateKeyFromJWK parses an EC JSON Web Key with a private member d,
+// which must match the public coordinates.
+func (c codec) PrivateKeyFromJWK(data []byte) (EccScalar, error) {
+	public, err := c.FromJWK(data)
+	if err != nil {
+		return nil, err
+	}
+	var jwk jsonWebKey
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, errors.Wrap(err, "invalid JWK")
+	}
+	d, err := base64.RawURLEncoding.DecodeString(jwk.D)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid JWK private key")
+	}
+	s, err := c.ScalarFromSEC1(public.CurveType(), d)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPrivateKey(s); err != nil {
+		return nil, err
+	}
+	if public.Equal(Point.MulByG(s)) != 1 {
+		return nil, errors.New("public key does not match the private key")
+	}
+	return s, nil
+}
@@ -0,0 +1,93 @@
+package curve
+
+import (
+	"github.com/PlatONnetwork/tecdsa/seed"
+	"sync"
+)
+
+var (
+	ed25519GeneratorHOnce sync.Once
+	ed25519GeneratorH     *Edwards25519Point
+)
+
+func init() {
+	mustRegister(CurveDescriptor{
+		Type:          ED25519,
+		Name:          "ed25519",
+		ScalarBits:    253,
+		FieldBits:     255,
+		SecurityLevel: 128,
+		PointBytes:    32,
+
+		Identity:   func() EccPoint { return Ed25519Point.Identity() },
+		GeneratorG: func() EccPoint { return Ed25519Point.NewEd25519G() },
+		// H is hashed to the curve on first use, like the constants of the
+		// other curves were derived.
+		GeneratorH: func() EccPoint {
+			ed25519GeneratorHOnce.Do(func() {
+				pt, err := HashToCurveRo(ED25519, []byte("h"), []byte("ic-crypto-tecdsa-ed25519-generator-h"))
+				if err != nil {
+					panic(err.Error())
+				}
+				ed25519GeneratorH = pt.(*Edwards25519Point)
+			})
+			return ed25519GeneratorH.Clone()
+		},
+		DeserializePoint: func(bytes []byte) (EccPoint, error) {
+			pt, err := Ed25519Point.Deserialize(bytes)
+			if err != nil {
+				return nil, err
+			}
+			return pt, nil
+		},
+		MulPoints: func(pt1 EccPoint, scalar1 EccScalar, pt2 EccPoint, scalar2 EccScalar) EccPoint {
+			return Ed25519Point.Identity().LinComb(pt1, scalar1, pt2, scalar2)
+		},
+
+		ScalarZero:       func() EccScalar { return Ed25519Scalar.Zero() },
+		ScalarOne:        func() EccScalar { return Ed25519Scalar.One() },
+		ScalarFromUint64: func(n uint64) EccScalar { return Ed25519Scalar.FromUint64(n) },
+		DeserializeScalar: func(bytes []byte) (EccScalar, error) {
+			s, err := Ed25519Scalar.Deserialize(bytes)
+			if err != nil {
+				return nil, err
+			}
+			return s, nil
+		},
+		ScalarFromBytesWide: func(bytes []byte) EccScalar { return Ed25519Scalar.FromWideBytes(bytes) },
+		ScalarBytes: func(scalar EccScalar) EccScalarBytes {
+			var bytes Ed25519ScalarBytes
+			copy(bytes[:], scalar.Serialize())
+			return bytes
+		},
+
+		FieldZero: func() EccFieldElement { return Ed25519Field.Zero() },
+		FieldOne:  func() EccFieldElement { return Ed25519Field.One() },
+		FieldA:    func() EccFieldElement { return Ed25519Field.FieldA() },
+		FieldB:    func() EccFieldElement { return Ed25519Field.FieldB() },
+		FieldFromBytes: func(bytes []byte) (EccFieldElement, error) {
+			fe, err := Ed25519Field.FromBytes(bytes)
+			if err != nil {
+				return nil, err
+			}
+			return fe, nil
+		},
+		FieldFromBytesWide: func(bytes []byte) EccFieldElement { return Ed25519Field.FromBytesWide(bytes) },
+
+		ExpandMessage: seed.ExpandMessageXmdSha512,
+		MapToCurve:    mapToCurveEdwards25519,
+	})
+}
+
+// mapToCurveEdwards25519 is Elligator 2 followed by the rational map to
+// edwards25519 and clearing the cofactor, so the result is always in the
+// prime order subgroup.
+func mapToCurveEdwards25519(u EccFieldElement) (EccPoint, error) {
+	x, y := curve25519ToEdwards25519(elligator2Curve25519(u))
+	pt, err := Ed25519Point.fromAffine(x.(*Edwards25519Field), y.(*Edwards25519Field))
+	if err != nil {
+		return nil, err
+	}
+	pt.point.MultByCofactor(pt.point)
+	return pt, nil
+}
@@ -0,0 +1,178 @@
+package curve
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	edfield "filippo.io/edwards25519/field"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+var (
+	Ed25519Field = EccEd25519Field{}
+)
+
+type EccEd25519Field struct{}
+
+// Edwards25519Field is an element of GF(2^255 - 19). Like the other
+// fields it is serialized big endian.
+type Edwards25519Field struct {
+	field *edfield.Element
+}
+
+func (e EccEd25519Field) FromHex(h string) *Edwards25519Field {
+	bytes, err := hex.DecodeString(h)
+	if err != nil {
+		panic(err.Error())
+	}
+	fe, err := e.FromBytes(bytes)
+	if err != nil {
+		panic(err.Error())
+	}
+	return fe
+}
+
+// FromBytes decodes a canonical big endian field element.
+func (EccEd25519Field) FromBytes(bytes []byte) (*Edwards25519Field, error) {
+	if len(bytes) != 32 {
+		return nil, errors.New("invalid field element length")
+	}
+	le := common.ReverseBytes(bytes)
+	fe, err := new(edfield.Element).SetBytes(le)
+	if err != nil {
+		return nil, err
+	}
+	if le[31]&0x80 != 0 || subtle.ConstantTimeCompare(fe.Bytes(), le) != 1 {
+		return nil, errors.New("invalid field element, not canonical")
+	}
+	return &Edwards25519Field{field: fe}, nil
+}
+
+// FromBytesWide reduces a big endian integer of up to 64 bytes. It is
+// processed in 31 byte limbs, which are below 2^248 and so always decode.
+func (EccEd25519Field) FromBytesWide(bytes []byte) *Edwards25519Field {
+	const limbBytes = 31
+	var shift [32]byte
+	shift[limbBytes] = 1
+	radix, _ := new(edfield.Element).SetBytes(shift[:])
+	acc := new(edfield.Element).Zero()
+	first := len(bytes) % limbBytes
+	if first == 0 {
+		first = limbBytes
+	}
+	for start, end := 0, first; start < len(bytes); start, end = end, end+limbBytes {
+		var buf [32]byte
+		copy(buf[:], common.ReverseBytes(bytes[start:end]))
+		limb, _ := new(edfield.Element).SetBytes(buf[:])
+		acc.Multiply(acc, radix)
+		acc.Add(acc, limb)
+	}
+	return &Edwards25519Field{field: acc}
+}
+
+func (EccEd25519Field) Zero() *Edwards25519Field {
+	return &Edwards25519Field{field: new(edfield.Element).Zero()}
+}
+
+func (EccEd25519Field) One() *Edwards25519Field {
+	return &Edwards25519Field{field: new(edfield.Element).One()}
+}
+
+// FieldA is the a = -1 of the twisted Edwards equation.
+func (e EccEd25519Field) FieldA() *Edwards25519Field {
+	return &Edwards25519Field{field: new(edfield.Element).Negate(new(edfield.Element).One())}
+}
+
+// FieldB is the d of the twisted Edwards equation.
+func (e EccEd25519Field) FieldB() *Edwards25519Field {
+	return e.FromHex("52036cee2b6ffe738cc740797779e89800700a4d4141d8ab75eb4dca135978a3")
+}
+
+func (s Edwards25519Field) CurveType() EccCurveType {
+	return ED25519
+}
+
+func (s *Edwards25519Field) Clone() EccFieldElement {
+	return Ed25519Field.Zero().Assign(s)
+}
+
+func (s *Edwards25519Field) Assign(other EccFieldElement) EccFieldElement {
+	s.field.Set(other.(*Edwards25519Field).field)
+	return s
+}
+
+func (s *Edwards25519Field) Add(lhs, rhs EccFieldElement) EccFieldElement {
+	l := lhs.(*Edwards25519Field)
+	r := rhs.(*Edwards25519Field)
+	s.field.Add(l.field, r.field)
+	return s
+}
+
+func (s *Edwards25519Field) Sub(lhs, rhs EccFieldElement) EccFieldElement {
+	l := lhs.(*Edwards25519Field)
+	r := rhs.(*Edwards25519Field)
+	s.field.Subtract(l.field, r.field)
+	return s
+}
+
+func (s *Edwards25519Field) Mul(lhs, rhs EccFieldElement) EccFieldElement {
+	l := lhs.(*Edwards25519Field)
+	r := rhs.(*Edwards25519Field)
+	s.field.Multiply(l.field, r.field)
+	return s
+}
+
+func (s *Edwards25519Field) Square(other EccFieldElement) EccFieldElement {
+	s.field.Square(other.(*Edwards25519Field).field)
+	return s
+}
+
+func (s Edwards25519Field) Equal(other EccFieldElement) int {
+	return s.field.Equal(other.(*Edwards25519Field).field)
+}
+
+func (s *Edwards25519Field) CAssign(other EccFieldElement, choice int) {
+	s.field.Select(other.(*Edwards25519Field).field, s.field, choice)
+}
+
+func (s *Edwards25519Field) Invert(other EccFieldElement) EccFieldElement {
+	s.field.Invert(other.(*Edwards25519Field).field)
+	return s
+}
+
+func (s *Edwards25519Field) Negate(other EccFieldElement) EccFieldElement {
+	s.field.Negate(other.(*Edwards25519Field).field)
+	return s
+}
+
+// Sqrt sets s to the non negative square root of other. When other is not
+// a square s is set to zero and the returned choice is 0.
+func (s *Edwards25519Field) Sqrt(other EccFieldElement) (EccFieldElement, int) {
+	_, wasSquare := s.field.SqrtRatio(other.(*Edwards25519Field).field, new(edfield.Element).One())
+	s.field.Select(s.field, new(edfield.Element).Zero(), wasSquare)
+	return s, wasSquare
+}
+
+// Progenitor computes other^((p-5)/8), the exponent used by square roots in
+// fields with p = 5 mod 8.
+func (s *Edwards25519Field) Progenitor(other EccFieldElement) EccFieldElement {
+	s.field.Pow22523(other.(*Edwards25519Field).field)
+	return s
+}
+
+func (s Edwards25519Field) IsZero() int {
+	return s.field.Equal(new(edfield.Element).Zero())
+}
+
+func (s Edwards25519Field) AsBytes() []byte {
+	return common.ReverseBytes(s.field.Bytes())
+}
+
+func (s Edwards25519Field) Sign() uint8 {
+	return uint8(s.field.IsNegative())
+}
+
+func (s Edwards25519Field) BigInt() *big.Int {
+	return new(big.Int).SetBytes(s.AsBytes())
+}
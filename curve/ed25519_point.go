@@ -0,0 +1,239 @@
+package curve
+
+import (
+	"crypto/subtle"
+	"filippo.io/edwards25519"
+	edfield "filippo.io/edwards25519/field"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+	"math/bits"
+)
+
+var (
+	Ed25519Point = EccEd25519Point{}
+)
+
+type EccEd25519Point struct{}
+
+// Edwards25519Point is an element of the prime order subgroup of
+// edwards25519. Its compressed form is the 32 byte RFC 8032 encoding, its
+// uncompressed form 0x04 || x || y with big endian affine coordinates.
+type Edwards25519Point struct {
+	point *edwards25519.Point
+}
+
+func (EccEd25519Point) NewEd25519G() *Edwards25519Point {
+	return &Edwards25519Point{point: edwards25519.NewGeneratorPoint()}
+}
+
+func (EccEd25519Point) Identity() *Edwards25519Point {
+	return &Edwards25519Point{point: edwards25519.NewIdentityPoint()}
+}
+
+// Deserialize decodes the compressed or uncompressed form. Non canonical
+// encodings and points outside the prime order subgroup are rejected.
+func (e EccEd25519Point) Deserialize(bytes []byte) (*Edwards25519Point, error) {
+	var pt *Edwards25519Point
+	switch {
+	case len(bytes) == 32:
+		value, err := new(edwards25519.Point).SetBytes(bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid point")
+		}
+		pt = &Edwards25519Point{point: value}
+		if subtle.ConstantTimeCompare(pt.Serialize(), bytes) != 1 {
+			return nil, errors.New("invalid point, non canonical encoding")
+		}
+	case len(bytes) == 65 && bytes[0] == byte(Uncompressed):
+		x, err := Ed25519Field.FromBytes(bytes[1:33])
+		if err != nil {
+			return nil, err
+		}
+		y, err := Ed25519Field.FromBytes(bytes[33:])
+		if err != nil {
+			return nil, err
+		}
+		if pt, err = e.fromAffine(x, y); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("invalid point, unexpected length")
+	}
+	if !pt.isTorsionFree() {
+		return nil, errors.New("invalid point, not in the prime order subgroup")
+	}
+	return pt, nil
+}
+
+// fromAffine builds a point of the full curve, possibly with a small order
+// component.
+func (EccEd25519Point) fromAffine(x, y *Edwards25519Field) (*Edwards25519Point, error) {
+	t := new(edfield.Element).Multiply(x.field, y.field)
+	value, err := new(edwards25519.Point).SetExtendedCoordinates(x.field, y.field, new(edfield.Element).One(), t)
+	if err != nil {
+		return nil, errors.New("invalid point, not on the curve")
+	}
+	return &Edwards25519Point{point: value}, nil
+}
+
+// isTorsionFree reports whether l * p is the identity, computed as
+// (l - 1) * p + p since scalars are reduced modulo l.
+func (s Edwards25519Point) isTorsionFree() bool {
+	minusOne := edwards25519.NewScalar().Negate(Ed25519Scalar.One().scalar)
+	q := new(edwards25519.Point).ScalarMult(minusOne, s.point)
+	q.Add(q, s.point)
+	return q.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+func (s Edwards25519Point) CurveType() EccCurveType {
+	return ED25519
+}
+
+func (s *Edwards25519Point) AddPoints(lhs, rhs EccPoint) EccPoint {
+	l := lhs.(*Edwards25519Point)
+	r := rhs.(*Edwards25519Point)
+	s.point.Add(l.point, r.point)
+	return s
+}
+
+func (s *Edwards25519Point) SubPoints(lhs, rhs EccPoint) EccPoint {
+	l := lhs.(*Edwards25519Point)
+	r := rhs.(*Edwards25519Point)
+	s.point.Subtract(l.point, r.point)
+	return s
+}
+
+func (s *Edwards25519Point) Plus(other EccPoint) EccPoint {
+	return s.Clone().AddPoints(s, other)
+}
+
+func (s *Edwards25519Point) Minus(other EccPoint) EccPoint {
+	return s.Clone().SubPoints(s, other)
+}
+
+func (s *Edwards25519Point) Times(scalar EccScalar) EccPoint {
+	return s.Clone().ScalarMul(s, scalar)
+}
+
+func (s *Edwards25519Point) Doubled() EccPoint {
+	return s.Clone().Double(s)
+}
+
+func (s *Edwards25519Point) Double(other EccPoint) EccPoint {
+	o := other.(*Edwards25519Point)
+	s.point.Add(o.point, o.point)
+	return s
+}
+
+func (s *Edwards25519Point) CAssign(other EccPoint, choice int) {
+	o := other.(*Edwards25519Point)
+	x, y, z, t := s.point.ExtendedCoordinates()
+	ox, oy, oz, ot := o.point.ExtendedCoordinates()
+	x.Select(ox, x, choice)
+	y.Select(oy, y, choice)
+	z.Select(oz, z, choice)
+	t.Select(ot, t, choice)
+	if _, err := s.point.SetExtendedCoordinates(x, y, z, t); err != nil {
+		panic(err.Error())
+	}
+}
+
+func (s Edwards25519Point) Clone() EccPoint {
+	return &Edwards25519Point{
+		point: new(edwards25519.Point).Set(s.point),
+	}
+}
+
+func (s *Edwards25519Point) ScalarMul(other EccPoint, scalar EccScalar) EccPoint {
+	o := other.(*Edwards25519Point)
+	f := scalar.(*Edwards25519Scalar)
+	s.point.ScalarMult(f.scalar, o.point)
+	return s
+}
+
+func (s *Edwards25519Point) MulByNodeIndex(scalar common.NodeIndex) EccPoint {
+	s64 := uint64(scalar + 1)
+	bits := 64 - bits.LeadingZeros64(uint64(s64))
+	res := EccPoint(Ed25519Point.Identity())
+	for b := 0; b < bits; b++ {
+		res = res.Double(res)
+		if (s64 >> (bits - 1 - b) & 1) == 1 {
+			res = res.AddPoints(res, s)
+		}
+	}
+	return res
+}
+
+func (s *Edwards25519Point) LinComb(pt1 EccPoint, scalar1 EccScalar, pt2 EccPoint, scalar2 EccScalar) EccPoint {
+	s.point.MultiScalarMult(
+		[]*edwards25519.Scalar{scalar1.(*Edwards25519Scalar).scalar, scalar2.(*Edwards25519Scalar).scalar},
+		[]*edwards25519.Point{pt1.(*Edwards25519Point).point, pt2.(*Edwards25519Point).point})
+	return s
+}
+
+func (s Edwards25519Point) Serialize() []byte {
+	return s.point.Bytes()
+}
+
+func (s Edwards25519Point) Equal(eccPoint EccPoint) int {
+	return s.point.Equal(eccPoint.(*Edwards25519Point).point)
+}
+
+func (s *Edwards25519Point) Assign(eccPoint EccPoint) EccPoint {
+	s.point.Set(eccPoint.(*Edwards25519Point).point)
+	return s
+}
+
+func (s Edwards25519Point) SerializeTagged() []byte {
+	bytes := make([]byte, 0, 1+s.CurveType().PointBytes())
+	bytes = append(bytes, s.CurveType().Tag())
+	return append(bytes, s.Serialize()...)
+}
+
+func (s Edwards25519Point) SerializeUncompressed() []byte {
+	bytes := make([]byte, 0, 65)
+	bytes = append(bytes, byte(Uncompressed))
+	bytes = append(bytes, s.AffineX().AsBytes()...)
+	return append(bytes, s.AffineY().AsBytes()...)
+}
+
+func (s Edwards25519Point) affine() (x, y *edfield.Element) {
+	x, y, z, _ := s.point.ExtendedCoordinates()
+	zInv := new(edfield.Element).Invert(z)
+	return x.Multiply(x, zInv), y.Multiply(y, zInv)
+}
+
+func (s Edwards25519Point) AffineX() EccFieldElement {
+	x, _ := s.affine()
+	return &Edwards25519Field{field: x}
+}
+
+func (s Edwards25519Point) AffineY() EccFieldElement {
+	_, y := s.affine()
+	return &Edwards25519Field{field: y}
+}
+
+func (s Edwards25519Point) IsInfinity() bool {
+	return s.point.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+func (s Edwards25519Point) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(s.SerializeTagged())
+}
+
+func (s *Edwards25519Point) UnmarshalCBOR(data []byte) error {
+	var bytes []byte
+	if err := cbor.Unmarshal(data, &bytes); err != nil {
+		return err
+	}
+	if len(bytes) == 0 {
+		return errors.New("invalid point")
+	}
+	tmp, err := Ed25519Point.Deserialize(bytes[1:])
+	if err != nil {
+		return err
+	}
+	s.point = tmp.point
+	return nil
+}
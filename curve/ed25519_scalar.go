@@ -0,0 +1,199 @@
+package curve
+
+import (
+	"filippo.io/edwards25519"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+var (
+	Ed25519Scalar     = EccEd25519Scalar{}
+	Ed25519GroupOrder = fromHex("1000000000000000000000000000000014def9dea2f79cd65812631a5cf5d3ed")
+	Ed25519OrderHalf  = new(big.Int).Div(Ed25519GroupOrder, big.NewInt(2))
+	// floor(n / 2), little endian like edwards25519.Scalar.Bytes
+	ed25519OrderHalfLE = common.ReverseBytes(Ed25519OrderHalf.FillBytes(make([]byte, 32)))
+)
+
+type EccEd25519Scalar struct{}
+
+// Edwards25519Scalar is an integer modulo the order of the prime order
+// subgroup. It is serialized big endian like the other scalars, which is
+// the reverse of the RFC 8032 encoding.
+type Edwards25519Scalar struct {
+	scalar *edwards25519.Scalar
+}
+
+func (EccEd25519Scalar) Deserialize(bytes []byte) (*Edwards25519Scalar, error) {
+	if len(bytes) != 32 {
+		return nil, errors.New("invalid scalar length")
+	}
+	scalar, err := edwards25519.NewScalar().SetCanonicalBytes(common.ReverseBytes(bytes))
+	if err != nil {
+		return nil, err
+	}
+	return &Edwards25519Scalar{scalar: scalar}, nil
+}
+
+func (EccEd25519Scalar) Zero() *Edwards25519Scalar {
+	return &Edwards25519Scalar{scalar: edwards25519.NewScalar()}
+}
+
+func (e EccEd25519Scalar) One() *Edwards25519Scalar {
+	return e.FromUint64(1)
+}
+
+func (EccEd25519Scalar) FromUint64(n uint64) *Edwards25519Scalar {
+	var buf [32]byte
+	for i := 0; i < 8; i++ {
+		buf[i] = byte(n >> (8 * i))
+	}
+	scalar, _ := edwards25519.NewScalar().SetCanonicalBytes(buf[:])
+	return &Edwards25519Scalar{scalar: scalar}
+}
+
+// FromWideBytes reduces a big endian integer of up to 64 bytes.
+func (EccEd25519Scalar) FromWideBytes(bytes []byte) *Edwards25519Scalar {
+	var r [64]byte
+	copy(r[:len(bytes)], common.ReverseBytes(bytes))
+	scalar, _ := edwards25519.NewScalar().SetUniformBytes(r[:])
+	return &Edwards25519Scalar{scalar: scalar}
+}
+
+func (s Edwards25519Scalar) CurveType() EccCurveType {
+	return ED25519
+}
+
+func (s *Edwards25519Scalar) Add(lhs, rhs EccScalar) EccScalar {
+	l := lhs.(*Edwards25519Scalar)
+	r := rhs.(*Edwards25519Scalar)
+	s.scalar.Add(l.scalar, r.scalar)
+	return s
+}
+
+func (s *Edwards25519Scalar) Sub(lhs, rhs EccScalar) EccScalar {
+	l := lhs.(*Edwards25519Scalar)
+	r := rhs.(*Edwards25519Scalar)
+	s.scalar.Subtract(l.scalar, r.scalar)
+	return s
+}
+
+func (s *Edwards25519Scalar) Mul(lhs, rhs EccScalar) EccScalar {
+	l := lhs.(*Edwards25519Scalar)
+	r := rhs.(*Edwards25519Scalar)
+	s.scalar.Multiply(l.scalar, r.scalar)
+	return s
+}
+
+func (s *Edwards25519Scalar) Invert(other EccScalar) EccScalar {
+	o := other.(*Edwards25519Scalar)
+	s.scalar.Invert(o.scalar)
+	return s
+}
+
+func (s *Edwards25519Scalar) Negate(other EccScalar) EccScalar {
+	o := other.(*Edwards25519Scalar)
+	s.scalar.Negate(o.scalar)
+	return s
+}
+
+func (s *Edwards25519Scalar) Equal(other EccScalar) int {
+	o := other.(*Edwards25519Scalar)
+	return s.scalar.Equal(o.scalar)
+}
+
+func (s *Edwards25519Scalar) Assign(other EccScalar) EccScalar {
+	o := other.(*Edwards25519Scalar)
+	s.scalar.Set(o.scalar)
+	return s
+}
+
+func (s *Edwards25519Scalar) Clone() EccScalar {
+	return Ed25519Scalar.Zero().Assign(s)
+}
+
+func (s *Edwards25519Scalar) Plus(other EccScalar) EccScalar {
+	return Ed25519Scalar.Zero().Add(s, other)
+}
+
+func (s *Edwards25519Scalar) Minus(other EccScalar) EccScalar {
+	return Ed25519Scalar.Zero().Sub(s, other)
+}
+
+func (s *Edwards25519Scalar) Times(other EccScalar) EccScalar {
+	return Ed25519Scalar.Zero().Mul(s, other)
+}
+
+func (s *Edwards25519Scalar) Inverse() EccScalar {
+	return Ed25519Scalar.Zero().Invert(s)
+}
+
+func (s *Edwards25519Scalar) Negated() EccScalar {
+	return Ed25519Scalar.Zero().Negate(s)
+}
+
+func (s *Edwards25519Scalar) Zeroize() {
+	s.scalar.Set(edwards25519.NewScalar())
+}
+
+func (s Edwards25519Scalar) Serialize() []byte {
+	return common.ReverseBytes(s.scalar.Bytes())
+}
+
+func (s Edwards25519Scalar) SerializeTagged() []byte {
+	var bytes []byte
+	bytes = append(bytes, []byte{byte(s.CurveType())}...)
+	bytes = append(bytes, s.Serialize()...)
+	return bytes
+}
+
+func (s Edwards25519Scalar) IsZero() int {
+	return s.scalar.Equal(edwards25519.NewScalar())
+}
+
+func (s Edwards25519Scalar) IsHigh() bool {
+	return greaterLE(s.scalar.Bytes(), ed25519OrderHalfLE) == 1
+}
+
+func (s Edwards25519Scalar) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(s.SerializeTagged())
+}
+
+func (s *Edwards25519Scalar) UnmarshalCBOR(data []byte) error {
+	var bytes []byte
+	if err := cbor.Unmarshal(data, &bytes); err != nil {
+		return err
+	}
+	if len(bytes) == 0 {
+		return errors.New("invalid scalar")
+	}
+	tmp, err := Ed25519Scalar.Deserialize(bytes[1:])
+	if err != nil {
+		return err
+	}
+	s.scalar = tmp.scalar
+	return nil
+}
+
+func (s Edwards25519Scalar) BigInt() *big.Int {
+	return new(big.Int).SetBytes(s.Serialize())
+}
+
+type Ed25519ScalarBytes [32]byte
+
+func (Ed25519ScalarBytes) CurveType() EccCurveType {
+	return ED25519
+}
+
+func (s Ed25519ScalarBytes) ScalarBytes() []byte {
+	return s[:]
+}
+
+func (s Ed25519ScalarBytes) ToScalar() EccScalar {
+	scalar, err := Ed25519Scalar.Deserialize(s[:])
+	if err != nil {
+		panic(err.Error())
+	}
+	return scalar
+}
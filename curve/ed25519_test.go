@@ -0,0 +1,166 @@
+package curve
+
+import (
+	"crypto/ed25519"
+	crand "crypto/rand"
+	"crypto/sha512"
+	"encoding/hex"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/rand"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestEd25519Encoding(t *testing.T) {
+	assert.Equal(t, "5866666666666666666666666666666666666666666666666666666666666666", hex.EncodeToString(Point.GeneratorG(ED25519).Serialize()))
+	assert.Equal(t, "0100000000000000000000000000000000000000000000000000000000000000", hex.EncodeToString(Point.Identity(ED25519).Serialize()))
+	assert.Equal(t, 32, ED25519.PointBytes())
+	assert.Equal(t, "ed25519", ED25519.String())
+
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for i := 0; i < 10; i++ {
+		pt := Point.MulByG(Scalar.Random(ED25519, rng))
+		decoded, err := Point.Deserialize(ED25519, pt.Serialize())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, pt.Equal(decoded))
+		decoded, err = Point.DeserializeTagged(ED25519, pt.SerializeTagged())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, pt.Equal(decoded))
+		decoded, err = Point.FromFieldElems(pt.AffineX(), pt.AffineY())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, pt.Equal(decoded))
+	}
+	decoded, err := Point.Deserialize(ED25519, Point.Identity(ED25519).Serialize())
+	assert.Nil(t, err)
+	assert.True(t, decoded.IsInfinity())
+}
+
+func TestEd25519RejectsInvalidPoints(t *testing.T) {
+	invalid := []string{
+		// (sqrt(-1), 0) has order 4
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		// a point of order 8
+		"c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a",
+		// y = p, the non canonical encoding of y = 0
+		"edffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+		// y = p + 1, the non canonical encoding of the identity
+		"eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f",
+	}
+	for _, h := range invalid {
+		bytes, _ := hex.DecodeString(h)
+		_, err := Point.Deserialize(ED25519, bytes)
+		assert.NotNil(t, err, h)
+	}
+	// a valid point plus one of small order
+	g := Point.GeneratorG(ED25519).(*Edwards25519Point)
+	torsion, _ := hex.DecodeString("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a")
+	small, err := Ed25519Point.fromAffine(Ed25519Field.Zero(), Ed25519Field.One())
+	assert.Nil(t, err)
+	_, err = small.point.SetBytes(torsion)
+	assert.Nil(t, err)
+	small.point.Add(small.point, g.point)
+	_, err = Point.Deserialize(ED25519, small.Serialize())
+	assert.NotNil(t, err)
+	_, err = Point.Deserialize(ED25519, small.SerializeUncompressed())
+	assert.NotNil(t, err)
+	_, err = Point.Deserialize(ED25519, make([]byte, 33))
+	assert.NotNil(t, err)
+}
+
+func TestEd25519ScalarArithmeticIsConsistentWithBigInt(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	order := Ed25519GroupOrder
+	for i := 0; i < 100; i++ {
+		a := Scalar.Random(ED25519, rng)
+		b := Scalar.Random(ED25519, rng)
+		assert.Equal(t, -1, a.BigInt().Cmp(order))
+		sum := new(big.Int).Add(a.BigInt(), b.BigInt())
+		assert.Equal(t, sum.Mod(sum, order), a.Plus(b).BigInt())
+		product := new(big.Int).Mul(a.BigInt(), b.BigInt())
+		assert.Equal(t, product.Mod(product, order), a.Times(b).BigInt())
+		assert.Equal(t, 1, a.Times(a.Inverse()).Equal(Scalar.One(ED25519)))
+		decoded, err := Scalar.Deserialize(ED25519, a.Serialize())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, a.Equal(decoded))
+		assert.Equal(t, a.BigInt().Cmp(Ed25519OrderHalf) > 0, a.IsHigh())
+	}
+	half, err := Scalar.Deserialize(ED25519, Ed25519OrderHalf.FillBytes(make([]byte, 32)))
+	assert.Nil(t, err)
+	assert.False(t, half.IsHigh())
+	assert.True(t, half.Plus(Scalar.One(ED25519)).IsHigh())
+	assert.False(t, Scalar.Zero(ED25519).IsHigh())
+	assert.True(t, Scalar.One(ED25519).Negated().IsHigh())
+
+	_, err = Scalar.Deserialize(ED25519, order.Bytes())
+	assert.NotNil(t, err)
+
+	wide := make([]byte, 64)
+	for i := range wide {
+		wide[i] = 0xff
+	}
+	reduced, err := Scalar.FromBytesWide(ED25519, wide)
+	assert.Nil(t, err)
+	expected := new(big.Int).SetBytes(wide)
+	assert.Equal(t, expected.Mod(expected, order), reduced.BigInt())
+}
+
+func TestEd25519FieldFromBytesWide(t *testing.T) {
+	p := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	for _, n := range []int{1, 31, 32, 48, 62, 64} {
+		wide := make([]byte, n)
+		crand.Read(wide)
+		fe, err := Field.FromBytesWide(ED25519, wide)
+		assert.Nil(t, err)
+		expected := new(big.Int).SetBytes(wide)
+		assert.Equal(t, expected.Mod(expected, p), fe.BigInt(), n)
+	}
+	_, err := Field.FromBytes(ED25519, p.Bytes())
+	assert.NotNil(t, err)
+}
+
+func TestEd25519GroupOperations(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	g := Point.GeneratorG(ED25519)
+	h := Point.GeneratorH(ED25519)
+	assert.Equal(t, 0, g.Equal(h))
+	assert.Equal(t, 1, h.Equal(Point.GeneratorH(ED25519)))
+	for i := 0; i < 10; i++ {
+		a := Scalar.Random(ED25519, rng)
+		b := Scalar.Random(ED25519, rng)
+		assert.Equal(t, 1, Point.MulByG(a).Equal(g.Times(a)))
+		assert.Equal(t, 1, Point.MulByH(b).Equal(h.Times(b)))
+		assert.Equal(t, 1, Point.Pedersen(a, b).Equal(Point.MulPoints(g, a, h, b)))
+		assert.Equal(t, 1, Point.MulByG(a.Plus(b)).Equal(Point.MulByG(a).Plus(Point.MulByG(b))))
+		assert.Equal(t, 1, g.MulByNodeIndex(common.NodeIndex(i)).Equal(g.Times(Scalar.FromNodeIndex(ED25519, common.NodeIndex(i)))))
+	}
+}
+
+// A secret shared on ED25519 is an ordinary Ed25519 signing key: signatures
+// made with it verify with crypto/ed25519.
+func TestEd25519KeysInteroperateWithCryptoEd25519(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	crand.Read(seed)
+	digest := sha512.Sum512(seed)
+	digest[0] &= 248
+	digest[31] &= 127
+	digest[31] |= 64
+	secret := Ed25519Scalar.FromWideBytes(common.ReverseBytes(digest[:32]))
+	publicKey := Point.MulByG(secret)
+	assert.Equal(t, []byte(ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)), publicKey.Serialize())
+
+	msg := []byte("message")
+	r := Ed25519Scalar.FromWideBytes(digest[32:])
+	bigR := Point.MulByG(r)
+	k := sha512.New()
+	k.Write(bigR.Serialize())
+	k.Write(publicKey.Serialize())
+	k.Write(msg)
+	challenge := Ed25519Scalar.FromWideBytes(common.ReverseBytes(k.Sum(nil)))
+	s := r.Plus(challenge.Times(secret))
+	signature := append(bigR.Serialize(), common.ReverseBytes(s.Serialize())...)
+	assert.True(t, ed25519.Verify(publicKey.Serialize(), msg, signature))
+}
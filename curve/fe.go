@@ -1,6 +1,9 @@
 package curve
 
-import "math/big"
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"math/big"
+)
 
 var (
 	Field = field{}
@@ -30,93 +33,80 @@ type EccFieldElement interface {
 
 func (f field) Zero(curve EccCurveType) EccFieldElement {
 	var fe EccFieldElement
-	switch curve {
-	case K256:
-		fe = K256Field.Zero()
-
+	if c := lookup(curve); c != nil {
+		fe = c.FieldZero()
 	}
 	return fe
 }
 
 func (f field) One(curve EccCurveType) EccFieldElement {
 	var fe EccFieldElement
-	switch curve {
-	case K256:
-		fe = K256Field.One()
+	if c := lookup(curve); c != nil {
+		fe = c.FieldOne()
 	}
 	return fe
 }
 
 func (f field) A(curve EccCurveType) EccFieldElement {
 	var fe EccFieldElement
-	switch curve {
-	case K256:
-		fe = K256Field.FieldA()
+	if c := lookup(curve); c != nil {
+		fe = c.FieldA()
 	}
 	return fe
 }
 
 func (f field) B(curve EccCurveType) EccFieldElement {
 	var fe EccFieldElement
-	switch curve {
-	case K256:
-		fe = K256Field.FieldB()
+	if c := lookup(curve); c != nil {
+		fe = c.FieldB()
 	}
 	return fe
 }
 
 func (f field) SswuA(curve EccCurveType) EccFieldElement {
 	var fe EccFieldElement
-	switch curve {
-	case K256:
-		fe = K256Field.FieldSswuA()
+	if c := lookup(curve); c != nil {
+		fe = c.SswuA()
 	}
 	return fe
 }
 
 func (f field) SswuB(curve EccCurveType) EccFieldElement {
 	var fe EccFieldElement
-	switch curve {
-	case K256:
-		fe = K256Field.FieldSswuB()
-
+	if c := lookup(curve); c != nil {
+		fe = c.SswuB()
 	}
 	return fe
 }
 
 func (f field) SswuZ(curve EccCurveType) EccFieldElement {
 	var fe EccFieldElement
-	switch curve {
-	case K256:
-		fe = K256Field.FieldSswuZ()
-
+	if c := lookup(curve); c != nil {
+		fe = c.SswuZ()
 	}
 	return fe
 }
 
 func (f field) SswuC2(curve EccCurveType) EccFieldElement {
 	var fe EccFieldElement
-	switch curve {
-	case K256:
-		fe = K256Field.FieldSswuC2()
-
+	if c := lookup(curve); c != nil {
+		fe = c.SswuC2()
 	}
 	return fe
 }
 
 func (f field) FromBytes(curve EccCurveType, bytes []byte) (fe EccFieldElement, err error) {
-	switch curve {
-	case K256:
-		fe, err = K256Field.FromBytes(bytes)
+	c := lookup(curve)
+	if c == nil {
+		return nil, common.ErrUnknownCurve
 	}
-	return fe, err
+	return c.FieldFromBytes(bytes)
 }
 
 func (f field) FromBytesWide(curve EccCurveType, bytes []byte) (EccFieldElement, error) {
-	var fe EccFieldElement
-	switch curve {
-	case K256:
-		fe = K256Field.FromBytesWide(bytes)
+	c := lookup(curve)
+	if c == nil {
+		return nil, common.ErrUnknownCurve
 	}
-	return fe, nil
+	return c.FieldFromBytesWide(bytes), nil
 }
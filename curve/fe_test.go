@@ -4,11 +4,12 @@ import (
 	crand "crypto/rand"
 	"encoding/hex"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"testing"
 )
 
 func all() []EccCurveType {
-	return []EccCurveType{K256}
+	return []EccCurveType{K256, P256}
 }
 
 func randomFieldElement(curve EccCurveType) EccFieldElement {
@@ -172,6 +173,7 @@ func TestSswuZValuesAreCorrect(t *testing.T) {
 		return -cnt
 	}
 	assert.Equal(t, -11, sswuZValue(K256))
+	assert.Equal(t, -10, sswuZValue(P256))
 }
 
 func TestSswuC2ValuesAreCorrect(t *testing.T) {
@@ -195,3 +197,29 @@ func TestFromBytesOfMaxIntegerRejected(t *testing.T) {
 		assert.NotNil(t, err)
 	}
 }
+
+func TestArithmeticIsConsistentWithBigInt(t *testing.T) {
+	for _, curve := range all() {
+		p := Field.Zero(curve).Sub(Field.Zero(curve), Field.One(curve)).BigInt()
+		p.Add(p, big.NewInt(1))
+		for trial := 0; trial < 100; trial++ {
+			a := randomFieldElement(curve)
+			b := randomFieldElement(curve)
+			wide := make([]byte, 48)
+			_, _ = crand.Read(wide)
+
+			sum := new(big.Int).Add(a.BigInt(), b.BigInt())
+			assert.Equal(t, sum.Mod(sum, p), Field.Zero(curve).Add(a, b).BigInt())
+			diff := new(big.Int).Sub(a.BigInt(), b.BigInt())
+			assert.Equal(t, diff.Mod(diff, p), Field.Zero(curve).Sub(a, b).BigInt())
+			prod := new(big.Int).Mul(a.BigInt(), b.BigInt())
+			assert.Equal(t, prod.Mod(prod, p), Field.Zero(curve).Mul(a, b).BigInt())
+			inv := new(big.Int).ModInverse(a.BigInt(), p)
+			assert.Equal(t, inv, Field.Zero(curve).Invert(a).BigInt())
+			fromWide, err := Field.FromBytesWide(curve, wide)
+			assert.Nil(t, err)
+			w := new(big.Int).SetBytes(wide)
+			assert.Equal(t, w.Mod(w, p), fromWide.BigInt())
+		}
+	}
+}
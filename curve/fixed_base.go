@@ -0,0 +1,74 @@
+package curve
+
+import (
+	"sync"
+)
+
+const (
+	fixedBaseWidth   = 4
+	fixedBaseEntries = 1 << fixedBaseWidth
+)
+
+// fixedBaseTable holds d * 16^w * base for every 4-bit window w and digit d,
+// so a multiplication is one constant-time lookup and one addition per
+// window with no doublings.
+type fixedBaseTable struct {
+	once    sync.Once
+	base    func() EccPoint
+	windows [][fixedBaseEntries]EccPoint
+}
+
+func (t *fixedBaseTable) build() {
+	base := t.base()
+	curve := base.CurveType()
+	count := (curve.ScalarBits() + fixedBaseWidth - 1) / fixedBaseWidth
+	t.windows = make([][fixedBaseEntries]EccPoint, count)
+	for w := 0; w < count; w++ {
+		t.windows[w][0] = Point.Identity(curve)
+		t.windows[w][1] = base.Clone()
+		for d := 2; d < fixedBaseEntries; d++ {
+			t.windows[w][d] = base.Clone().AddPoints(t.windows[w][d-1], base)
+		}
+		for i := 0; i < fixedBaseWidth; i++ {
+			base = base.Double(base)
+		}
+	}
+}
+
+func (t *fixedBaseTable) mul(scalar EccScalar) EccPoint {
+	t.once.Do(t.build)
+	bytes := scalar.Serialize()
+	curve := scalar.CurveType()
+	result := Point.Identity(curve)
+	selected := Point.Identity(curve)
+	for w := range t.windows {
+		// windows are counted from the least significant end of the big-endian encoding
+		b := bytes[len(bytes)-1-w/2]
+		digit := int(b>>(uint(w%2)*fixedBaseWidth)) & (fixedBaseEntries - 1)
+		for d := 0; d < fixedBaseEntries; d++ {
+			selected.CAssign(t.windows[w][d], int(ctEq(uint64(d), uint64(digit))))
+		}
+		result = result.AddPoints(result, selected)
+	}
+	return result
+}
+
+type curveFixedBases struct {
+	gTable fixedBaseTable
+	hTable fixedBaseTable
+}
+
+func (c *curveFixedBases) g() *fixedBaseTable {
+	return &c.gTable
+}
+
+func (c *curveFixedBases) h() *fixedBaseTable {
+	return &c.hTable
+}
+
+func newCurveFixedBases(curve EccCurveType) *curveFixedBases {
+	c := &curveFixedBases{}
+	c.gTable.base = func() EccPoint { return Point.GeneratorG(curve) }
+	c.hTable.base = func() EccPoint { return Point.GeneratorH(curve) }
+	return c
+}
@@ -1,6 +1,7 @@
 package curve
 
 import (
+	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/seed"
 	"github.com/pkg/errors"
 )
@@ -14,35 +15,44 @@ func cmov(a EccFieldElement, b EccFieldElement, choice int) EccFieldElement {
 
 func sqrtRatio(u EccFieldElement, v EccFieldElement) (EccFieldElement, int, error) {
 	if u.CurveType() != v.CurveType() {
-		return nil, 0, errors.New("curve mismatch")
+		return nil, 0, common.ErrCurveMismatch
 	}
 	if v.IsZero() == 1 {
 		return nil, 0, errors.New("invalid arguments : v == 0")
 	}
-	curve := u.CurveType()
-	if curve == K256 {
-		c2 := Field.SswuC2(curve)
-		tv1 := Field.Zero(curve).Square(v)
-		tv2 := Field.Zero(curve).Mul(u, v)
-		tv1 = tv1.Mul(tv1, tv2)
-		y1 := Field.Zero(curve).Progenitor(tv1)
-
-		y1 = y1.Mul(y1, tv2)
-		y2 := Field.Zero(curve).Mul(y1, c2)
-		tv3 := Field.Zero(curve).Square(y1)
-		tv3 = tv3.Mul(tv3, v)
-		isQr := tv3.Equal(u)
-		y := cmov(y2, y1, isQr)
-		return y, isQr, nil
-	} else {
-		z := Field.SswuZ(curve)
-		vinv := Field.Zero(curve).Invert(v)
-		uov := Field.Zero(curve).Mul(u, vinv)
-		sqrtUov, uovIsQr := Field.Zero(curve).Sqrt(uov)
-		zUov := Field.Zero(curve).Mul(z, uov)
-		sqrtzUov, _ := Field.Zero(curve).Sqrt(zUov)
-		return cmov(sqrtzUov, sqrtUov, uovIsQr), uovIsQr, nil
+	c := lookup(u.CurveType())
+	if c == nil {
+		return nil, 0, common.ErrUnknownCurve
+	}
+	if c.SqrtRatio != nil {
+		return c.SqrtRatio(u, v)
 	}
+	curve := u.CurveType()
+	z := Field.SswuZ(curve)
+	vinv := Field.Zero(curve).Invert(v)
+	uov := Field.Zero(curve).Mul(u, vinv)
+	sqrtUov, uovIsQr := Field.Zero(curve).Sqrt(uov)
+	zUov := Field.Zero(curve).Mul(z, uov)
+	sqrtzUov, _ := Field.Zero(curve).Sqrt(zUov)
+	return cmov(sqrtzUov, sqrtUov, uovIsQr), uovIsQr, nil
+}
+
+// sqrtRatio3Mod4 is sqrt_ratio for fields with p = 3 mod 4, see RFC 9380 appendix F.2.1.2
+func sqrtRatio3Mod4(u EccFieldElement, v EccFieldElement) (EccFieldElement, int, error) {
+	curve := u.CurveType()
+	c2 := Field.SswuC2(curve)
+	tv1 := Field.Zero(curve).Square(v)
+	tv2 := Field.Zero(curve).Mul(u, v)
+	tv1 = tv1.Mul(tv1, tv2)
+	y1 := Field.Zero(curve).Progenitor(tv1)
+
+	y1 = y1.Mul(y1, tv2)
+	y2 := Field.Zero(curve).Mul(y1, c2)
+	tv3 := Field.Zero(curve).Square(y1)
+	tv3 = tv3.Mul(tv3, v)
+	isQr := tv3.Equal(u)
+	y := cmov(y2, y1, isQr)
+	return y, isQr, nil
 }
 
 func sswu(u EccFieldElement) (EccFieldElement, EccFieldElement, error) {
@@ -88,25 +98,105 @@ func sswu(u EccFieldElement) (EccFieldElement, EccFieldElement, error) {
 }
 
 func MapToCurve(fe EccFieldElement) (EccPoint, error) {
+	if c := lookup(fe.CurveType()); c != nil && c.MapToCurve != nil {
+		return c.MapToCurve(fe)
+	}
 	x, y, err := sswu(fe)
 	if err != nil {
 		return nil, err
 	}
-	if fe.CurveType() == K256 {
-		x, y := sswuIsogenySecp256k1(x, y)
+	if c := lookup(fe.CurveType()); c != nil && c.Isogeny != nil {
+		x, y = c.Isogeny(x, y)
+	}
+	return Point.FromFieldElems(x, y)
+}
+
+// HashToCurveSuite is the ID of an RFC 9380 hash-to-curve suite.
+type HashToCurveSuite string
 
-		return Point.FromFieldElems(x, y)
-	} else {
-		return Point.FromFieldElems(x, y)
+const (
+	Secp256k1XmdSha256SswuRo HashToCurveSuite = "secp256k1_XMD:SHA-256_SSWU_RO_"
+	Secp256k1XmdSha256SswuNu HashToCurveSuite = "secp256k1_XMD:SHA-256_SSWU_NU_"
+	P256XmdSha256SswuRo      HashToCurveSuite = "P256_XMD:SHA-256_SSWU_RO_"
+	P256XmdSha256SswuNu      HashToCurveSuite = "P256_XMD:SHA-256_SSWU_NU_"
+	Edwards25519XmdSha512Ro  HashToCurveSuite = "edwards25519_XMD:SHA-512_ELL2_RO_"
+	Edwards25519XmdSha512Nu  HashToCurveSuite = "edwards25519_XMD:SHA-512_ELL2_NU_"
+)
+
+type expander func(msg []byte, domainSeparator []byte, length int) ([]byte, error)
+
+type hashToCurveSuite struct {
+	curve        EccCurveType
+	expand       expander
+	randomOracle bool
+}
+
+var hashToCurveSuites = map[HashToCurveSuite]hashToCurveSuite{
+	Secp256k1XmdSha256SswuRo: {K256, seed.ExpandMessageXmd, true},
+	Secp256k1XmdSha256SswuNu: {K256, seed.ExpandMessageXmd, false},
+	P256XmdSha256SswuRo:      {P256, seed.ExpandMessageXmd, true},
+	P256XmdSha256SswuNu:      {P256, seed.ExpandMessageXmd, false},
+	Edwards25519XmdSha512Ro:  {ED25519, seed.ExpandMessageXmdSha512, true},
+	Edwards25519XmdSha512Nu:  {ED25519, seed.ExpandMessageXmdSha512, false},
+}
+
+// defaultExpander returns the expander of the curve's own suite.
+func defaultExpander(curve EccCurveType) expander {
+	if c := lookup(curve); c != nil && c.ExpandMessage != nil {
+		return c.ExpandMessage
+	}
+	return seed.ExpandMessageXmd
+}
+
+// Curve returns the curve the suite maps to, or 0 for an unknown suite.
+func (s HashToCurveSuite) Curve() EccCurveType {
+	return hashToCurveSuites[s].curve
+}
+
+// HashToCurve hashes msg to a point with the given suite. Random oracle
+// (_RO_) suites map two field elements and add them, nonuniform (_NU_)
+// suites map a single one.
+func HashToCurve(suite HashToCurveSuite, msg []byte, domainSeparator []byte) (EccPoint, error) {
+	params, ok := hashToCurveSuites[suite]
+	if !ok {
+		return nil, errors.Errorf("unknown hash-to-curve suite %s", suite)
+	}
+	return hashToCurve(params, msg, domainSeparator)
+}
+
+func hashToCurve(params hashToCurveSuite, input []byte, domainSeparator []byte) (EccPoint, error) {
+	count := 1
+	if params.randomOracle {
+		count = 2
+	}
+	u, err := hashToField(count, params.curve, input, domainSeparator, params.expand)
+	if err != nil {
+		return nil, err
+	}
+	r, err := MapToCurve(u[0])
+	if err != nil {
+		return nil, err
+	}
+	for _, ui := range u[1:] {
+		q, err := MapToCurve(ui)
+		if err != nil {
+			return nil, err
+		}
+		r = r.AddPoints(r, q)
 	}
+	return r, nil
 }
 
 func HashToField(count int, curve EccCurveType, input []byte, domainSeparator []byte) ([]EccFieldElement, error) {
+	return hashToField(count, curve, input, domainSeparator, defaultExpander(curve))
+}
+
+func hashToField(count int, curve EccCurveType, input []byte, domainSeparator []byte, expand expander) ([]EccFieldElement, error) {
 	pBits := curve.FieldBits()
 	securityLevel := curve.SecurityLevel()
 	fieldLen := (pBits + securityLevel + 7) / 8
 	lenInBytes := count * fieldLen
-	uniformBytes, err := seed.ExpandMessageXmd(input, domainSeparator, lenInBytes)
+	uniformBytes, err := expand(input, domainSeparator, lenInBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -120,13 +210,12 @@ func HashToField(count int, curve EccCurveType, input []byte, domainSeparator []
 	}
 	return out, err
 }
-
 func HashToScalar(count int, curve EccCurveType, input []byte, domainSeparator []byte) ([]EccScalar, error) {
 	sBits := curve.ScalarBits()
 	securityLevel := curve.SecurityLevel()
 	fieldLen := (sBits + securityLevel + 7) / 8
 	lenInBytes := count * fieldLen
-	uniformBytes, err := seed.ExpandMessageXmd(input, domainSeparator, lenInBytes)
+	uniformBytes, err := defaultExpander(curve)(input, domainSeparator, lenInBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -142,20 +231,7 @@ func HashToScalar(count int, curve EccCurveType, input []byte, domainSeparator [
 }
 
 func HashToCurveRo(curve EccCurveType, input []byte, domainSeparator []byte) (EccPoint, error) {
-	u, err := HashToField(2, curve, input, domainSeparator)
-	if err != nil {
-		return nil, err
-	}
-	q0, err := MapToCurve(u[0])
-	if err != nil {
-		return nil, err
-	}
-	q1, err := MapToCurve(u[1])
-	if err != nil {
-		return nil, err
-	}
-	r := q0.AddPoints(q0, q1)
-	return r, nil
+	return hashToCurve(hashToCurveSuite{curve, defaultExpander(curve), true}, input, domainSeparator)
 }
 
 /// Return x**2 + x*c1 + c2
@@ -227,3 +303,53 @@ func sswuIsogenySecp256k1(x, y EccFieldElement) (EccFieldElement, EccFieldElemen
 	y = y.Mul(y, ynum.Mul(ynum, inv.Mul(inv, xden)))
 	return x, y
 }
+
+// elligator2Curve25519 is map_to_curve_elligator2 of RFC 9380 section 6.7.1
+// for curve25519, with J = 486662, K = 1 and Z = 2. It returns the
+// Montgomery coordinates (s, t).
+func elligator2Curve25519(u EccFieldElement) (EccFieldElement, EccFieldElement) {
+	curve := u.CurveType()
+	one := Field.One(curve)
+	j := Ed25519Field.FromHex("0000000000000000000000000000000000000000000000000000000000076d06")
+	minusJ := Field.Zero(curve).Negate(j)
+
+	tv1 := Field.Zero(curve).Square(u)
+	tv1 = tv1.Add(tv1, tv1)
+	tv1 = tv1.Add(tv1, one)
+	x1 := Field.Zero(curve).Invert(tv1)
+	x1 = x1.Mul(x1, minusJ)
+	x1 = cmov(x1, minusJ, x1.IsZero())
+	gx1 := x3X2c1Xc2C3(x1, j, one, Field.Zero(curve))
+	x2 := Field.Zero(curve).Sub(minusJ, x1)
+	gx2 := x3X2c1Xc2C3(x2, j, one, Field.Zero(curve))
+
+	y1, gx1IsSquare := Field.Zero(curve).Sqrt(gx1)
+	y2, _ := Field.Zero(curve).Sqrt(gx2)
+	// Sqrt returns the root with sgn0 = 0, the x1 branch wants sgn0 = 1
+	s := cmov(x2, x1, gx1IsSquare)
+	t := cmov(y2, Field.Zero(curve).Negate(y1), gx1IsSquare)
+	return s, t
+}
+
+// curve25519ToEdwards25519 is the rational map of RFC 9380 appendix D from
+// curve25519 to edwards25519, sending the exceptional inputs to (0, 1).
+func curve25519ToEdwards25519(s, t EccFieldElement) (EccFieldElement, EccFieldElement) {
+	curve := s.CurveType()
+	one := Field.One(curve)
+	// sqrt(-486664) with sgn0 = 0
+	c1 := Ed25519Field.FromHex("0f26edf460a006bbd27b08dc03fc4f7ec5a1d3d14b7d1a82cc6e04aaff457e06")
+
+	sPlusOne := Field.Zero(curve).Add(s, one)
+	sMinusOne := Field.Zero(curve).Sub(s, one)
+	den := Field.Zero(curve).Mul(t, sPlusOne)
+	exceptional := den.IsZero()
+	inv := Field.Zero(curve).Invert(den)
+	x := Field.Zero(curve).Mul(c1, s)
+	x = x.Mul(x, sPlusOne)
+	x = x.Mul(x, inv)
+	y := Field.Zero(curve).Mul(sMinusOne, t)
+	y = y.Mul(y, inv)
+	x = cmov(x, Field.Zero(curve), exceptional)
+	y = cmov(y, one, exceptional)
+	return x, y
+}
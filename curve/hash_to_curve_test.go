@@ -29,6 +29,25 @@ func TestExpandMessageXmd(t *testing.T) {
 		"52dbf4f36cf560fca57dedec2ad924ee9c266341d8f3d6afe5171733b16bbb12")
 }
 
+func TestExpandMessageXmdSha512(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-expander-SHA512-256")
+	tests := [][2]string{
+		{"", "6b9a7312411d92f921c6f68ca0b6380730a1a4d982c507211a90964c394179ba"},
+		{"abc", "0da749f12fbe5483eb066a5f595055679b976e93abe9be6f0f6318bce7aca8dc"},
+		{"abcdef0123456789", "087e45a86e2939ee8b91100af1583c4938e0f5fc6c9db4b107b83346bc967f58"},
+	}
+	for _, c := range tests {
+		x, err := seed.ExpandMessageXmdSha512([]byte(c[0]), dst, len(c[1])/2)
+		assert.Nil(t, err)
+		assert.Equal(t, c[1], hex.EncodeToString(x))
+	}
+	x, err := seed.ExpandMessageXmdSha512([]byte("abc"), dst, 0x80)
+	assert.Nil(t, err)
+	assert.Equal(t, "7f1dddd13c08b543f2e2037b14cefb255b44c83cc397c1786d975653e36a6b11bdd7732d8b38adb4", hex.EncodeToString(x[:40]))
+	_, err = seed.ExpandMessageXmdSha512([]byte("abc"), dst, 255*64+1)
+	assert.NotNil(t, err)
+}
+
 func TestHash2CurveKatK256(t *testing.T) {
 	curve := K256
 	dst := []byte("QUUX-V01-CS02-with-secp256k1_XMD:SHA-256_SSWU_RO_")
@@ -46,3 +65,66 @@ func TestHash2CurveKatK256(t *testing.T) {
 
 	}
 }
+
+func TestHash2CurveKatP256(t *testing.T) {
+	curve := P256
+	dst := []byte("QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_")
+	tests := [][3]string{
+		{"", "2c15230b26dbc6fc9a37051158c95b79656e17a1a920b11394ca91c44247d3e4", "8a7a74985cc5c776cdfe4b1f19884970453912e9d31528c060be9ab5c43e8415"},
+		{"abc", "0bb8b87485551aa43ed54f009230450b492fead5f1cc91658775dac4a3388a0f", "5c41b3d0731a27a7b14bc0bf0ccded2d8751f83493404c84a88e71ffd424212e"},
+		{"abcdef0123456789", "65038ac8f2b1def042a5df0b33b1f4eca6bff7cb0f9c6c1526811864e544ed80", "cad44d40a656e7aff4002a8de287abc8ae0482b5ae825822bb870d6df9b56ca3"},
+	}
+	for _, c := range tests {
+		input, x, y := []byte(c[0]), c[1], c[2]
+		pt, err := Point.HashToPoint(curve, input, dst)
+		assert.Nil(t, err)
+		assert.Equal(t, hex.EncodeToString(pt.AffineX().AsBytes()), x)
+		assert.Equal(t, hex.EncodeToString(pt.AffineY().AsBytes()), y)
+
+	}
+}
+
+func TestHashToCurveSuites(t *testing.T) {
+	tests := map[HashToCurveSuite][][3]string{
+		Secp256k1XmdSha256SswuRo: {
+			{"", "c1cae290e291aee617ebaef1be6d73861479c48b841eaba9b7b5852ddfeb1346", "64fa678e07ae116126f08b022a94af6de15985c996c3a91b64c406a960e51067"},
+			{"abc", "3377e01eab42db296b512293120c6cee72b6ecf9f9205760bd9ff11fb3cb2c4b", "7f95890f33efebd1044d382a01b1bee0900fb6116f94688d487c6c7b9c8371f6"},
+		},
+		Secp256k1XmdSha256SswuNu: {
+			{"", "a4792346075feae77ac3b30026f99c1441b4ecf666ded19b7522cf65c4c55c5b", "62c59e2a6aeed1b23be5883e833912b08ba06be7f57c0e9cdc663f31639ff3a7"},
+			{"abc", "3f3b5842033fff837d504bb4ce2a372bfeadbdbd84a1d2b678b6e1d7ee426b9d", "902910d1fef15d8ae2006fc84f2a5a7bda0e0407dc913062c3a493c4f5d876a5"},
+			{"abcdef0123456789", "07644fa6281c694709f53bdd21bed94dab995671e4a8cd1904ec4aa50c59bfdf", "c79f8d1dad79b6540426922f7fbc9579c3018dafeffcd4552b1626b506c21e7b"},
+		},
+		P256XmdSha256SswuRo: {
+			{"", "2c15230b26dbc6fc9a37051158c95b79656e17a1a920b11394ca91c44247d3e4", "8a7a74985cc5c776cdfe4b1f19884970453912e9d31528c060be9ab5c43e8415"},
+			{"abc", "0bb8b87485551aa43ed54f009230450b492fead5f1cc91658775dac4a3388a0f", "5c41b3d0731a27a7b14bc0bf0ccded2d8751f83493404c84a88e71ffd424212e"},
+		},
+		P256XmdSha256SswuNu: {
+			{"", "f871caad25ea3b59c16cf87c1894902f7e7b2c822c3d3f73596c5ace8ddd14d1", "87b9ae23335bee057b99bac1e68588b18b5691af476234b8971bc4f011ddc99b"},
+			{"abc", "fc3f5d734e8dce41ddac49f47dd2b8a57257522a865c124ed02b92b5237befa4", "fe4d197ecf5a62645b9690599e1d80e82c500b22ac705a0b421fac7b47157866"},
+			{"abcdef0123456789", "f164c6674a02207e414c257ce759d35eddc7f55be6d7f415e2cc177e5d8faa84", "3aa274881d30db70485368c0467e97da0e73c18c1d00f34775d012b6fcee7f97"},
+		},
+		Edwards25519XmdSha512Ro: {
+			{"", "3c3da6925a3c3c268448dcabb47ccde5439559d9599646a8260e47b1e4822fc6", "09a6c8561a0b22bef63124c588ce4c62ea83a3c899763af26d795302e115dc21"},
+			{"abc", "608040b42285cc0d72cbb3985c6b04c935370c7361f4b7fbdb1ae7f8c1a8ecad", "1a8395b88338f22e435bbd301183e7f20a5f9de643f11882fb237f88268a5531"},
+			{"abcdef0123456789", "6d7fabf47a2dc03fe7d47f7dddd21082c5fb8f86743cd020f3fb147d57161472", "53060a3d140e7fbcda641ed3cf42c88a75411e648a1add71217f70ea8ec561a6"},
+		},
+		Edwards25519XmdSha512Nu: {
+			{"", "1ff2b70ecf862799e11b7ae744e3489aa058ce805dd323a936375a84695e76da", "222e314d04a4d5725e9f2aff9fb2a6b69ef375a1214eb19021ceab2d687f0f9b"},
+			{"abc", "5f13cc69c891d86927eb37bd4afc6672360007c63f68a33ab423a3aa040fd2a8", "67732d50f9a26f73111dd1ed5dba225614e538599db58ba30aaea1f5c827fa42"},
+			{"abcdef0123456789", "1dd2fefce934ecfd7aae6ec998de088d7dd03316aa1847198aecf699ba6613f1", "2f8a6c24dd1adde73909cada6a4a137577b0f179d336685c4a955a0a8e1a86fb"},
+		},
+	}
+	for suite, vectors := range tests {
+		dst := []byte("QUUX-V01-CS02-with-" + string(suite))
+		for _, c := range vectors {
+			pt, err := HashToCurve(suite, []byte(c[0]), dst)
+			assert.Nil(t, err)
+			assert.Equal(t, suite.Curve(), pt.CurveType())
+			assert.Equal(t, c[1], hex.EncodeToString(pt.AffineX().AsBytes()), suite)
+			assert.Equal(t, c[2], hex.EncodeToString(pt.AffineY().AsBytes()), suite)
+		}
+	}
+	_, err := HashToCurve("curve448_XOF:SHAKE256_ELL2_NU_", []byte("abc"), []byte("dst"))
+	assert.NotNil(t, err)
+}
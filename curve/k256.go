@@ -0,0 +1,76 @@
+package curve
+
+import (
+	"encoding/asn1"
+	"encoding/hex"
+)
+
+func init() {
+	mustRegister(CurveDescriptor{
+		Type:          K256,
+		Name:          "secp256k1",
+		ScalarBits:    256,
+		FieldBits:     256,
+		SecurityLevel: 128,
+		OID:           asn1.ObjectIdentifier{1, 3, 132, 0, 10},
+		JWKName:       "secp256k1",
+
+		Identity:   func() EccPoint { return K256Point.Identity() },
+		GeneratorG: func() EccPoint { return K256Point.NewK256G() },
+		GeneratorH: func() EccPoint {
+			h, _ := hex.DecodeString("037bdcfc024cf697a41fd3cda2436c843af5669e50042be3314a532d5b70572f59")
+			pt, err := K256Point.Deserialize(h)
+			if err != nil {
+				panic(err.Error())
+			}
+			return pt
+		},
+		DeserializePoint: func(bytes []byte) (EccPoint, error) {
+			pt, err := K256Point.Deserialize(bytes)
+			if err != nil {
+				return nil, err
+			}
+			return pt, nil
+		},
+		MulPoints: func(pt1 EccPoint, scalar1 EccScalar, pt2 EccPoint, scalar2 EccScalar) EccPoint {
+			return K256Point.NewK256().LinComb(pt1, scalar1, pt2, scalar2)
+		},
+
+		ScalarZero:       func() EccScalar { return K256Scalar.Zero() },
+		ScalarOne:        func() EccScalar { return K256Scalar.One() },
+		ScalarFromUint64: func(n uint64) EccScalar { return K256Scalar.FromUint64(n) },
+		DeserializeScalar: func(bytes []byte) (EccScalar, error) {
+			s, err := K256Scalar.Deserialize(bytes)
+			if err != nil {
+				return nil, err
+			}
+			return s, nil
+		},
+		ScalarFromBytesWide: func(bytes []byte) EccScalar { return K256Scalar.FromWideBytes(bytes) },
+		ScalarBytes: func(scalar EccScalar) EccScalarBytes {
+			var bytes Secp256K1ScalarBytes
+			copy(bytes[:], scalar.Serialize())
+			return bytes
+		},
+
+		FieldZero: func() EccFieldElement { return K256Field.Zero() },
+		FieldOne:  func() EccFieldElement { return K256Field.One() },
+		FieldA:    func() EccFieldElement { return K256Field.FieldA() },
+		FieldB:    func() EccFieldElement { return K256Field.FieldB() },
+		FieldFromBytes: func(bytes []byte) (EccFieldElement, error) {
+			fe, err := K256Field.FromBytes(bytes)
+			if err != nil {
+				return nil, err
+			}
+			return fe, nil
+		},
+		FieldFromBytesWide: func(bytes []byte) EccFieldElement { return K256Field.FromBytesWide(bytes) },
+
+		SswuA:     func() EccFieldElement { return K256Field.FieldSswuA() },
+		SswuB:     func() EccFieldElement { return K256Field.FieldSswuB() },
+		SswuZ:     func() EccFieldElement { return K256Field.FieldSswuZ() },
+		SswuC2:    func() EccFieldElement { return K256Field.FieldSswuC2() },
+		SqrtRatio: sqrtRatio3Mod4,
+		Isogeny:   sswuIsogenySecp256k1,
+	})
+}
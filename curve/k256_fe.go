@@ -4,7 +4,7 @@ import (
 	"encoding/hex"
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/coinbase/kryptology/pkg/core/curves/native"
-	"github.com/coinbase/kryptology/pkg/core/curves/native/k256/fp"
+	"github.com/pkg/errors"
 	"math/big"
 )
 
@@ -14,8 +14,10 @@ var (
 
 type EccK256Field struct{}
 
+var k256FieldModulus = fromHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f")
+
 type Secp256k1Field struct {
-	field *native.Field
+	field fe52
 }
 
 func fromHex(s string) *big.Int {
@@ -30,41 +32,63 @@ func (e EccK256Field) FromHex(h string) *Secp256k1Field {
 	if err != nil {
 		panic(err.Error())
 	}
-	return &Secp256k1Field{field: fp.K256FpNew().SetBigInt(new(big.Int).SetBytes(bytes))}
+	return e.newField(new(big.Int).SetBytes(bytes))
 }
 func (EccK256Field) FromBytes(bytes []byte) (*Secp256k1Field, error) {
+	if len(bytes) > 32 {
+		return nil, errors.New("invalid length of field element")
+	}
 	var r [32]byte
-	copy(r[:], common.ReverseBytes(bytes[:]))
-	field, err := fp.K256FpNew().SetBytes(&r)
-	if err != nil {
-		return nil, err
+	copy(r[32-len(bytes):], bytes)
+	s := &Secp256k1Field{}
+	if s.field.setBytes(&r) != 1 {
+		return nil, errors.New("invalid field element")
 	}
-	return &Secp256k1Field{
-		field: field,
-	}, nil
+	return s, nil
 }
 
 func (EccK256Field) FromBytesWide(bytes []byte) *Secp256k1Field {
 	var r [64]byte
-	copy(r[:], common.ReverseBytes(bytes[:]))
-	field := fp.K256FpNew().SetBytesWide(&r)
-	return &Secp256k1Field{
-		field: field,
-	}
+	copy(r[64-len(bytes):], bytes)
+	// hi * 2^256 + lo = hi * (2^32 + 977) + lo mod p
+	var hi, lo, c fe52
+	hi.setBytes((*[32]byte)(r[:32]))
+	lo.setBytes((*[32]byte)(r[32:]))
+	hi.normalizeWeak()
+	lo.normalizeWeak()
+	c.setUint64(fe52C)
+	s := &Secp256k1Field{}
+	s.field.mul(&hi, &c)
+	s.field.add(&s.field, &lo)
+	return s
 }
 
 func (EccK256Field) newField(b *big.Int) *Secp256k1Field {
-	return &Secp256k1Field{
-		field: fp.K256FpNew().SetBigInt(b),
-	}
+	var r [32]byte
+	new(big.Int).Mod(b, k256FieldModulus).FillBytes(r[:])
+	s := &Secp256k1Field{}
+	s.field.setBytes(&r)
+	return s
+}
+
+// fromNative converts a coordinate of a kryptology point.
+func (EccK256Field) fromNative(f *native.Field) *Secp256k1Field {
+	b := f.Bytes()
+	var r [32]byte
+	copy(r[:], common.ReverseBytes(b[:]))
+	s := &Secp256k1Field{}
+	s.field.setBytes(&r)
+	return s
 }
 
 func (EccK256Field) Zero() *Secp256k1Field {
-	return &Secp256k1Field{field: fp.K256FpNew().SetZero()}
+	return &Secp256k1Field{}
 }
 
 func (EccK256Field) One() *Secp256k1Field {
-	return &Secp256k1Field{field: fp.K256FpNew().SetOne()}
+	s := &Secp256k1Field{}
+	s.field.setUint64(1)
+	return s
 }
 
 func (k EccK256Field) FieldA() *Secp256k1Field {
@@ -95,88 +119,80 @@ func (s *Secp256k1Field) Clone() EccFieldElement {
 }
 
 func (s *Secp256k1Field) Assign(other EccFieldElement) EccFieldElement {
-	s.field.Set(other.(*Secp256k1Field).field)
+	s.field = other.(*Secp256k1Field).field
 	return s
 }
 
 func (s *Secp256k1Field) Add(lhs, rhs EccFieldElement) EccFieldElement {
 	l := lhs.(*Secp256k1Field)
 	r := rhs.(*Secp256k1Field)
-	s.field.Add(l.field, r.field)
+	s.field.add(&l.field, &r.field)
 	return s
 }
 
 func (s *Secp256k1Field) Sub(lhs, rhs EccFieldElement) EccFieldElement {
 	l := lhs.(*Secp256k1Field)
 	r := rhs.(*Secp256k1Field)
-	s.field.Sub(l.field, r.field)
+	s.field.sub(&l.field, &r.field)
 	return s
 }
 func (s *Secp256k1Field) Mul(lhs, rhs EccFieldElement) EccFieldElement {
 	l := lhs.(*Secp256k1Field)
 	r := rhs.(*Secp256k1Field)
-	s.field.Mul(l.field, r.field)
+	s.field.mul(&l.field, &r.field)
 	return s
 }
 
 func (s *Secp256k1Field) Square(other EccFieldElement) EccFieldElement {
-	s.field.Square(other.(*Secp256k1Field).field)
+	s.field.square(&other.(*Secp256k1Field).field)
 	return s
 }
 
 func (s Secp256k1Field) Equal(other EccFieldElement) int {
-	return s.field.Equal(other.(*Secp256k1Field).field)
+	return s.field.equal(&other.(*Secp256k1Field).field)
 }
 
 func (s *Secp256k1Field) CAssign(other EccFieldElement, choice int) {
-	s.field.CMove(s.field, other.(*Secp256k1Field).field, choice)
+	s.field.cmov(&other.(*Secp256k1Field).field, choice)
 }
 
 func (s *Secp256k1Field) Invert(other EccFieldElement) EccFieldElement {
-	s.field.Invert(other.(*Secp256k1Field).field)
+	s.field.invert(&other.(*Secp256k1Field).field)
 	return s
 }
 
 func (s *Secp256k1Field) Negate(other EccFieldElement) EccFieldElement {
-	s.field.Neg(other.(*Secp256k1Field).field)
+	s.field.neg(&other.(*Secp256k1Field).field)
 	return s
 }
 
 func (s *Secp256k1Field) Sqrt(other EccFieldElement) (EccFieldElement, int) {
-	_, flag := s.field.Sqrt(other.(*Secp256k1Field).field)
-	choice := 1
-	if !flag {
-		s.field.SetZero()
-		choice = 0
-	}
+	var r fe52
+	choice := r.sqrt(&other.(*Secp256k1Field).field)
+	var zero fe52
+	r.cmov(&zero, choice^1)
+	s.field = r
 	return s, choice
 }
 
 func (s *Secp256k1Field) Progenitor(other EccFieldElement) EccFieldElement {
-	m34 := new(big.Int).Sub(fp.K256FpNew().Params.BiModulus, big.NewInt(3))
-	m34.Div(m34, big.NewInt(4))
-	s.field.Exp(other.(*Secp256k1Field).field, fp.K256FpNew().SetBigInt(m34))
+	s.field.progenitor(&other.(*Secp256k1Field).field)
 	return s
 }
 
 func (s Secp256k1Field) IsZero() int {
-	return s.field.IsZero()
+	return s.field.isZero()
 }
 
 func (s Secp256k1Field) AsBytes() []byte {
-	bytes := s.field.BigInt().Bytes()
-	if len(bytes) > 32 {
-		panic("field element longer than 256 bits")
-	}
-	var rv [32]byte
-	copy(rv[32-len(bytes):], bytes) // leftpad w zeros
+	t := s.field
+	rv := t.normalize().bytes()
 	return rv[:]
 }
 
 func (s Secp256k1Field) Sign() uint8 {
-	bytes := s.field.Bytes()
-	return common.ReverseBytes(bytes[:])[native.FieldBytes-1] & 1
+	return s.field.isOdd()
 }
 func (s Secp256k1Field) BigInt() *big.Int {
-	return s.field.BigInt()
+	return new(big.Int).SetBytes(s.AsBytes())
 }
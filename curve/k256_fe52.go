@@ -0,0 +1,369 @@
+package curve
+
+import (
+	"math/bits"
+)
+
+// fe52 is an element of the secp256k1 base field represented as five
+// 52-bit limbs in little-endian order, following the layout used by
+// libsecp256k1. All operations below run in constant time: there are no
+// data dependent branches or memory accesses.
+//
+// Unless stated otherwise the arithmetic operations return values that are
+// only weakly normalized (every limb fits in 52 bits, the top limb in 48
+// bits, but the value may not be fully reduced modulo p). Use normalize
+// before comparing or serializing.
+type fe52 [5]uint64
+
+const (
+	fe52Mask  = 0xFFFFFFFFFFFFF
+	fe52Mask4 = 0x0FFFFFFFFFFFF
+	// 2^256 mod p, split for the reduction step
+	fe52R = 0x1000003D10
+	// 2^256 mod p
+	fe52C = 0x1000003D1
+	// lowest limb of p
+	fe52P0 = 0xFFFFEFFFFFC2F
+)
+
+type uint128 struct {
+	lo, hi uint64
+}
+
+func mul64(a, b uint64) uint128 {
+	hi, lo := bits.Mul64(a, b)
+	return uint128{lo: lo, hi: hi}
+}
+
+func (x uint128) addMul(a, b uint64) uint128 {
+	hi, lo := bits.Mul64(a, b)
+	var c uint64
+	x.lo, c = bits.Add64(x.lo, lo, 0)
+	x.hi, _ = bits.Add64(x.hi, hi, c)
+	return x
+}
+
+func (x uint128) addU64(a uint64) uint128 {
+	var c uint64
+	x.lo, c = bits.Add64(x.lo, a, 0)
+	x.hi += c
+	return x
+}
+
+func (x uint128) rsh52() uint128 {
+	return uint128{lo: x.lo>>52 | x.hi<<12, hi: x.hi >> 52}
+}
+
+func (x uint128) rsh64() uint128 {
+	return uint128{lo: x.hi, hi: 0}
+}
+
+// ctEq returns 1 if a == b and 0 otherwise, for a, b < 2^63.
+func ctEq(a, b uint64) uint64 {
+	return ((a ^ b) - 1) >> 63
+}
+
+// ctGe returns 1 if a >= b and 0 otherwise, for a, b < 2^63.
+func ctGe(a, b uint64) uint64 {
+	return 1 ^ ((a - b) >> 63)
+}
+
+func (r *fe52) setUint64(v uint64) *fe52 {
+	r[0] = v & fe52Mask
+	r[1] = v >> 52
+	r[2], r[3], r[4] = 0, 0, 0
+	return r
+}
+
+// setBytes sets r from a 32 byte big-endian encoding and returns 1 if the
+// encoded integer was smaller than p. The value is always loaded, reduced
+// or not, so the caller decides how to treat overflow.
+func (r *fe52) setBytes(b *[32]byte) uint64 {
+	r[0] = uint64(b[31]) | uint64(b[30])<<8 | uint64(b[29])<<16 | uint64(b[28])<<24 | uint64(b[27])<<32 | uint64(b[26])<<40 | uint64(b[25]&0xF)<<48
+	r[1] = uint64(b[25]>>4) | uint64(b[24])<<4 | uint64(b[23])<<12 | uint64(b[22])<<20 | uint64(b[21])<<28 | uint64(b[20])<<36 | uint64(b[19])<<44
+	r[2] = uint64(b[18]) | uint64(b[17])<<8 | uint64(b[16])<<16 | uint64(b[15])<<24 | uint64(b[14])<<32 | uint64(b[13])<<40 | uint64(b[12]&0xF)<<48
+	r[3] = uint64(b[12]>>4) | uint64(b[11])<<4 | uint64(b[10])<<12 | uint64(b[9])<<20 | uint64(b[8])<<28 | uint64(b[7])<<36 | uint64(b[6])<<44
+	r[4] = uint64(b[5]) | uint64(b[4])<<8 | uint64(b[3])<<16 | uint64(b[2])<<24 | uint64(b[1])<<32 | uint64(b[0])<<40
+	overflow := ctEq(r[4], fe52Mask4) & ctEq(r[3]&r[2]&r[1], fe52Mask) & ctGe(r[0], fe52P0)
+	return overflow ^ 1
+}
+
+// bytes returns the 32 byte big-endian encoding of a normalized element.
+func (r *fe52) bytes() [32]byte {
+	var b [32]byte
+	b[0] = byte(r[4] >> 40)
+	b[1] = byte(r[4] >> 32)
+	b[2] = byte(r[4] >> 24)
+	b[3] = byte(r[4] >> 16)
+	b[4] = byte(r[4] >> 8)
+	b[5] = byte(r[4])
+	b[6] = byte(r[3] >> 44)
+	b[7] = byte(r[3] >> 36)
+	b[8] = byte(r[3] >> 28)
+	b[9] = byte(r[3] >> 20)
+	b[10] = byte(r[3] >> 12)
+	b[11] = byte(r[3] >> 4)
+	b[12] = byte(r[2]>>48)&0xF | byte(r[3]&0xF)<<4
+	b[13] = byte(r[2] >> 40)
+	b[14] = byte(r[2] >> 32)
+	b[15] = byte(r[2] >> 24)
+	b[16] = byte(r[2] >> 16)
+	b[17] = byte(r[2] >> 8)
+	b[18] = byte(r[2])
+	b[19] = byte(r[1] >> 44)
+	b[20] = byte(r[1] >> 36)
+	b[21] = byte(r[1] >> 28)
+	b[22] = byte(r[1] >> 20)
+	b[23] = byte(r[1] >> 12)
+	b[24] = byte(r[1] >> 4)
+	b[25] = byte(r[0]>>48)&0xF | byte(r[1]&0xF)<<4
+	b[26] = byte(r[0] >> 40)
+	b[27] = byte(r[0] >> 32)
+	b[28] = byte(r[0] >> 24)
+	b[29] = byte(r[0] >> 16)
+	b[30] = byte(r[0] >> 8)
+	b[31] = byte(r[0])
+	return b
+}
+
+// normalizeWeak propagates carries so that every limb fits in 52 bits.
+func (r *fe52) normalizeWeak() *fe52 {
+	t0, t1, t2, t3, t4 := r[0], r[1], r[2], r[3], r[4]
+	x := t4 >> 48
+	t4 &= fe52Mask4
+	t0 += x * fe52C
+	t1 += t0 >> 52
+	t0 &= fe52Mask
+	t2 += t1 >> 52
+	t1 &= fe52Mask
+	t3 += t2 >> 52
+	t2 &= fe52Mask
+	t4 += t3 >> 52
+	t3 &= fe52Mask
+	r[0], r[1], r[2], r[3], r[4] = t0, t1, t2, t3, t4
+	return r
+}
+
+// normalize fully reduces r modulo p.
+func (r *fe52) normalize() *fe52 {
+	t0, t1, t2, t3, t4 := r[0], r[1], r[2], r[3], r[4]
+	x := t4 >> 48
+	t4 &= fe52Mask4
+	t0 += x * fe52C
+	t1 += t0 >> 52
+	t0 &= fe52Mask
+	t2 += t1 >> 52
+	t1 &= fe52Mask
+	m := t1
+	t3 += t2 >> 52
+	t2 &= fe52Mask
+	m &= t2
+	t4 += t3 >> 52
+	t3 &= fe52Mask
+	m &= t3
+	x = (t4 >> 48) | (ctEq(t4, fe52Mask4) & ctEq(m, fe52Mask) & ctGe(t0, fe52P0))
+	t0 += x * fe52C
+	t1 += t0 >> 52
+	t0 &= fe52Mask
+	t2 += t1 >> 52
+	t1 &= fe52Mask
+	t3 += t2 >> 52
+	t2 &= fe52Mask
+	t4 += t3 >> 52
+	t3 &= fe52Mask
+	t4 &= fe52Mask4
+	r[0], r[1], r[2], r[3], r[4] = t0, t1, t2, t3, t4
+	return r
+}
+
+func (r *fe52) add(a, b *fe52) *fe52 {
+	r[0] = a[0] + b[0]
+	r[1] = a[1] + b[1]
+	r[2] = a[2] + b[2]
+	r[3] = a[3] + b[3]
+	r[4] = a[4] + b[4]
+	return r.normalizeWeak()
+}
+
+// neg sets r = -a for a weakly normalized a.
+func (r *fe52) neg(a *fe52) *fe52 {
+	r[0] = fe52P0*4 - a[0]
+	r[1] = fe52Mask*4 - a[1]
+	r[2] = fe52Mask*4 - a[2]
+	r[3] = fe52Mask*4 - a[3]
+	r[4] = fe52Mask4*4 - a[4]
+	return r.normalizeWeak()
+}
+
+func (r *fe52) sub(a, b *fe52) *fe52 {
+	var nb fe52
+	nb.neg(b)
+	return r.add(a, &nb)
+}
+
+func (r *fe52) mul(a, b *fe52) *fe52 {
+	a0, a1, a2, a3, a4 := a[0], a[1], a[2], a[3], a[4]
+	b0, b1, b2, b3, b4 := b[0], b[1], b[2], b[3], b[4]
+
+	d := mul64(a0, b3).addMul(a1, b2).addMul(a2, b1).addMul(a3, b0)
+	c := mul64(a4, b4)
+	d = d.addMul(fe52R, c.lo)
+	c = c.rsh64()
+	t3 := d.lo & fe52Mask
+	d = d.rsh52()
+
+	d = d.addMul(a0, b4).addMul(a1, b3).addMul(a2, b2).addMul(a3, b1).addMul(a4, b0)
+	d = d.addMul(fe52R<<12, c.lo)
+	t4 := d.lo & fe52Mask
+	d = d.rsh52()
+	tx := t4 >> 48
+	t4 &= fe52Mask >> 4
+
+	c = mul64(a0, b0)
+	d = d.addMul(a1, b4).addMul(a2, b3).addMul(a3, b2).addMul(a4, b1)
+	u0 := d.lo & fe52Mask
+	d = d.rsh52()
+	u0 = (u0 << 4) | tx
+	c = c.addMul(u0, fe52R>>4)
+	r0 := c.lo & fe52Mask
+	c = c.rsh52()
+
+	c = c.addMul(a0, b1).addMul(a1, b0)
+	d = d.addMul(a2, b4).addMul(a3, b3).addMul(a4, b2)
+	c = c.addMul(d.lo&fe52Mask, fe52R)
+	d = d.rsh52()
+	r1 := c.lo & fe52Mask
+	c = c.rsh52()
+
+	c = c.addMul(a0, b2).addMul(a1, b1).addMul(a2, b0)
+	d = d.addMul(a3, b4).addMul(a4, b3)
+	c = c.addMul(fe52R, d.lo)
+	d = d.rsh64()
+	r2 := c.lo & fe52Mask
+	c = c.rsh52()
+
+	c = c.addMul(fe52R<<12, d.lo).addU64(t3)
+	r3 := c.lo & fe52Mask
+	c = c.rsh52()
+	r4 := c.lo + t4
+
+	r[0], r[1], r[2], r[3], r[4] = r0, r1, r2, r3, r4
+	return r
+}
+
+func (r *fe52) square(a *fe52) *fe52 {
+	return r.mul(a, a)
+}
+
+func (r *fe52) sqrN(a *fe52, n int) *fe52 {
+	r.square(a)
+	for i := 1; i < n; i++ {
+		r.square(r)
+	}
+	return r
+}
+
+// powChain computes the common prefix of the inversion and square root
+// addition chains, returning a^(2^2-1), a^(2^22-1) and a^(2^223-1).
+func (a *fe52) powChain() (x2, x22, x223 fe52) {
+	var x3, x6, x9, x11, x44, x88, x176, x220 fe52
+	x2.square(a)
+	x2.mul(&x2, a)
+	x3.square(&x2)
+	x3.mul(&x3, a)
+	x6.sqrN(&x3, 3)
+	x6.mul(&x6, &x3)
+	x9.sqrN(&x6, 3)
+	x9.mul(&x9, &x3)
+	x11.sqrN(&x9, 2)
+	x11.mul(&x11, &x2)
+	x22.sqrN(&x11, 11)
+	x22.mul(&x22, &x11)
+	x44.sqrN(&x22, 22)
+	x44.mul(&x44, &x22)
+	x88.sqrN(&x44, 44)
+	x88.mul(&x88, &x44)
+	x176.sqrN(&x88, 88)
+	x176.mul(&x176, &x88)
+	x220.sqrN(&x176, 44)
+	x220.mul(&x220, &x44)
+	x223.sqrN(&x220, 3)
+	x223.mul(&x223, &x3)
+	return x2, x22, x223
+}
+
+// invert sets r = a^(p-2), which is zero when a is zero.
+func (r *fe52) invert(a *fe52) *fe52 {
+	x2, x22, t := a.powChain()
+	t.sqrN(&t, 23)
+	t.mul(&t, &x22)
+	t.sqrN(&t, 5)
+	t.mul(&t, a)
+	t.sqrN(&t, 3)
+	t.mul(&t, &x2)
+	t.sqrN(&t, 2)
+	t.mul(&t, a)
+	*r = t
+	return r
+}
+
+// sqrt sets r = a^((p+1)/4) and returns 1 if r is a square root of a.
+func (r *fe52) sqrt(a *fe52) int {
+	x2, x22, t := a.powChain()
+	t.sqrN(&t, 23)
+	t.mul(&t, &x22)
+	t.sqrN(&t, 6)
+	t.mul(&t, &x2)
+	t.sqrN(&t, 2)
+	var check fe52
+	check.square(&t)
+	*r = t
+	return check.equal(a)
+}
+
+// fe52ProgenitorExp is (p-3)/4 as little-endian 64-bit words.
+var fe52ProgenitorExp = [4]uint64{0xFFFFFFFFBFFFFF0B, 0xFFFFFFFFFFFFFFFF, 0xFFFFFFFFFFFFFFFF, 0x3FFFFFFFFFFFFFFF}
+
+// progenitor sets r = a^((p-3)/4). The exponent is public, so the square and
+// multiply ladder leaks nothing about a.
+func (r *fe52) progenitor(a *fe52) *fe52 {
+	var t fe52
+	t.setUint64(1)
+	for i := 3; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			t.square(&t)
+			if (fe52ProgenitorExp[i]>>uint(j))&1 == 1 {
+				t.mul(&t, a)
+			}
+		}
+	}
+	*r = t
+	return r
+}
+
+func (r *fe52) cmov(a *fe52, flag int) {
+	mask := -uint64(flag & 1)
+	r[0] ^= mask & (r[0] ^ a[0])
+	r[1] ^= mask & (r[1] ^ a[1])
+	r[2] ^= mask & (r[2] ^ a[2])
+	r[3] ^= mask & (r[3] ^ a[3])
+	r[4] ^= mask & (r[4] ^ a[4])
+}
+
+func (r *fe52) isZero() int {
+	t := *r
+	t.normalize()
+	return int(ctEq(t[0]|t[1]|t[2]|t[3]|t[4], 0))
+}
+
+func (r *fe52) equal(a *fe52) int {
+	var d fe52
+	d.sub(r, a)
+	return d.isZero()
+}
+
+func (r *fe52) isOdd() uint8 {
+	t := *r
+	t.normalize()
+	return uint8(t[0] & 1)
+}
@@ -25,6 +25,6 @@ func TestSecp256k1(t *testing.T) {
 	assert.Equal(t, toString(a), "0xc078ce542299e5235f75aaa70f0a2d8d16ac2c9c3490f1a2bfabb83ee5bbb6fc")
 	a, _ = Field.SswuA(K256).Sqrt(Field.SswuA(K256))
 	assert.Equal(t, toString(a), "0x0000000000000000000000000000000000000000000000000000000000000000")
-	//a = Field.SswuA(K256).Progenitor(Field.SswuA(K256))
-	//assert.Equal(t, toString(a), "0x5a839b0f169007abed6a6312e3fffed8db39a7678938f006afc3b389f61d71de")
+	a = Field.SswuA(K256).Progenitor(Field.SswuA(K256))
+	assert.Equal(t, toString(a), "0x5a839b0f169007abed6a6312e3fffed8db39a7678938f006afc3b389f61d71de")
 }
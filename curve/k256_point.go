@@ -6,6 +6,7 @@ import (
 	"github.com/coinbase/kryptology/pkg/core/curves/native/k256"
 	"github.com/coinbase/kryptology/pkg/core/curves/native/k256/fp"
 	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
 	"math/big"
 	"math/bits"
 )
@@ -53,6 +54,9 @@ func (e EccK256Point) fromUncompressed(xb, yb []byte) (*Secp256k1Point, error) {
 	value.X = x
 	value.Y = y
 	value.Z.SetOne()
+	if !value.IsOnCurve() {
+		return nil, errors.New("invalid point, not on the curve")
+	}
 	return &Secp256k1Point{point: value}, nil
 }
 func (e EccK256Point) decompress(bytes []byte, sign int) (*Secp256k1Point, error) {
@@ -74,6 +78,8 @@ func (e EccK256Point) decompress(bytes []byte, sign int) (*Secp256k1Point, error
 		value.X = x
 		value.Y = y
 		value.Z.SetOne()
+	} else {
+		return nil, errors.New("invalid point, x is not on the curve")
 	}
 	return &Secp256k1Point{point: value}, nil
 }
@@ -101,24 +107,57 @@ func (s Secp256k1Point) CurveType() EccCurveType {
 
 func (s *Secp256k1Point) AddPoints(lhs, rhs EccPoint) EccPoint {
 	l := lhs.(*Secp256k1Point)
-	r := rhs.(*Secp256k1Point)
-	s.point.Add(l.point, r.point)
+	r := s.unaliased(rhs.(*Secp256k1Point))
+	s.point.Add(l.point, r)
 	return s
 }
 
 func (s *Secp256k1Point) SubPoints(lhs, rhs EccPoint) EccPoint {
 	l := lhs.(*Secp256k1Point)
-	r := rhs.(*Secp256k1Point)
-	s.point.Sub(l.point, r.point)
+	r := s.unaliased(rhs.(*Secp256k1Point))
+	s.point.Sub(l.point, r)
 	return s
 }
 
+// unaliased returns the native point of an operand. The native Add and Sub
+// overwrite the receiver with lhs before reading rhs, so an rhs sharing the
+// receiver's storage is copied first.
+func (s *Secp256k1Point) unaliased(other *Secp256k1Point) *native.EllipticPoint {
+	if other.point == s.point {
+		return k256.K256PointNew().Set(other.point)
+	}
+	return other.point
+}
+
+func (s *Secp256k1Point) Plus(other EccPoint) EccPoint {
+	return s.Clone().AddPoints(s, other)
+}
+
+func (s *Secp256k1Point) Minus(other EccPoint) EccPoint {
+	return s.Clone().SubPoints(s, other)
+}
+
+func (s *Secp256k1Point) Times(scalar EccScalar) EccPoint {
+	return s.Clone().ScalarMul(s, scalar)
+}
+
+func (s *Secp256k1Point) Doubled() EccPoint {
+	return s.Clone().Double(s)
+}
+
 func (s *Secp256k1Point) Double(other EccPoint) EccPoint {
 	o := other.(*Secp256k1Point)
 	s.point.Double(o.point)
 	return s
 }
 
+func (s *Secp256k1Point) CAssign(other EccPoint, choice int) {
+	o := other.(*Secp256k1Point)
+	s.point.X.CMove(s.point.X, o.point.X, choice)
+	s.point.Y.CMove(s.point.Y, o.point.Y, choice)
+	s.point.Z.CMove(s.point.Z, o.point.Z, choice)
+}
+
 func (s Secp256k1Point) Clone() EccPoint {
 	return &Secp256k1Point{
 		point: k256.K256PointNew().Set(s.point),
@@ -128,7 +167,7 @@ func (s Secp256k1Point) Clone() EccPoint {
 func (s *Secp256k1Point) ScalarMul(other EccPoint, scalar EccScalar) EccPoint {
 	o := other.(*Secp256k1Point)
 	f := scalar.(*Secp256k1Scalar)
-	s.point.Mul(o.point, f.scalar)
+	s.point.Mul(o.point, f.native())
 	return s
 }
 func (s *Secp256k1Point) MulByNodeIndex(scalar common.NodeIndex) EccPoint {
@@ -146,23 +185,25 @@ func (s *Secp256k1Point) MulByNodeIndex(scalar common.NodeIndex) EccPoint {
 
 func (s *Secp256k1Point) LinComb(pt1 EccPoint, scalar1 EccScalar, pt2 EccPoint, scalar2 EccScalar) EccPoint {
 
-	s.point.SumOfProducts([]*native.EllipticPoint{pt1.(*Secp256k1Point).point, pt2.(*Secp256k1Point).point}, []*native.Field{scalar1.(*Secp256k1Scalar).scalar, scalar2.(*Secp256k1Scalar).scalar})
+	s.point.SumOfProducts([]*native.EllipticPoint{pt1.(*Secp256k1Point).point, pt2.(*Secp256k1Point).point}, []*native.Field{scalar1.(*Secp256k1Scalar).native(), scalar2.(*Secp256k1Scalar).native()})
 	return s
 }
 
 func (s Secp256k1Point) encodePoint(compress bool) []byte {
-	p := k256.K256PointNew().ToAffine(s.point)
-	x := &Secp256k1Field{field: p.GetX()}
-	y := &Secp256k1Field{field: p.GetY()}
-	choice := 0
-	if s.point.IsIdentity() {
-		choice = 1
+	if s.point.Z.IsOne() == 1 {
+		return encodeAffine(s.point, compress)
 	}
-	encode := Encode.ConditionalSelect(Encode.FromAffineCoordinates(x.AsBytes(), y.AsBytes(), compress), Encode.Identity(), choice)
-	result := make([]byte, 33, 33)
-	copy(result[0:encode.Len()], encode.AsBytes())
-	return result
+	return encodeAffine(k256.K256PointNew().ToAffine(s.point), compress)
 }
+
+func (s Secp256k1Point) nativePoint() *native.EllipticPoint {
+	return s.point
+}
+
+func (s Secp256k1Point) withNative(pt *native.EllipticPoint) EccPoint {
+	return &Secp256k1Point{point: pt}
+}
+
 func (s Secp256k1Point) Serialize() []byte {
 	return s.encodePoint(true)
 }
@@ -188,11 +229,11 @@ func (s Secp256k1Point) SerializeUncompressed() []byte {
 }
 
 func (s Secp256k1Point) AffineX() EccFieldElement {
-	return &Secp256k1Field{field: s.point.GetX()}
+	return K256Field.fromNative(s.point.GetX())
 }
 
 func (s Secp256k1Point) AffineY() EccFieldElement {
-	return &Secp256k1Field{field: s.point.GetY()}
+	return K256Field.fromNative(s.point.GetY())
 }
 
 func (s Secp256k1Point) IsInfinity() bool {
@@ -0,0 +1,218 @@
+package curve
+
+import (
+	"math/bits"
+)
+
+// sc64 is an element of the secp256k1 scalar field held as four 64-bit
+// little-endian limbs in Montgomery form (a*2^256 mod n). Every value is
+// kept fully reduced and all operations run in constant time.
+type sc64 [4]uint64
+
+var (
+	sc64N = sc64{0xBFD25E8CD0364141, 0xBAAEDCE6AF48A03B, 0xFFFFFFFFFFFFFFFE, 0xFFFFFFFFFFFFFFFF}
+	// 2^256 mod n, i.e. one in Montgomery form
+	sc64R1 = sc64{0x402DA1732FC9BEBF, 0x4551231950B75FC4, 0x0000000000000001, 0x0000000000000000}
+	// 2^512 mod n
+	sc64R2 = sc64{0x896CF21467D7D140, 0x741496C20E7CF878, 0xE697F5E45BCD07C6, 0x9D671CD581C69BC5}
+	// n - 2, the Fermat inversion exponent
+	sc64NMinus2 = [4]uint64{0xBFD25E8CD036413F, 0xBAAEDCE6AF48A03B, 0xFFFFFFFFFFFFFFFE, 0xFFFFFFFFFFFFFFFF}
+	// floor(n / 2)
+	sc64Half = sc64{0xDFE92F46681B20A0, 0x5D576E7357A4501D, 0xFFFFFFFFFFFFFFFF, 0x7FFFFFFFFFFFFFFF}
+)
+
+// -n^-1 mod 2^64
+const sc64N0 = 0x4B0DFF665588B13F
+
+// subBorrow returns a - b and the final borrow.
+func (a *sc64) subBorrow(b *sc64) (sc64, uint64) {
+	var r sc64
+	var borrow uint64
+	r[0], borrow = bits.Sub64(a[0], b[0], 0)
+	r[1], borrow = bits.Sub64(a[1], b[1], borrow)
+	r[2], borrow = bits.Sub64(a[2], b[2], borrow)
+	r[3], borrow = bits.Sub64(a[3], b[3], borrow)
+	return r, borrow
+}
+
+// reduce subtracts n once if the 257-bit value carry*2^256 + a is at
+// least n.
+func (r *sc64) reduce(a *sc64, carry uint64) *sc64 {
+	t, borrow := a.subBorrow(&sc64N)
+	_, borrow = bits.Sub64(carry, 0, borrow)
+	// borrow is 1 when a < n, in which case a is kept
+	r.cmov(a, &t, int(borrow))
+	return r
+}
+
+// cmov sets r to b when flag is 1 and to a when flag is 0.
+func (r *sc64) cmov(b, a *sc64, flag int) {
+	mask := -uint64(flag & 1)
+	r[0] = a[0] ^ (mask & (a[0] ^ b[0]))
+	r[1] = a[1] ^ (mask & (a[1] ^ b[1]))
+	r[2] = a[2] ^ (mask & (a[2] ^ b[2]))
+	r[3] = a[3] ^ (mask & (a[3] ^ b[3]))
+}
+
+func (r *sc64) add(a, b *sc64) *sc64 {
+	var t sc64
+	var carry uint64
+	t[0], carry = bits.Add64(a[0], b[0], 0)
+	t[1], carry = bits.Add64(a[1], b[1], carry)
+	t[2], carry = bits.Add64(a[2], b[2], carry)
+	t[3], carry = bits.Add64(a[3], b[3], carry)
+	return r.reduce(&t, carry)
+}
+
+func (r *sc64) sub(a, b *sc64) *sc64 {
+	t, borrow := a.subBorrow(b)
+	mask := -borrow
+	var carry uint64
+	t[0], carry = bits.Add64(t[0], sc64N[0]&mask, 0)
+	t[1], carry = bits.Add64(t[1], sc64N[1]&mask, carry)
+	t[2], carry = bits.Add64(t[2], sc64N[2]&mask, carry)
+	t[3], _ = bits.Add64(t[3], sc64N[3]&mask, carry)
+	*r = t
+	return r
+}
+
+func (r *sc64) neg(a *sc64) *sc64 {
+	var zero sc64
+	return r.sub(&zero, a)
+}
+
+// montMul sets r = a*b/2^256 mod n using coarsely integrated operand
+// scanning. Inputs must be below 2^256 and at least one below n.
+func (r *sc64) montMul(a, b *sc64) *sc64 {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var c, hi, lo uint64
+		// t += a * b[i]
+		for j := 0; j < 4; j++ {
+			hi, lo = bits.Mul64(a[j], b[i])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j] = lo
+			c = hi
+		}
+		var cc uint64
+		t[4], cc = bits.Add64(t[4], c, 0)
+		t[5] = cc
+
+		// t = (t + m*n) / 2^64
+		m := t[0] * sc64N0
+		hi, lo = bits.Mul64(m, sc64N[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < 4; j++ {
+			hi, lo = bits.Mul64(m, sc64N[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1] = lo
+			c = hi
+		}
+		t[3], cc = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + cc
+	}
+	res := sc64{t[0], t[1], t[2], t[3]}
+	return r.reduce(&res, t[4])
+}
+
+func (r *sc64) square(a *sc64) *sc64 {
+	return r.montMul(a, a)
+}
+
+// toMont converts a plain integer below 2^256 into Montgomery form,
+// reducing it modulo n.
+func (r *sc64) toMont(a *sc64) *sc64 {
+	return r.montMul(a, &sc64R2)
+}
+
+// fromMont converts out of Montgomery form.
+func (r *sc64) fromMont(a *sc64) *sc64 {
+	one := sc64{1}
+	return r.montMul(a, &one)
+}
+
+// setBytes loads a 32 byte big-endian integer in Montgomery form and
+// returns 1 if it was smaller than n.
+func (r *sc64) setBytes(b *[32]byte) int {
+	var t sc64
+	for i := 0; i < 4; i++ {
+		off := 24 - 8*i
+		t[i] = uint64(b[off])<<56 | uint64(b[off+1])<<48 | uint64(b[off+2])<<40 | uint64(b[off+3])<<32 |
+			uint64(b[off+4])<<24 | uint64(b[off+5])<<16 | uint64(b[off+6])<<8 | uint64(b[off+7])
+	}
+	_, borrow := t.subBorrow(&sc64N)
+	r.toMont(&t)
+	return int(borrow)
+}
+
+// setBytesWide reduces a 64 byte big-endian integer modulo n.
+func (r *sc64) setBytesWide(b *[64]byte) *sc64 {
+	var hi, lo sc64
+	hi.setBytes((*[32]byte)(b[:32]))
+	lo.setBytes((*[32]byte)(b[32:]))
+	// hi is in Montgomery form already, one more factor of 2^256 gives hi*2^256
+	hi.montMul(&hi, &sc64R2)
+	return r.add(&hi, &lo)
+}
+
+// bytes returns the 32 byte big-endian encoding.
+func (r *sc64) bytes() [32]byte {
+	var t sc64
+	t.fromMont(r)
+	var b [32]byte
+	for i := 0; i < 4; i++ {
+		off := 24 - 8*i
+		v := t[i]
+		b[off] = byte(v >> 56)
+		b[off+1] = byte(v >> 48)
+		b[off+2] = byte(v >> 40)
+		b[off+3] = byte(v >> 32)
+		b[off+4] = byte(v >> 24)
+		b[off+5] = byte(v >> 16)
+		b[off+6] = byte(v >> 8)
+		b[off+7] = byte(v)
+	}
+	return b
+}
+
+// invert sets r = a^(n-2), which is zero when a is zero. The exponent is
+// public so the square and multiply ladder does not depend on a.
+func (r *sc64) invert(a *sc64) *sc64 {
+	t := sc64R1
+	for i := 3; i >= 0; i-- {
+		for j := 63; j >= 0; j-- {
+			t.square(&t)
+			if (sc64NMinus2[i]>>uint(j))&1 == 1 {
+				t.montMul(&t, a)
+			}
+		}
+	}
+	*r = t
+	return r
+}
+
+func (r *sc64) isZero() int {
+	d := r[0] | r[1] | r[2] | r[3]
+	return int(((d | -d) >> 63) ^ 1)
+}
+
+func (r *sc64) equal(a *sc64) int {
+	d := (r[0] ^ a[0]) | (r[1] ^ a[1]) | (r[2] ^ a[2]) | (r[3] ^ a[3])
+	return int(((d | -d) >> 63) ^ 1)
+}
+
+// isHigh returns 1 if the scalar is greater than floor(n/2).
+func (r *sc64) isHigh() int {
+	var t sc64
+	t.fromMont(r)
+	_, borrow := sc64Half.subBorrow(&t)
+	return int(borrow)
+}
@@ -1,60 +1,57 @@
 package curve
 
 import (
-	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/coinbase/kryptology/pkg/core/curves/native"
 	"github.com/coinbase/kryptology/pkg/core/curves/native/k256/fq"
 	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
 	"math/big"
 )
 
 var (
 	K256Scalar = EccK256Scalar{}
 	GroupOrder = fromHex("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141")
-	OrderHalf  = fq.K256FqNew().SetBigInt(new(big.Int).Div(GroupOrder, big.NewInt(2)))
+	OrderHalf  = new(big.Int).Div(GroupOrder, big.NewInt(2))
 )
 
 type EccK256Scalar struct{}
 type Secp256k1Scalar struct {
-	scalar *native.Field
+	scalar sc64
 }
 
 func (EccK256Scalar) Deserialize(bytes []byte) (*Secp256k1Scalar, error) {
+	if len(bytes) > 32 {
+		return nil, errors.New("invalid length of scalar")
+	}
 	var buf [32]byte
-	copy(buf[:], common.ReverseBytes(bytes))
-	field, err := fq.K256FqNew().SetBytes(&buf)
-	if err != nil {
-		return nil, err
+	copy(buf[32-len(bytes):], bytes)
+	s := &Secp256k1Scalar{}
+	if s.scalar.setBytes(&buf) != 1 {
+		return nil, errors.New("invalid scalar")
 	}
-	return &Secp256k1Scalar{
-		scalar: field,
-	}, nil
+	return s, nil
 }
 func (EccK256Scalar) Zero() *Secp256k1Scalar {
-	return &Secp256k1Scalar{
-		scalar: fq.K256FqNew().SetZero(),
-	}
+	return &Secp256k1Scalar{}
 }
 
 func (EccK256Scalar) One() *Secp256k1Scalar {
-	return &Secp256k1Scalar{
-		scalar: fq.K256FqNew().SetOne(),
-	}
+	return &Secp256k1Scalar{scalar: sc64R1}
 }
 
 func (EccK256Scalar) FromUint64(n uint64) *Secp256k1Scalar {
-	return &Secp256k1Scalar{
-		scalar: fq.K256FqNew().SetUint64(n),
-	}
+	s := &Secp256k1Scalar{}
+	v := sc64{n}
+	s.scalar.toMont(&v)
+	return s
 }
 
 func (EccK256Scalar) FromWideBytes(bytes []byte) *Secp256k1Scalar {
 	var r [64]byte
-	copy(r[:len(bytes)], common.ReverseBytes(bytes))
-
-	return &Secp256k1Scalar{
-		scalar: fq.K256FqNew().SetBytesWide(&r),
-	}
+	copy(r[64-len(bytes):], bytes)
+	s := &Secp256k1Scalar{}
+	s.scalar.setBytesWide(&r)
+	return s
 }
 
 func (s Secp256k1Scalar) CurveType() EccCurveType {
@@ -63,44 +60,44 @@ func (s Secp256k1Scalar) CurveType() EccCurveType {
 func (s *Secp256k1Scalar) Add(lhs, rhs EccScalar) EccScalar {
 	l := lhs.(*Secp256k1Scalar)
 	r := rhs.(*Secp256k1Scalar)
-	s.scalar.Add(l.scalar, r.scalar)
+	s.scalar.add(&l.scalar, &r.scalar)
 	return s
 }
 
 func (s *Secp256k1Scalar) Sub(lhs, rhs EccScalar) EccScalar {
 	l := lhs.(*Secp256k1Scalar)
 	r := rhs.(*Secp256k1Scalar)
-	s.scalar.Sub(l.scalar, r.scalar)
+	s.scalar.sub(&l.scalar, &r.scalar)
 	return s
 }
 
 func (s *Secp256k1Scalar) Mul(lhs, rhs EccScalar) EccScalar {
 	l := lhs.(*Secp256k1Scalar)
 	r := rhs.(*Secp256k1Scalar)
-	s.scalar.Mul(l.scalar, r.scalar)
+	s.scalar.montMul(&l.scalar, &r.scalar)
 	return s
 }
 
 func (s *Secp256k1Scalar) Invert(other EccScalar) EccScalar {
 	o := other.(*Secp256k1Scalar)
-	s.scalar.Invert(o.scalar)
+	s.scalar.invert(&o.scalar)
 	return s
 }
 
 func (s *Secp256k1Scalar) Negate(other EccScalar) EccScalar {
 	o := other.(*Secp256k1Scalar)
-	s.scalar.Neg(o.scalar)
+	s.scalar.neg(&o.scalar)
 	return s
 }
 
 func (s *Secp256k1Scalar) Equal(other EccScalar) int {
 	o := other.(*Secp256k1Scalar)
 
-	return s.scalar.Equal(o.scalar)
+	return s.scalar.equal(&o.scalar)
 }
 func (s *Secp256k1Scalar) Assign(other EccScalar) EccScalar {
 	o := other.(*Secp256k1Scalar)
-	s.scalar.Set(o.scalar)
+	s.scalar = o.scalar
 	return s
 }
 
@@ -108,9 +105,33 @@ func (s *Secp256k1Scalar) Clone() EccScalar {
 	return K256Scalar.Zero().Assign(s)
 }
 
+func (s *Secp256k1Scalar) Plus(other EccScalar) EccScalar {
+	return K256Scalar.Zero().Add(s, other)
+}
+
+func (s *Secp256k1Scalar) Minus(other EccScalar) EccScalar {
+	return K256Scalar.Zero().Sub(s, other)
+}
+
+func (s *Secp256k1Scalar) Times(other EccScalar) EccScalar {
+	return K256Scalar.Zero().Mul(s, other)
+}
+
+func (s *Secp256k1Scalar) Inverse() EccScalar {
+	return K256Scalar.Zero().Invert(s)
+}
+
+func (s *Secp256k1Scalar) Negated() EccScalar {
+	return K256Scalar.Zero().Negate(s)
+}
+
+func (s *Secp256k1Scalar) Zeroize() {
+	s.scalar = sc64{}
+}
+
 func (s Secp256k1Scalar) Serialize() []byte {
-	bytes := s.scalar.Bytes()
-	return common.ReverseBytes(bytes[:])
+	bytes := s.scalar.bytes()
+	return bytes[:]
 }
 
 func (s Secp256k1Scalar) SerializeTagged() []byte {
@@ -121,12 +142,11 @@ func (s Secp256k1Scalar) SerializeTagged() []byte {
 }
 
 func (s Secp256k1Scalar) IsZero() int {
-	return s.scalar.IsZero()
+	return s.scalar.isZero()
 }
 
 func (s Secp256k1Scalar) IsHigh() bool {
-	return s.scalar.Cmp(OrderHalf) > 0
-	return true
+	return s.scalar.isHigh() == 1
 }
 func (s Secp256k1Scalar) MarshalCBOR() ([]byte, error) {
 	return cbor.Marshal(s.SerializeTagged())
@@ -145,7 +165,15 @@ func (s *Secp256k1Scalar) UnmarshalCBOR(data []byte) error {
 	return nil
 }
 func (s Secp256k1Scalar) BigInt() *big.Int {
-	return s.scalar.BigInt()
+	return new(big.Int).SetBytes(s.Serialize())
+}
+
+// native converts to kryptology's representation for the point arithmetic.
+// Both hold little-endian limbs in Montgomery form with R = 2^256 mod n, so
+// the limbs are copied as they are.
+func (s Secp256k1Scalar) native() *native.Field {
+	limbs := [native.FieldLimbs]uint64(s.scalar)
+	return fq.K256FqNew().SetRaw(&limbs)
 }
 
 type Secp256K1ScalarBytes [32]byte
@@ -0,0 +1,103 @@
+package curve
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/pkg/errors"
+	"math/bits"
+)
+
+// MultiScalarMul returns sum(scalars[i] * points[i]) using the bucket method
+// of Pippenger. The running time depends on the scalars, so it must only be
+// used with public values such as Lagrange coefficients.
+func (p point) MultiScalarMul(points []EccPoint, scalars []EccScalar) (EccPoint, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("points and scalars have different lengths")
+	}
+	if len(points) == 0 {
+		return nil, errors.New("no points to multiply")
+	}
+	curveType := points[0].CurveType()
+	for i := range points {
+		if points[i].CurveType() != curveType || scalars[i].CurveType() != curveType {
+			return nil, common.ErrCurveMismatch
+		}
+	}
+	if len(points) == 1 {
+		return points[0].Clone().ScalarMul(points[0], scalars[0]), nil
+	}
+	if len(points) == 2 {
+		return p.MulPoints(points[0], scalars[0], points[1], scalars[1]), nil
+	}
+
+	encoded := make([][]byte, len(scalars))
+	maxBits := 0
+	for i, s := range scalars {
+		encoded[i] = s.Serialize()
+		if l := bitLen(encoded[i]); l > maxBits {
+			maxBits = l
+		}
+	}
+
+	window := msmWindowSize(len(points))
+	buckets := make([]EccPoint, (1<<window)-1)
+	result := p.Identity(curveType)
+	for w := (maxBits+window-1)/window - 1; w >= 0; w-- {
+		for i := 0; i < window; i++ {
+			result = result.Double(result)
+		}
+		for i := range buckets {
+			buckets[i] = p.Identity(curveType)
+		}
+		for i, pt := range points {
+			if d := windowDigit(encoded[i], w*window, window); d != 0 {
+				buckets[d-1] = buckets[d-1].AddPoints(buckets[d-1], pt)
+			}
+		}
+		// sum(d * bucket[d-1]) computed as a sum of running sums
+		running := p.Identity(curveType)
+		sum := p.Identity(curveType)
+		for i := len(buckets) - 1; i >= 0; i-- {
+			running = running.AddPoints(running, buckets[i])
+			sum = sum.AddPoints(sum, running)
+		}
+		result = result.AddPoints(result, sum)
+	}
+	return result, nil
+}
+
+// msmWindowSize picks the bucket window width in bits for n terms.
+func msmWindowSize(n int) int {
+	w := bits.Len(uint(n)) - 2
+	if w < 2 {
+		w = 2
+	}
+	if w > 16 {
+		w = 16
+	}
+	return w
+}
+
+// bitLen returns the bit length of a big-endian integer.
+func bitLen(b []byte) int {
+	for i, v := range b {
+		if v != 0 {
+			return (len(b)-i-1)*8 + bits.Len8(v)
+		}
+	}
+	return 0
+}
+
+// windowDigit extracts width bits of a big-endian integer starting at bit
+// offset from the least significant end.
+func windowDigit(b []byte, offset, width int) int {
+	d := 0
+	for i := width - 1; i >= 0; i-- {
+		bit := offset + i
+		idx := len(b) - 1 - bit/8
+		d <<= 1
+		if idx >= 0 {
+			d |= int(b[idx]>>(uint(bit)%8)) & 1
+		}
+	}
+	return d
+}
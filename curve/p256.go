@@ -0,0 +1,74 @@
+package curve
+
+import (
+	"encoding/asn1"
+	"encoding/hex"
+)
+
+func init() {
+	mustRegister(CurveDescriptor{
+		Type:          P256,
+		Name:          "secp256r1",
+		ScalarBits:    256,
+		FieldBits:     256,
+		SecurityLevel: 128,
+		OID:           asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7},
+		JWKName:       "P-256",
+
+		Identity:   func() EccPoint { return P256Point.Identity() },
+		GeneratorG: func() EccPoint { return P256Point.NewP256G() },
+		GeneratorH: func() EccPoint {
+			h, _ := hex.DecodeString("036774e87305efcb97c0ce289d57cd721972845ca33eccb8026c6d7c1c4182e7c1")
+			pt, err := P256Point.Deserialize(h)
+			if err != nil {
+				panic(err.Error())
+			}
+			return pt
+		},
+		DeserializePoint: func(bytes []byte) (EccPoint, error) {
+			pt, err := P256Point.Deserialize(bytes)
+			if err != nil {
+				return nil, err
+			}
+			return pt, nil
+		},
+		MulPoints: func(pt1 EccPoint, scalar1 EccScalar, pt2 EccPoint, scalar2 EccScalar) EccPoint {
+			return P256Point.NewP256().LinComb(pt1, scalar1, pt2, scalar2)
+		},
+
+		ScalarZero:       func() EccScalar { return P256Scalar.Zero() },
+		ScalarOne:        func() EccScalar { return P256Scalar.One() },
+		ScalarFromUint64: func(n uint64) EccScalar { return P256Scalar.FromUint64(n) },
+		DeserializeScalar: func(bytes []byte) (EccScalar, error) {
+			s, err := P256Scalar.Deserialize(bytes)
+			if err != nil {
+				return nil, err
+			}
+			return s, nil
+		},
+		ScalarFromBytesWide: func(bytes []byte) EccScalar { return P256Scalar.FromWideBytes(bytes) },
+		ScalarBytes: func(scalar EccScalar) EccScalarBytes {
+			var bytes Secp256R1ScalarBytes
+			copy(bytes[:], scalar.Serialize())
+			return bytes
+		},
+
+		FieldZero: func() EccFieldElement { return P256Field.Zero() },
+		FieldOne:  func() EccFieldElement { return P256Field.One() },
+		FieldA:    func() EccFieldElement { return P256Field.FieldA() },
+		FieldB:    func() EccFieldElement { return P256Field.FieldB() },
+		FieldFromBytes: func(bytes []byte) (EccFieldElement, error) {
+			fe, err := P256Field.FromBytes(bytes)
+			if err != nil {
+				return nil, err
+			}
+			return fe, nil
+		},
+		FieldFromBytesWide: func(bytes []byte) EccFieldElement { return P256Field.FromBytesWide(bytes) },
+
+		SswuA:  func() EccFieldElement { return P256Field.FieldSswuA() },
+		SswuB:  func() EccFieldElement { return P256Field.FieldSswuB() },
+		SswuZ:  func() EccFieldElement { return P256Field.FieldSswuZ() },
+		SswuC2: func() EccFieldElement { return P256Field.FieldSswuC2() },
+	})
+}
@@ -0,0 +1,175 @@
+package curve
+
+import (
+	"encoding/hex"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p256/fp"
+	"math/big"
+)
+
+var (
+	P256Field = EccP256Field{}
+)
+
+type EccP256Field struct{}
+
+type Secp256r1Field struct {
+	field *native.Field
+}
+
+func (e EccP256Field) FromHex(h string) *Secp256r1Field {
+	bytes, err := hex.DecodeString(h)
+	if err != nil {
+		panic(err.Error())
+	}
+	return &Secp256r1Field{field: fp.P256FpNew().SetBigInt(new(big.Int).SetBytes(bytes))}
+}
+func (EccP256Field) FromBytes(bytes []byte) (*Secp256r1Field, error) {
+	var r [32]byte
+	copy(r[:], common.ReverseBytes(bytes[:]))
+	field, err := fp.P256FpNew().SetBytes(&r)
+	if err != nil {
+		return nil, err
+	}
+	return &Secp256r1Field{
+		field: field,
+	}, nil
+}
+
+func (EccP256Field) FromBytesWide(bytes []byte) *Secp256r1Field {
+	var r [64]byte
+	copy(r[:], common.ReverseBytes(bytes[:]))
+	field := fp.P256FpNew().SetBytesWide(&r)
+	return &Secp256r1Field{
+		field: field,
+	}
+}
+
+func (EccP256Field) newField(b *big.Int) *Secp256r1Field {
+	return &Secp256r1Field{
+		field: fp.P256FpNew().SetBigInt(b),
+	}
+}
+
+func (EccP256Field) Zero() *Secp256r1Field {
+	return &Secp256r1Field{field: fp.P256FpNew().SetZero()}
+}
+
+func (EccP256Field) One() *Secp256r1Field {
+	return &Secp256r1Field{field: fp.P256FpNew().SetOne()}
+}
+
+func (k EccP256Field) FieldA() *Secp256r1Field {
+	return k.newField(fromHex("FFFFFFFF00000001000000000000000000000000FFFFFFFFFFFFFFFFFFFFFFFC"))
+}
+func (k EccP256Field) FieldB() *Secp256r1Field {
+	return k.newField(fromHex("5AC635D8AA3A93E7B3EBBD55769886BC651D06B0CC53B0F63BCE3C3E27D2604B"))
+}
+func (k EccP256Field) FieldSswuA() *Secp256r1Field {
+	return k.FieldA()
+}
+func (k EccP256Field) FieldSswuB() *Secp256r1Field {
+	return k.FieldB()
+}
+func (k EccP256Field) FieldSswuZ() *Secp256r1Field {
+	return k.newField(fromHex("FFFFFFFF00000001000000000000000000000000FFFFFFFFFFFFFFFFFFFFFFF5"))
+}
+func (k EccP256Field) FieldSswuC2() *Secp256r1Field {
+	return k.newField(fromHex("da538e3be1d89b99c978fc675180aab27b8d1ff84c55d5b62ccd3427e433c47f"))
+}
+
+func (s Secp256r1Field) CurveType() EccCurveType {
+	return P256
+}
+
+func (s *Secp256r1Field) Clone() EccFieldElement {
+	return P256Field.Zero().Assign(s)
+}
+
+func (s *Secp256r1Field) Assign(other EccFieldElement) EccFieldElement {
+	s.field.Set(other.(*Secp256r1Field).field)
+	return s
+}
+
+func (s *Secp256r1Field) Add(lhs, rhs EccFieldElement) EccFieldElement {
+	l := lhs.(*Secp256r1Field)
+	r := rhs.(*Secp256r1Field)
+	s.field.Add(l.field, r.field)
+	return s
+}
+
+func (s *Secp256r1Field) Sub(lhs, rhs EccFieldElement) EccFieldElement {
+	l := lhs.(*Secp256r1Field)
+	r := rhs.(*Secp256r1Field)
+	s.field.Sub(l.field, r.field)
+	return s
+}
+func (s *Secp256r1Field) Mul(lhs, rhs EccFieldElement) EccFieldElement {
+	l := lhs.(*Secp256r1Field)
+	r := rhs.(*Secp256r1Field)
+	s.field.Mul(l.field, r.field)
+	return s
+}
+
+func (s *Secp256r1Field) Square(other EccFieldElement) EccFieldElement {
+	s.field.Square(other.(*Secp256r1Field).field)
+	return s
+}
+
+func (s Secp256r1Field) Equal(other EccFieldElement) int {
+	return s.field.Equal(other.(*Secp256r1Field).field)
+}
+
+func (s *Secp256r1Field) CAssign(other EccFieldElement, choice int) {
+	s.field.CMove(s.field, other.(*Secp256r1Field).field, choice)
+}
+
+func (s *Secp256r1Field) Invert(other EccFieldElement) EccFieldElement {
+	s.field.Invert(other.(*Secp256r1Field).field)
+	return s
+}
+
+func (s *Secp256r1Field) Negate(other EccFieldElement) EccFieldElement {
+	s.field.Neg(other.(*Secp256r1Field).field)
+	return s
+}
+
+func (s *Secp256r1Field) Sqrt(other EccFieldElement) (EccFieldElement, int) {
+	_, flag := s.field.Sqrt(other.(*Secp256r1Field).field)
+	choice := 1
+	if !flag {
+		s.field.SetZero()
+		choice = 0
+	}
+	return s, choice
+}
+
+func (s *Secp256r1Field) Progenitor(other EccFieldElement) EccFieldElement {
+	m34 := new(big.Int).Sub(fp.P256FpNew().Params.BiModulus, big.NewInt(3))
+	m34.Div(m34, big.NewInt(4))
+	s.field.Exp(other.(*Secp256r1Field).field, fp.P256FpNew().SetBigInt(m34))
+	return s
+}
+
+func (s Secp256r1Field) IsZero() int {
+	return s.field.IsZero()
+}
+
+func (s Secp256r1Field) AsBytes() []byte {
+	bytes := s.field.BigInt().Bytes()
+	if len(bytes) > 32 {
+		panic("field element longer than 256 bits")
+	}
+	var rv [32]byte
+	copy(rv[32-len(bytes):], bytes) // leftpad w zeros
+	return rv[:]
+}
+
+func (s Secp256r1Field) Sign() uint8 {
+	bytes := s.field.Bytes()
+	return common.ReverseBytes(bytes[:])[native.FieldBytes-1] & 1
+}
+func (s Secp256r1Field) BigInt() *big.Int {
+	return s.field.BigInt()
+}
@@ -0,0 +1,252 @@
+package curve
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p256"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p256/fp"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/pkg/errors"
+	"math/big"
+	"math/bits"
+)
+
+var (
+	P256Point = EccP256Point{}
+)
+
+type EccP256Point struct{}
+
+type Secp256r1Point struct {
+	point *native.EllipticPoint
+}
+
+func (EccP256Point) NewP256() *Secp256r1Point {
+	return &Secp256r1Point{point: p256.P256PointNew()}
+}
+
+func (EccP256Point) NewP256G() *Secp256r1Point {
+	return &Secp256r1Point{point: p256.P256PointNew().Generator()}
+}
+
+func (EccP256Point) Identity() *Secp256r1Point {
+	return &Secp256r1Point{point: p256.P256PointNew().Identity()}
+}
+func (e EccP256Point) Deserialize(bytes []byte) (*Secp256r1Point, error) {
+	ec, err := Encode.FromBytes(bytes)
+	if err != nil {
+		return nil, err
+	}
+	return e.fromEncodedPoint(ec)
+}
+
+func (e EccP256Point) fromUncompressed(xb, yb []byte) (*Secp256r1Point, error) {
+	x := fp.P256FpNew().SetBigInt(new(big.Int).SetBytes(xb))
+
+	y := fp.P256FpNew().SetBigInt(new(big.Int).SetBytes(yb))
+
+	value := p256.P256PointNew()
+	value.X = x
+	value.Y = y
+	value.Z.SetOne()
+	if !value.IsOnCurve() {
+		return nil, errors.New("invalid point, not on the curve")
+	}
+	return &Secp256r1Point{point: value}, nil
+}
+func (e EccP256Point) decompress(bytes []byte, sign int) (*Secp256r1Point, error) {
+	x := fp.P256FpNew().SetBigInt(new(big.Int).SetBytes(bytes))
+
+	value := p256.P256PointNew().Identity()
+	rhs := fp.P256FpNew()
+	p := p256.P256PointNew()
+	p.Arithmetic.RhsEq(rhs, x)
+	// test that rhs is quadratic residue
+	// if not, then this Point is at infinity
+	y, wasQr := fp.P256FpNew().Sqrt(rhs)
+	if wasQr {
+		// fix the sign
+		sigY := int(y.Bytes()[0] & 1)
+		if sigY != sign {
+			y.Neg(y)
+		}
+		value.X = x
+		value.Y = y
+		value.Z.SetOne()
+	} else {
+		return nil, errors.New("invalid point, x is not on the curve")
+	}
+	return &Secp256r1Point{point: value}, nil
+}
+func (e EccP256Point) fromEncodedPoint(encodePoint EncodePoint) (pt *Secp256r1Point, err error) {
+	cd := encodePoint.Coordinates()
+	switch c := cd.(type) {
+	case *IdentityCoordinates:
+		pt = e.Identity()
+	case *CompactCoordinates:
+		pt, err = e.decompress(c.x, 0)
+	case *CompressedCoordinates:
+		choice := 0
+		if c.yIsOdd {
+			choice = 1
+		}
+		pt, err = e.decompress(c.x, choice)
+	case *UnCompressedCoordinates:
+		pt, err = e.fromUncompressed(c.x, c.y)
+	}
+	return pt, err
+}
+func (s Secp256r1Point) CurveType() EccCurveType {
+	return P256
+}
+
+func (s *Secp256r1Point) AddPoints(lhs, rhs EccPoint) EccPoint {
+	l := lhs.(*Secp256r1Point)
+	r := s.unaliased(rhs.(*Secp256r1Point))
+	s.point.Add(l.point, r)
+	return s
+}
+
+func (s *Secp256r1Point) SubPoints(lhs, rhs EccPoint) EccPoint {
+	l := lhs.(*Secp256r1Point)
+	r := s.unaliased(rhs.(*Secp256r1Point))
+	s.point.Sub(l.point, r)
+	return s
+}
+
+// unaliased returns the native point of an operand. The native Add and Sub
+// overwrite the receiver with lhs before reading rhs, so an rhs sharing the
+// receiver's storage is copied first.
+func (s *Secp256r1Point) unaliased(other *Secp256r1Point) *native.EllipticPoint {
+	if other.point == s.point {
+		return p256.P256PointNew().Set(other.point)
+	}
+	return other.point
+}
+
+func (s *Secp256r1Point) Plus(other EccPoint) EccPoint {
+	return s.Clone().AddPoints(s, other)
+}
+
+func (s *Secp256r1Point) Minus(other EccPoint) EccPoint {
+	return s.Clone().SubPoints(s, other)
+}
+
+func (s *Secp256r1Point) Times(scalar EccScalar) EccPoint {
+	return s.Clone().ScalarMul(s, scalar)
+}
+
+func (s *Secp256r1Point) Doubled() EccPoint {
+	return s.Clone().Double(s)
+}
+
+func (s *Secp256r1Point) Double(other EccPoint) EccPoint {
+	o := other.(*Secp256r1Point)
+	s.point.Double(o.point)
+	return s
+}
+
+func (s *Secp256r1Point) CAssign(other EccPoint, choice int) {
+	o := other.(*Secp256r1Point)
+	s.point.X.CMove(s.point.X, o.point.X, choice)
+	s.point.Y.CMove(s.point.Y, o.point.Y, choice)
+	s.point.Z.CMove(s.point.Z, o.point.Z, choice)
+}
+
+func (s Secp256r1Point) Clone() EccPoint {
+	return &Secp256r1Point{
+		point: p256.P256PointNew().Set(s.point),
+	}
+}
+
+func (s *Secp256r1Point) ScalarMul(other EccPoint, scalar EccScalar) EccPoint {
+	o := other.(*Secp256r1Point)
+	f := scalar.(*Secp256r1Scalar)
+	s.point.Mul(o.point, f.scalar)
+	return s
+}
+func (s *Secp256r1Point) MulByNodeIndex(scalar common.NodeIndex) EccPoint {
+	s64 := uint64(scalar + 1)
+	bits := 64 - bits.LeadingZeros64(uint64(s64))
+	res := EccPoint(P256Point.Identity())
+	for b := 0; b < bits; b++ {
+		res = res.Double(res)
+		if (s64 >> (bits - 1 - b) & 1) == 1 {
+			res = res.AddPoints(res, s)
+		}
+	}
+	return res
+}
+
+func (s *Secp256r1Point) LinComb(pt1 EccPoint, scalar1 EccScalar, pt2 EccPoint, scalar2 EccScalar) EccPoint {
+	s.point.SumOfProducts([]*native.EllipticPoint{pt1.(*Secp256r1Point).point, pt2.(*Secp256r1Point).point}, []*native.Field{scalar1.(*Secp256r1Scalar).scalar, scalar2.(*Secp256r1Scalar).scalar})
+	return s
+}
+
+func (s Secp256r1Point) encodePoint(compress bool) []byte {
+	if s.point.Z.IsOne() == 1 {
+		return encodeAffine(s.point, compress)
+	}
+	return encodeAffine(p256.P256PointNew().ToAffine(s.point), compress)
+}
+
+func (s Secp256r1Point) nativePoint() *native.EllipticPoint {
+	return s.point
+}
+
+func (s Secp256r1Point) withNative(pt *native.EllipticPoint) EccPoint {
+	return &Secp256r1Point{point: pt}
+}
+
+func (s Secp256r1Point) Serialize() []byte {
+	return s.encodePoint(true)
+}
+
+func (s Secp256r1Point) Equal(eccPoint EccPoint) int {
+	return s.point.Equal(eccPoint.(*Secp256r1Point).point)
+}
+
+func (s *Secp256r1Point) Assign(eccPoint EccPoint) EccPoint {
+	s.point.Set(eccPoint.(*Secp256r1Point).point)
+	return s
+}
+
+func (s Secp256r1Point) SerializeTagged() []byte {
+	bytes := make([]byte, 1+s.CurveType().PointBytes(), 1+s.CurveType().PointBytes())
+	bytes[0] = s.CurveType().Tag()
+	copy(bytes[1:], s.Serialize())
+	return bytes
+}
+
+func (s Secp256r1Point) SerializeUncompressed() []byte {
+	return s.encodePoint(false)
+}
+
+func (s Secp256r1Point) AffineX() EccFieldElement {
+	return &Secp256r1Field{field: s.point.GetX()}
+}
+
+func (s Secp256r1Point) AffineY() EccFieldElement {
+	return &Secp256r1Field{field: s.point.GetY()}
+}
+
+func (s Secp256r1Point) IsInfinity() bool {
+	return s.point.IsIdentity()
+}
+
+func (s Secp256r1Point) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(s.SerializeTagged())
+}
+
+func (s *Secp256r1Point) UnmarshalCBOR(data []byte) error {
+	var bytes []byte
+	if err := cbor.Unmarshal(data, &bytes); err != nil {
+		return err
+	}
+	tmp, err := P256Point.Deserialize(bytes[1:])
+	if err != nil {
+		return err
+	}
+	s.point = tmp.point
+	return nil
+}
@@ -0,0 +1,192 @@
+package curve
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/coinbase/kryptology/pkg/core/curves/native"
+	"github.com/coinbase/kryptology/pkg/core/curves/native/p256/fq"
+	"github.com/fxamacker/cbor/v2"
+	"math/big"
+)
+
+var (
+	P256Scalar     = EccP256Scalar{}
+	P256GroupOrder = fromHex("FFFFFFFF00000000FFFFFFFFFFFFFFFFBCE6FAADA7179E84F3B9CAC2FC632551")
+	P256OrderHalf  = new(big.Int).Div(P256GroupOrder, big.NewInt(2))
+	// floor(n / 2), little endian like native.Field.Bytes
+	p256OrderHalfLE = common.ReverseBytes(P256OrderHalf.FillBytes(make([]byte, 32)))
+)
+
+type EccP256Scalar struct{}
+type Secp256r1Scalar struct {
+	scalar *native.Field
+}
+
+func (EccP256Scalar) Deserialize(bytes []byte) (*Secp256r1Scalar, error) {
+	var buf [32]byte
+	copy(buf[:], common.ReverseBytes(bytes))
+	field, err := fq.P256FqNew().SetBytes(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return &Secp256r1Scalar{
+		scalar: field,
+	}, nil
+}
+func (EccP256Scalar) Zero() *Secp256r1Scalar {
+	return &Secp256r1Scalar{
+		scalar: fq.P256FqNew().SetZero(),
+	}
+}
+
+func (EccP256Scalar) One() *Secp256r1Scalar {
+	return &Secp256r1Scalar{
+		scalar: fq.P256FqNew().SetOne(),
+	}
+}
+
+func (EccP256Scalar) FromUint64(n uint64) *Secp256r1Scalar {
+	return &Secp256r1Scalar{
+		scalar: fq.P256FqNew().SetUint64(n),
+	}
+}
+
+func (EccP256Scalar) FromWideBytes(bytes []byte) *Secp256r1Scalar {
+	var r [64]byte
+	copy(r[:len(bytes)], common.ReverseBytes(bytes))
+
+	return &Secp256r1Scalar{
+		scalar: fq.P256FqNew().SetBytesWide(&r),
+	}
+}
+
+func (s Secp256r1Scalar) CurveType() EccCurveType {
+	return P256
+}
+func (s *Secp256r1Scalar) Add(lhs, rhs EccScalar) EccScalar {
+	l := lhs.(*Secp256r1Scalar)
+	r := rhs.(*Secp256r1Scalar)
+	s.scalar.Add(l.scalar, r.scalar)
+	return s
+}
+
+func (s *Secp256r1Scalar) Sub(lhs, rhs EccScalar) EccScalar {
+	l := lhs.(*Secp256r1Scalar)
+	r := rhs.(*Secp256r1Scalar)
+	s.scalar.Sub(l.scalar, r.scalar)
+	return s
+}
+
+func (s *Secp256r1Scalar) Mul(lhs, rhs EccScalar) EccScalar {
+	l := lhs.(*Secp256r1Scalar)
+	r := rhs.(*Secp256r1Scalar)
+	s.scalar.Mul(l.scalar, r.scalar)
+	return s
+}
+
+func (s *Secp256r1Scalar) Invert(other EccScalar) EccScalar {
+	o := other.(*Secp256r1Scalar)
+	s.scalar.Invert(o.scalar)
+	return s
+}
+
+func (s *Secp256r1Scalar) Negate(other EccScalar) EccScalar {
+	o := other.(*Secp256r1Scalar)
+	s.scalar.Neg(o.scalar)
+	return s
+}
+
+func (s *Secp256r1Scalar) Equal(other EccScalar) int {
+	o := other.(*Secp256r1Scalar)
+
+	return s.scalar.Equal(o.scalar)
+}
+func (s *Secp256r1Scalar) Assign(other EccScalar) EccScalar {
+	o := other.(*Secp256r1Scalar)
+	s.scalar.Set(o.scalar)
+	return s
+}
+
+func (s *Secp256r1Scalar) Clone() EccScalar {
+	return P256Scalar.Zero().Assign(s)
+}
+
+func (s *Secp256r1Scalar) Plus(other EccScalar) EccScalar {
+	return P256Scalar.Zero().Add(s, other)
+}
+
+func (s *Secp256r1Scalar) Minus(other EccScalar) EccScalar {
+	return P256Scalar.Zero().Sub(s, other)
+}
+
+func (s *Secp256r1Scalar) Times(other EccScalar) EccScalar {
+	return P256Scalar.Zero().Mul(s, other)
+}
+
+func (s *Secp256r1Scalar) Inverse() EccScalar {
+	return P256Scalar.Zero().Invert(s)
+}
+
+func (s *Secp256r1Scalar) Negated() EccScalar {
+	return P256Scalar.Zero().Negate(s)
+}
+
+func (s *Secp256r1Scalar) Zeroize() {
+	s.scalar.SetZero()
+}
+
+func (s Secp256r1Scalar) Serialize() []byte {
+	bytes := s.scalar.Bytes()
+	return common.ReverseBytes(bytes[:])
+}
+
+func (s Secp256r1Scalar) SerializeTagged() []byte {
+	var bytes []byte
+	bytes = append(bytes, []byte{byte(s.CurveType())}...)
+	bytes = append(bytes, s.Serialize()...)
+	return bytes
+}
+
+func (s Secp256r1Scalar) IsZero() int {
+	return s.scalar.IsZero()
+}
+
+func (s Secp256r1Scalar) IsHigh() bool {
+	bytes := s.scalar.Bytes()
+	return greaterLE(bytes[:], p256OrderHalfLE) == 1
+}
+func (s Secp256r1Scalar) MarshalCBOR() ([]byte, error) {
+	return cbor.Marshal(s.SerializeTagged())
+}
+
+func (s *Secp256r1Scalar) UnmarshalCBOR(data []byte) error {
+	var bytes []byte
+	if err := cbor.Unmarshal(data, &bytes); err != nil {
+		return err
+	}
+	tmp, err := P256Scalar.Deserialize(bytes[1:])
+	if err != nil {
+		return err
+	}
+	s.scalar = tmp.scalar
+	return nil
+}
+func (s Secp256r1Scalar) BigInt() *big.Int {
+	return s.scalar.BigInt()
+}
+
+type Secp256R1ScalarBytes [32]byte
+
+func (Secp256R1ScalarBytes) CurveType() EccCurveType {
+	return P256
+}
+func (s Secp256R1ScalarBytes) ScalarBytes() []byte {
+	return s[:]
+}
+
+func (s Secp256R1ScalarBytes) ToScalar() EccScalar {
+	scalar, err := P256Scalar.Deserialize(s[:])
+	if err != nil {
+		panic(err.Error())
+	}
+	return scalar
+}
@@ -1,7 +1,6 @@
 package curve
 
 import (
-	"encoding/hex"
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/pkg/errors"
 )
@@ -12,12 +11,29 @@ var (
 
 type point struct{}
 
+// EccPoint is a point of an elliptic curve group.
+//
+// AddPoints, SubPoints, ScalarMul, Double, CAssign and Assign are in-place:
+// they overwrite the receiver with the result and return it. Their operands
+// are never modified and may be the receiver itself.
+//
+// Plus, Minus, Times and Doubled are immutable: they return a new point and
+// modify neither the receiver nor the operands.
+//
+// No method modifies anything but its receiver, so a point may be shared
+// between goroutines as long as none of them uses it as the receiver of an
+// in-place method.
 type EccPoint interface {
 	CurveType() EccCurveType
 	AddPoints(lhs, rhs EccPoint) EccPoint
 	SubPoints(lhs, rhs EccPoint) EccPoint
 	ScalarMul(other EccPoint, scalar EccScalar) EccPoint
 	Double(other EccPoint) EccPoint
+	CAssign(other EccPoint, choice int)
+	Plus(other EccPoint) EccPoint
+	Minus(other EccPoint) EccPoint
+	Times(scalar EccScalar) EccPoint
+	Doubled() EccPoint
 	MulByNodeIndex(scalar common.NodeIndex) EccPoint
 	Clone() EccPoint
 	Serialize() []byte
@@ -32,33 +48,26 @@ type EccPoint interface {
 
 func (p point) Identity(curve EccCurveType) EccPoint {
 	var ec EccPoint
-	switch curve {
-	case K256:
-		ec = K256Point.Identity()
+	if c := lookup(curve); c != nil {
+		ec = c.Identity()
 	}
 	return ec
 }
 
 func (p point) GeneratorG(curve EccCurveType) EccPoint {
 	var ec EccPoint
-	switch curve {
-	case K256:
-		ec = K256Point.NewK256G()
+	if c := lookup(curve); c != nil {
+		ec = c.GeneratorG()
 	}
 	return ec
 }
 
 func (p point) GeneratorH(curve EccCurveType) EccPoint {
-	var h []byte
-	switch curve {
-	case K256:
-		h, _ = hex.DecodeString("037bdcfc024cf697a41fd3cda2436c843af5669e50042be3314a532d5b70572f59")
-	}
-	pt, err := p.Deserialize(curve, h)
-	if err != nil {
-		panic(err.Error())
+	var ec EccPoint
+	if c := lookup(curve); c != nil {
+		ec = c.GeneratorH()
 	}
-	return pt
+	return ec
 }
 
 func (p point) HashToPoint(curve EccCurveType, input []byte, domainSeparator []byte) (EccPoint, error) {
@@ -66,7 +75,7 @@ func (p point) HashToPoint(curve EccCurveType, input []byte, domainSeparator []b
 }
 func (p point) FromFieldElems(x EccFieldElement, y EccFieldElement) (EccPoint, error) {
 	if x.CurveType() != y.CurveType() {
-		return nil, errors.New("curve mismatch")
+		return nil, common.ErrCurveMismatch
 	}
 	curve := x.CurveType()
 	xb := x.AsBytes()
@@ -78,35 +87,58 @@ func (p point) FromFieldElems(x EccFieldElement, y EccFieldElement) (EccPoint, e
 }
 
 func (p point) MulPoints(pt1 EccPoint, scalar1 EccScalar, pt2 EccPoint, scalar2 EccScalar) EccPoint {
-	var ec EccPoint
-	switch pt1.CurveType() {
-	case K256:
-		ec = K256Point.NewK256().LinComb(pt1, scalar1, pt2, scalar2)
+	c := lookup(pt1.CurveType())
+	if c == nil {
+		return nil
 	}
-	return ec
+	if c.MulPoints != nil {
+		return c.MulPoints(pt1, scalar1, pt2, scalar2)
+	}
+	lhs := pt1.Clone().ScalarMul(pt1, scalar1)
+	return lhs.AddPoints(lhs, pt2.Clone().ScalarMul(pt2, scalar2))
 }
 
 func (p point) Pedersen(scalar1 EccScalar, scalar2 EccScalar) EccPoint {
-	g := p.GeneratorG(scalar1.CurveType())
-	h := p.GeneratorH(scalar1.CurveType())
-	return p.MulPoints(g, scalar1, h, scalar2)
+	g := p.MulByG(scalar1)
+	h := p.MulByH(scalar2)
+	if g == nil || h == nil {
+		return nil
+	}
+	return g.AddPoints(g, h)
 }
 
 func (p point) MulByG(scalar EccScalar) EccPoint {
-	g := p.GeneratorG(scalar.CurveType())
-	return g.ScalarMul(g, scalar)
+	var ec EccPoint
+	if c := lookup(scalar.CurveType()); c != nil {
+		ec = c.fixedBases.g().mul(scalar)
+	}
+	return ec
+}
+
+func (p point) MulByH(scalar EccScalar) EccPoint {
+	var ec EccPoint
+	if c := lookup(scalar.CurveType()); c != nil {
+		ec = c.fixedBases.h().mul(scalar)
+	}
+	return ec
 }
 
 func (p point) DeserializeTagged(curve EccCurveType, bytes []byte) (EccPoint, error) {
 	if len(bytes) == 0 {
 		return nil, errors.New("invalid point, bytes is empty")
 	}
-	return p.Deserialize(EccCurveType(bytes[0]), bytes[:])
+	if bytes[0] != curve.Tag() {
+		return nil, errors.New("invalid point, unexpected curve tag")
+	}
+	return p.Deserialize(curve, bytes[1:])
 }
 func (p point) Deserialize(curve EccCurveType, bytes []byte) (EccPoint, error) {
 	if len(bytes) != curve.PointBytes() {
 		return nil, errors.New("invalid point")
 	}
+	if c := lookup(curve); c != nil && c.PointBytes != 0 {
+		return c.DeserializePoint(bytes)
+	}
 
 	flag := true
 	for _, b := range bytes {
@@ -124,9 +156,9 @@ func (p point) Deserialize(curve EccCurveType, bytes []byte) (EccPoint, error) {
 	return p.DeserializeAnyFormat(curve, bytes)
 }
 func (p point) DeserializeAnyFormat(curve EccCurveType, bytes []byte) (pt EccPoint, err error) {
-	switch curve {
-	case K256:
-		pt, err = K256Point.Deserialize(bytes)
+	c := lookup(curve)
+	if c == nil {
+		return nil, common.ErrUnknownCurve
 	}
-	return pt, err
+	return c.DeserializePoint(bytes)
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/rand"
 	"github.com/stretchr/testify/assert"
+	"math/big"
+	"sync"
 	"testing"
 )
 
@@ -114,6 +116,153 @@ func TestScalarNegate(t *testing.T) {
 	}
 }
 
+func TestScalarArithmeticIsConsistentWithBigInt(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		n := Scalar.Zero(curve).Sub(Scalar.Zero(curve), Scalar.One(curve)).BigInt()
+		n.Add(n, big.NewInt(1))
+		half := new(big.Int).Rsh(n, 1)
+		for i := 0; i < 100; i++ {
+			a := Scalar.Random(curve, rng)
+			b := Scalar.Random(curve, rng)
+
+			sum := new(big.Int).Add(a.BigInt(), b.BigInt())
+			assert.Equal(t, sum.Mod(sum, n), Scalar.Zero(curve).Add(a, b).BigInt())
+			diff := new(big.Int).Sub(a.BigInt(), b.BigInt())
+			assert.Equal(t, diff.Mod(diff, n), Scalar.Zero(curve).Sub(a, b).BigInt())
+			prod := new(big.Int).Mul(a.BigInt(), b.BigInt())
+			assert.Equal(t, prod.Mod(prod, n), Scalar.Zero(curve).Mul(a, b).BigInt())
+			inv := new(big.Int).ModInverse(a.BigInt(), n)
+			assert.Equal(t, inv, Scalar.Zero(curve).Invert(a).BigInt())
+			assert.Equal(t, a.BigInt().Cmp(half) > 0, a.IsHigh())
+		}
+		halfScalar, err := Scalar.Deserialize(curve, half.Bytes())
+		assert.Nil(t, err)
+		assert.False(t, halfScalar.IsHigh())
+		assert.True(t, Scalar.Zero(curve).Add(halfScalar, Scalar.One(curve)).IsHigh())
+	}
+}
+
+func TestK256ScalarNativeMatchesItsEncoding(t *testing.T) {
+	rng := rand.NewChaCha20(make([]byte, 32))
+	for i := 0; i < 100; i++ {
+		s := Scalar.Random(K256, rng).(*Secp256k1Scalar)
+		assert.Equal(t, s.BigInt(), s.native().BigInt())
+	}
+	assert.Equal(t, 1, K256Scalar.Zero().native().IsZero())
+	assert.Equal(t, 1, K256Scalar.One().native().IsOne())
+}
+
+func TestMultiScalarMulMatchesNaive(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		for _, n := range []int{1, 2, 3, 17, 40} {
+			points := make([]EccPoint, n)
+			scalars := make([]EccScalar, n)
+			expected := Point.Identity(curve)
+			for i := 0; i < n; i++ {
+				points[i] = Point.MulByG(Scalar.Random(curve, rng))
+				scalars[i] = Scalar.Random(curve, rng)
+				if i%5 == 0 {
+					scalars[i] = Scalar.FromUint64(curve, uint64(i))
+				}
+				term := points[i].Clone().ScalarMul(points[i], scalars[i])
+				expected = expected.AddPoints(expected, term)
+			}
+			result, err := Point.MultiScalarMul(points, scalars)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, result.Equal(expected))
+		}
+		_, err := Point.MultiScalarMul([]EccPoint{Point.GeneratorG(curve)}, nil)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestFixedBaseMulMatchesScalarMul(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		g := Point.GeneratorG(curve)
+		h := Point.GeneratorH(curve)
+		scalars := []EccScalar{Scalar.Zero(curve), Scalar.One(curve), Scalar.Zero(curve).Negate(Scalar.One(curve))}
+		for i := 0; i < 20; i++ {
+			scalars = append(scalars, Scalar.Random(curve, rng))
+		}
+		for _, s := range scalars {
+			assert.Equal(t, 1, Point.MulByG(s).Equal(g.Clone().ScalarMul(g, s)))
+			assert.Equal(t, 1, Point.MulByH(s).Equal(h.Clone().ScalarMul(h, s)))
+			assert.Equal(t, 1, Point.Pedersen(s, scalars[1]).Equal(Point.MulPoints(g, s, h, scalars[1])))
+		}
+	}
+}
+
+func TestBatchSerializeMatchesSerialize(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		points := []EccPoint{Point.Identity(curve)}
+		for i := 0; i < 10; i++ {
+			pt := Point.MulByG(Scalar.Random(curve, rng))
+			points = append(points, pt.AddPoints(pt, Point.GeneratorH(curve)))
+		}
+		points = append(points, Point.Identity(curve))
+		encoded := Point.BatchSerialize(points)
+		affine := Point.BatchToAffine(points)
+		for i, pt := range points {
+			assert.Equal(t, pt.Serialize(), encoded[i])
+			assert.Equal(t, 1, pt.Equal(affine[i]))
+			assert.Equal(t, pt.Serialize(), affine[i].Serialize())
+			assert.Equal(t, pt.IsInfinity(), affine[i].IsInfinity())
+		}
+	}
+}
+
+func TestBatchInvertHandlesZero(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		scalars := []EccScalar{Scalar.Zero(curve)}
+		for i := 0; i < 10; i++ {
+			scalars = append(scalars, Scalar.Random(curve, rng))
+		}
+		scalars = append(scalars, Scalar.Zero(curve), Scalar.One(curve))
+		inverses, err := Scalar.BatchInvert(scalars)
+		assert.Nil(t, err)
+		for i, s := range scalars {
+			assert.Equal(t, 1, inverses[i].Equal(s.Clone().Invert(s)))
+		}
+		_, err = Scalar.BatchInvert(nil)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestInnerProductMatchesElementwiseMul(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		lhs := make([]EccScalar, 8)
+		rhs := make([]EccScalar, 8)
+		for i := range lhs {
+			lhs[i] = Scalar.Random(curve, rng)
+			rhs[i] = Scalar.Random(curve, rng)
+		}
+		products, err := Scalar.ElementwiseMul(lhs, rhs)
+		assert.Nil(t, err)
+		sum := Scalar.Zero(curve)
+		for i, p := range products {
+			assert.Equal(t, 1, p.Equal(Scalar.Zero(curve).Mul(lhs[i], rhs[i])))
+			sum = sum.Add(sum, p)
+		}
+		ip, err := Scalar.InnerProduct(lhs, rhs)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, ip.Equal(sum))
+		_, err = Scalar.InnerProduct(lhs, rhs[1:])
+		assert.NotNil(t, err)
+	}
+}
+
 func TestPointMulByNodeIndex(t *testing.T) {
 	for _, curve := range all() {
 		g := Point.GeneratorG(curve)
@@ -125,3 +274,143 @@ func TestPointMulByNodeIndex(t *testing.T) {
 		}
 	}
 }
+
+func TestScalarZeroize(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		a := Scalar.Random(curve, rng)
+		b := Scalar.Random(curve, rng)
+		Scalar.Zeroize(a, nil, b)
+		assert.Equal(t, 1, a.IsZero())
+		assert.Equal(t, 1, b.IsZero())
+		assert.Equal(t, make([]byte, curve.ScalarBytes()), a.Serialize())
+	}
+}
+
+func TestInPlaceOpsAllowAliasing(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		a := Scalar.Random(curve, rng)
+		b := Scalar.Random(curve, rng)
+		assert.Equal(t, 1, a.Clone().Add(a, b).Equal(a.Plus(b)))
+		assert.Equal(t, 1, a.Clone().Sub(b, a.Clone()).Equal(b.Minus(a)))
+		s := a.Clone()
+		assert.Equal(t, 1, s.Mul(s, s).Equal(a.Times(a)))
+		s = a.Clone()
+		assert.Equal(t, 1, s.Sub(b, s).Equal(b.Minus(a)))
+		s = a.Clone()
+		assert.Equal(t, 1, s.Invert(s).Equal(a.Inverse()))
+		s = a.Clone()
+		assert.Equal(t, 1, s.Negate(s).Equal(a.Negated()))
+
+		p := Point.MulByG(a)
+		q := Point.MulByG(b)
+		sum := Point.MulByG(a.Plus(b))
+		diff := Point.MulByG(b.Minus(a))
+		r := p.Clone()
+		assert.Equal(t, 1, r.AddPoints(q, r).Equal(sum))
+		r = p.Clone()
+		assert.Equal(t, 1, r.AddPoints(r, q).Equal(sum))
+		r = p.Clone()
+		assert.Equal(t, 1, r.SubPoints(q, r).Equal(diff))
+		r = p.Clone()
+		assert.True(t, r.SubPoints(r, r).IsInfinity())
+		r = p.Clone()
+		assert.Equal(t, 1, r.AddPoints(r, r).Equal(p.Doubled()))
+		r = p.Clone()
+		assert.Equal(t, 1, r.ScalarMul(r, b).Equal(Point.MulByG(a.Times(b))))
+	}
+}
+
+func TestImmutableOpsLeaveOperandsUnchanged(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		a := Scalar.Random(curve, rng)
+		b := Scalar.Random(curve, rng)
+		aBytes, bBytes := a.Serialize(), b.Serialize()
+		a.Plus(b)
+		a.Minus(b)
+		a.Times(b)
+		a.Inverse()
+		a.Negated()
+		assert.Equal(t, aBytes, a.Serialize())
+		assert.Equal(t, bBytes, b.Serialize())
+
+		p := Point.MulByG(a)
+		q := Point.MulByG(b)
+		pBytes, qBytes := p.Serialize(), q.Serialize()
+		p.Plus(q)
+		p.Minus(q)
+		p.Times(b)
+		p.Doubled()
+		assert.Equal(t, pBytes, p.Serialize())
+		assert.Equal(t, qBytes, q.Serialize())
+		assert.Equal(t, bBytes, b.Serialize())
+	}
+}
+
+func TestInPlaceScalarOpsDoNotAllocate(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		a := Scalar.Random(curve, rng)
+		b := Scalar.Random(curve, rng)
+		s := Scalar.Zero(curve)
+		allocs := testing.AllocsPerRun(100, func() {
+			s.Add(a, b)
+			s.Sub(s, b)
+			s.Mul(s, a)
+			s.Negate(s)
+			s.Assign(b)
+		})
+		assert.Equal(t, float64(0), allocs, curve.String())
+	}
+}
+
+func TestSharedOperandsAreNotCorruptedConcurrently(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curve := range all() {
+		a := Scalar.Random(curve, rng)
+		b := Scalar.Random(curve, rng)
+		p := Point.MulByG(a)
+		q := Point.MulByG(b)
+		wantScalar := a.Times(b).Plus(a)
+		wantPoint := p.Times(b).Plus(q)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 16)
+		for i := 0; i < cap(errs); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					s := Scalar.Zero(curve)
+					s.Mul(a, b)
+					s.Add(s, a)
+					r := Point.Identity(curve)
+					r.ScalarMul(p, b)
+					r.AddPoints(r, q)
+					if s.Equal(wantScalar) != 1 || a.Times(b).Plus(a).Equal(wantScalar) != 1 {
+						errs <- fmt.Errorf("scalar result differs")
+						return
+					}
+					if r.Equal(wantPoint) != 1 || p.Times(b).Plus(q).Equal(wantPoint) != 1 {
+						errs <- fmt.Errorf("point result differs")
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Error(err)
+		}
+		assert.Equal(t, 1, p.Equal(Point.MulByG(a)))
+		assert.Equal(t, 1, q.Equal(Point.MulByG(b)))
+	}
+}
@@ -0,0 +1,186 @@
+package curve
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"github.com/pkg/errors"
+	"sort"
+	"sync"
+)
+
+// CurveDescriptor tells the curve package how to build the points, scalars
+// and field elements of a curve. Curves defined outside this package are
+// added with Register and afterwards work everywhere an EccCurveType is
+// accepted.
+type CurveDescriptor struct {
+	// Type is the identifier of the curve, it doubles as the tag byte in
+	// tagged serializations and must be unique and non zero.
+	Type          EccCurveType
+	Name          string
+	ScalarBits    int
+	FieldBits     int
+	SecurityLevel int
+	// PointBytes is the length of a serialized point. Optional, zero means
+	// SEC1 compressed points of 1 + field bytes, where all zero bytes are the
+	// identity. Curves with another encoding validate it in
+	// DeserializePoint.
+	PointBytes int
+	// OID and JWKName identify the curve in X.509 and JWK, they are
+	// optional and only needed by Codec.
+	OID     asn1.ObjectIdentifier
+	JWKName string
+
+	Identity   func() EccPoint
+	GeneratorG func() EccPoint
+	GeneratorH func() EccPoint
+	// DeserializePoint decodes any SEC1 style encoding of a point.
+	DeserializePoint func(bytes []byte) (EccPoint, error)
+	// MulPoints computes pt1 * scalar1 + pt2 * scalar2. Optional, defaults
+	// to two scalar multiplications.
+	MulPoints func(pt1 EccPoint, scalar1 EccScalar, pt2 EccPoint, scalar2 EccScalar) EccPoint
+
+	ScalarZero          func() EccScalar
+	ScalarOne           func() EccScalar
+	ScalarFromUint64    func(n uint64) EccScalar
+	DeserializeScalar   func(bytes []byte) (EccScalar, error)
+	ScalarFromBytesWide func(bytes []byte) EccScalar
+	ScalarBytes         func(scalar EccScalar) EccScalarBytes
+
+	FieldZero          func() EccFieldElement
+	FieldOne           func() EccFieldElement
+	FieldA             func() EccFieldElement
+	FieldB             func() EccFieldElement
+	FieldFromBytes     func(bytes []byte) (EccFieldElement, error)
+	FieldFromBytesWide func(bytes []byte) EccFieldElement
+
+	// ExpandMessage is the expander used by HashToField, HashToScalar and
+	// HashToCurveRo. Optional, defaults to expand_message_xmd with SHA-256.
+	ExpandMessage func(msg []byte, domainSeparator []byte, length int) ([]byte, error)
+	// MapToCurve maps a field element to a point of the prime order group.
+	// Optional, curves without it use simplified SWU and must provide the
+	// parameters below.
+	MapToCurve func(u EccFieldElement) (EccPoint, error)
+
+	// Simplified SWU parameters used by hash-to-curve.
+	SswuA  func() EccFieldElement
+	SswuB  func() EccFieldElement
+	SswuZ  func() EccFieldElement
+	SswuC2 func() EccFieldElement
+	// SqrtRatio overrides the generic square root of u/v used by SSWU.
+	// Optional.
+	SqrtRatio func(u, v EccFieldElement) (EccFieldElement, int, error)
+	// Isogeny maps points found by SSWU on an isogenous curve back to this
+	// curve. Optional, for curves where SSWU applies directly.
+	Isogeny func(x, y EccFieldElement) (EccFieldElement, EccFieldElement)
+}
+
+func (d *CurveDescriptor) validate() error {
+	if d.Type == 0 {
+		return errors.New("curve type must not be zero")
+	}
+	if d.Type < 0 || d.Type > 0xff {
+		return errors.New("curve type must fit in the tag byte")
+	}
+	if d.Name == "" || d.ScalarBits <= 0 || d.FieldBits <= 0 || d.SecurityLevel <= 0 {
+		return errors.New("curve parameters are incomplete")
+	}
+	required := []struct {
+		name string
+		ok   bool
+	}{
+		{"Identity", d.Identity != nil},
+		{"GeneratorG", d.GeneratorG != nil},
+		{"GeneratorH", d.GeneratorH != nil},
+		{"DeserializePoint", d.DeserializePoint != nil},
+		{"ScalarZero", d.ScalarZero != nil},
+		{"ScalarOne", d.ScalarOne != nil},
+		{"ScalarFromUint64", d.ScalarFromUint64 != nil},
+		{"DeserializeScalar", d.DeserializeScalar != nil},
+		{"ScalarFromBytesWide", d.ScalarFromBytesWide != nil},
+		{"ScalarBytes", d.ScalarBytes != nil},
+		{"FieldZero", d.FieldZero != nil},
+		{"FieldOne", d.FieldOne != nil},
+		{"FieldA", d.FieldA != nil},
+		{"FieldB", d.FieldB != nil},
+		{"FieldFromBytes", d.FieldFromBytes != nil},
+		{"FieldFromBytesWide", d.FieldFromBytesWide != nil},
+	}
+	if d.MapToCurve == nil {
+		required = append(required, []struct {
+			name string
+			ok   bool
+		}{
+			{"SswuA", d.SswuA != nil},
+			{"SswuB", d.SswuB != nil},
+			{"SswuZ", d.SswuZ != nil},
+			{"SswuC2", d.SswuC2 != nil},
+		}...)
+	}
+	for _, r := range required {
+		if !r.ok {
+			return fmt.Errorf("curve descriptor is missing %s", r.name)
+		}
+	}
+	return nil
+}
+
+type registeredCurve struct {
+	CurveDescriptor
+	fixedBases *curveFixedBases
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[EccCurveType]*registeredCurve{}
+)
+
+// Register adds a curve. It is meant to be called from an init function;
+// registering the same type twice is an error.
+func Register(descriptor CurveDescriptor) error {
+	if err := descriptor.validate(); err != nil {
+		return err
+	}
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := registry[descriptor.Type]; ok {
+		return fmt.Errorf("curve type %d is already registered", descriptor.Type)
+	}
+	registry[descriptor.Type] = &registeredCurve{
+		CurveDescriptor: descriptor,
+		fixedBases:      newCurveFixedBases(descriptor.Type),
+	}
+	return nil
+}
+
+func mustRegister(descriptor CurveDescriptor) {
+	if err := Register(descriptor); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Lookup returns the descriptor of a registered curve.
+func Lookup(curve EccCurveType) (CurveDescriptor, bool) {
+	r := lookup(curve)
+	if r == nil {
+		return CurveDescriptor{}, false
+	}
+	return r.CurveDescriptor, true
+}
+
+// RegisteredCurves returns every registered curve type in ascending order.
+func RegisteredCurves() []EccCurveType {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	curves := make([]EccCurveType, 0, len(registry))
+	for c := range registry {
+		curves = append(curves, c)
+	}
+	sort.Slice(curves, func(i, j int) bool { return curves[i] < curves[j] })
+	return curves
+}
+
+func lookup(curve EccCurveType) *registeredCurve {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	return registry[curve]
+}
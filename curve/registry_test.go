@@ -0,0 +1,63 @@
+package curve
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBuiltinCurvesAreRegistered(t *testing.T) {
+	assert.Equal(t, []EccCurveType{K256, P256, ED25519}, RegisteredCurves())
+	for _, curve := range all() {
+		d, ok := Lookup(curve)
+		assert.True(t, ok)
+		assert.Equal(t, curve, d.Type)
+		assert.Equal(t, d.Name, curve.String())
+		assert.Equal(t, uint8(curve), curve.Tag())
+		assert.Equal(t, curve, FromTag(curve.Tag()))
+	}
+	_, ok := Lookup(EccCurveType(0xfe))
+	assert.False(t, ok)
+	assert.Equal(t, EccCurveType(0), FromTag(0xfe))
+	assert.Equal(t, "", EccCurveType(0xfe).String())
+}
+
+func TestRegisterRejectsInvalidDescriptors(t *testing.T) {
+	d, ok := Lookup(K256)
+	assert.True(t, ok)
+	assert.NotNil(t, Register(d))
+
+	d.Type = 0
+	assert.NotNil(t, Register(d))
+	d.Type = 0x100
+	assert.NotNil(t, Register(d))
+
+	d.Type = EccCurveType(0xfe)
+	d.GeneratorH = nil
+	assert.NotNil(t, Register(d))
+	_, ok = Lookup(d.Type)
+	assert.False(t, ok)
+}
+
+func TestUnknownCurveIsRejected(t *testing.T) {
+	unknown := EccCurveType(0xfe)
+	_, err := Point.Deserialize(unknown, make([]byte, 33))
+	assert.NotNil(t, err)
+	_, err = Scalar.Deserialize(unknown, make([]byte, 32))
+	assert.NotNil(t, err)
+	_, err = Field.FromBytes(unknown, make([]byte, 32))
+	assert.NotNil(t, err)
+	assert.Nil(t, Point.Identity(unknown))
+	scalar := unknownScalar{Scalar.One(K256)}
+	assert.Nil(t, Point.MulByG(scalar))
+	assert.Nil(t, Point.MulByH(scalar))
+	assert.Nil(t, Point.Pedersen(scalar, scalar))
+}
+
+// unknownScalar is a scalar of a curve that is not registered.
+type unknownScalar struct {
+	EccScalar
+}
+
+func (unknownScalar) CurveType() EccCurveType {
+	return EccCurveType(0xfe)
+}
@@ -14,6 +14,19 @@ var (
 
 type scalar struct{}
 
+// EccScalar is an integer modulo the group order.
+//
+// Add, Sub, Mul, Invert, Negate and Assign are in-place: they overwrite the
+// receiver with the result and return it. Their operands are never modified
+// and may be the receiver itself, so s.Add(s, t) is fine. They do not
+// allocate.
+//
+// Plus, Minus, Times, Inverse and Negated are immutable: they return a new
+// scalar and modify neither the receiver nor the operand.
+//
+// No method modifies anything but its receiver, so a scalar may be shared
+// between goroutines as long as none of them uses it as the receiver of an
+// in-place method.
 type EccScalar interface {
 	CurveType() EccCurveType
 	Add(lhs, rhs EccScalar) EccScalar
@@ -22,6 +35,11 @@ type EccScalar interface {
 	Invert(other EccScalar) EccScalar
 	Negate(other EccScalar) EccScalar
 	Assign(other EccScalar) EccScalar
+	Plus(other EccScalar) EccScalar
+	Minus(other EccScalar) EccScalar
+	Times(other EccScalar) EccScalar
+	Inverse() EccScalar
+	Negated() EccScalar
 	Clone() EccScalar
 	Equal(other EccScalar) int
 	IsZero() int
@@ -29,6 +47,9 @@ type EccScalar interface {
 	BigInt() *big.Int
 	Serialize() []byte
 	SerializeTagged() []byte
+	// Zeroize overwrites the scalar with zero. Use it to wipe secrets once
+	// they are no longer needed.
+	Zeroize()
 }
 
 func (s scalar) HashToScalar(curve EccCurveType, input []byte, domainSeparator []byte) (EccScalar, error) {
@@ -47,61 +68,60 @@ func (s scalar) DeserializeTagged(bytes []byte) (EccScalar, error) {
 	if len(bytes) == 0 {
 		return nil, errors.New("invalid scalar")
 	}
-	var scalar EccScalar
-	var err error
-	switch EccCurveType(bytes[0]) {
-	case K256:
-		scalar, err = K256Scalar.Deserialize(bytes[0:])
-
-	}
-	return scalar, err
+	return s.Deserialize(EccCurveType(bytes[0]), bytes[1:])
 }
 
 func (s scalar) Deserialize(curve EccCurveType, bytes []byte) (EccScalar, error) {
 	if len(bytes) == 0 {
 		return nil, errors.New("invalid scalar")
 	}
-	var scalar EccScalar
-	var err error
-	switch curve {
-	case K256:
-		scalar, err = K256Scalar.Deserialize(bytes)
+	c := lookup(curve)
+	if c == nil {
+		return nil, common.ErrUnknownCurve
 	}
-	return scalar, err
+	return c.DeserializeScalar(bytes)
 }
 
-func (s scalar) FromBytesWide(curve EccCurveType, bytes []byte) (EccScalar, error) {
-	var scalar EccScalar
-	switch curve {
-	case K256:
-		scalar = K256Scalar.FromWideBytes(bytes)
+// DeserializeCanonical only accepts the encoding Serialize produces: exactly
+// ScalarBytes big endian bytes of an integer below the group order.
+func (s scalar) DeserializeCanonical(curve EccCurveType, bytes []byte) (EccScalar, error) {
+	if len(bytes) != curve.ScalarBytes() {
+		return nil, errors.New("invalid scalar, unexpected length")
+	}
+	return s.Deserialize(curve, bytes)
+}
 
+func (s scalar) FromBytesWide(curve EccCurveType, bytes []byte) (EccScalar, error) {
+	c := lookup(curve)
+	if c == nil {
+		return nil, common.ErrUnknownCurve
 	}
-	return scalar, nil
+	return c.ScalarFromBytesWide(bytes), nil
 }
 
 func (s scalar) Zero(curve EccCurveType) EccScalar {
 	var scalar EccScalar
-	switch curve {
-	case K256:
-		scalar = K256Scalar.Zero()
+	if c := lookup(curve); c != nil {
+		scalar = c.ScalarZero()
 	}
 	return scalar
 }
 
 func (s scalar) One(curve EccCurveType) EccScalar {
 	var scalar EccScalar
-	switch curve {
-	case K256:
-		scalar = K256Scalar.One()
+	if c := lookup(curve); c != nil {
+		scalar = c.ScalarOne()
 	}
 	return scalar
 }
 
 func (s scalar) Random(curve EccCurveType, rng rand.Rand) EccScalar {
 	buf := make([]byte, curve.ScalarBytes())
+	// drop the bits above the order so that fewer candidates are rejected
+	mask := byte(0xff >> uint(8*len(buf)-curve.ScalarBits()))
 	for {
 		rng.FillUint8(buf)
+		buf[0] &= mask
 		if scalar, err := s.Deserialize(curve, buf); err == nil {
 			return scalar
 		}
@@ -115,23 +135,29 @@ func (s scalar) FromSeed(curve EccCurveType, seed *seed.Seed) EccScalar {
 
 func (s scalar) ToScalarBytes(scalar EccScalar) EccScalarBytes {
 	var bytes EccScalarBytes
-	switch scalar.CurveType() {
-	case K256:
-		var k256 Secp256K1ScalarBytes
-		copy(k256[:], scalar.Serialize())
+	if c := lookup(scalar.CurveType()); c != nil {
+		bytes = c.ScalarBytes(scalar)
 	}
 	return bytes
 }
 
 func (s scalar) FromUint64(curve EccCurveType, n uint64) EccScalar {
 	var scalar EccScalar
-	switch curve {
-	case K256:
-		scalar = K256Scalar.FromUint64(n)
+	if c := lookup(curve); c != nil {
+		scalar = c.ScalarFromUint64(n)
 	}
 	return scalar
 }
 
+// Zeroize wipes every non nil scalar.
+func (s scalar) Zeroize(scalars ...EccScalar) {
+	for _, scalar := range scalars {
+		if scalar != nil {
+			scalar.Zeroize()
+		}
+	}
+}
+
 func (s scalar) FromNodeIndex(curve EccCurveType, index common.NodeIndex) EccScalar {
 	return s.FromUint64(curve, uint64(index)+1)
 }
@@ -141,3 +167,13 @@ type EccScalarBytes interface {
 	ScalarBytes() []byte
 	ToScalar() EccScalar
 }
+
+// greaterLE returns 1 if a > b, both little endian and of the same length,
+// without branching on their values.
+func greaterLE(a, b []byte) int {
+	var borrow uint32
+	for i := range a {
+		borrow = (uint32(b[i]) - uint32(a[i]) - borrow) >> 31
+	}
+	return int(borrow)
+}
@@ -0,0 +1,82 @@
+package curve
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/pkg/errors"
+)
+
+func checkScalarVector(scalars []EccScalar) error {
+	if len(scalars) == 0 {
+		return errors.New("scalars is empty")
+	}
+	for _, s := range scalars {
+		if s.CurveType() != scalars[0].CurveType() {
+			return common.ErrCurveMismatch
+		}
+	}
+	return nil
+}
+
+// BatchInvert returns the inverse of every scalar using a single inversion
+// (Montgomery's trick). Zero has no inverse and is returned as zero without
+// affecting the other results; this is done without branching on secrets.
+func (s scalar) BatchInvert(scalars []EccScalar) ([]EccScalar, error) {
+	if err := checkScalarVector(scalars); err != nil {
+		return nil, err
+	}
+	curveType := scalars[0].CurveType()
+
+	// zeros are replaced by one so they drop out of the running product
+	nonZero := make([]EccScalar, len(scalars))
+	prefix := make([]EccScalar, len(scalars))
+	acc := s.One(curveType)
+	for i, x := range scalars {
+		fix := s.FromUint64(curveType, uint64(x.IsZero()))
+		nonZero[i] = fix.Add(x, fix)
+		prefix[i] = acc.Clone()
+		acc = acc.Mul(acc, nonZero[i])
+	}
+
+	inv := acc.Invert(acc)
+	result := make([]EccScalar, len(scalars))
+	for i := len(scalars) - 1; i >= 0; i-- {
+		r := prefix[i].Mul(prefix[i], inv)
+		inv = inv.Mul(inv, nonZero[i])
+		keep := s.FromUint64(curveType, uint64(1-scalars[i].IsZero()))
+		result[i] = r.Mul(r, keep)
+	}
+	return result, nil
+}
+
+// InnerProduct returns sum(lhs[i] * rhs[i]).
+func (s scalar) InnerProduct(lhs, rhs []EccScalar) (EccScalar, error) {
+	if len(lhs) != len(rhs) {
+		return nil, errors.New("scalar vectors have different lengths")
+	}
+	if err := checkScalarVector(append(append([]EccScalar{}, lhs...), rhs...)); err != nil {
+		return nil, err
+	}
+	result := s.Zero(lhs[0].CurveType())
+	tmp := s.Zero(lhs[0].CurveType())
+	for i := range lhs {
+		tmp = tmp.Mul(lhs[i], rhs[i])
+		result = result.Add(result, tmp)
+	}
+	tmp.Zeroize()
+	return result, nil
+}
+
+// ElementwiseMul returns the vector lhs[i] * rhs[i].
+func (s scalar) ElementwiseMul(lhs, rhs []EccScalar) ([]EccScalar, error) {
+	if len(lhs) != len(rhs) {
+		return nil, errors.New("scalar vectors have different lengths")
+	}
+	if err := checkScalarVector(append(append([]EccScalar{}, lhs...), rhs...)); err != nil {
+		return nil, err
+	}
+	result := make([]EccScalar, len(lhs))
+	for i := range lhs {
+		result[i] = s.Zero(lhs[i].CurveType()).Mul(lhs[i], rhs[i])
+	}
+	return result, nil
+}
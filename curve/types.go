@@ -1,24 +1,24 @@
 package curve
 
 const (
-	K256 = EccCurveType(1)
+	K256    = EccCurveType(1)
+	P256    = EccCurveType(2)
+	ED25519 = EccCurveType(3)
 )
 
 type EccCurveType int
 
 func FromTag(tag uint8) EccCurveType {
 	t := EccCurveType(0)
-	switch tag {
-	case 1:
-		t = EccCurveType(K256)
+	if lookup(EccCurveType(tag)) != nil {
+		t = EccCurveType(tag)
 	}
 	return t
 }
 func (e EccCurveType) ScalarBits() int {
 	bits := 0
-	switch e {
-	case K256:
-		bits = 256
+	if c := lookup(e); c != nil {
+		bits = c.ScalarBits
 	}
 	return bits
 }
@@ -28,9 +28,8 @@ func (e EccCurveType) ScalarBytes() int {
 
 func (e EccCurveType) FieldBits() int {
 	bits := 0
-	switch e {
-	case K256:
-		bits = 256
+	if c := lookup(e); c != nil {
+		bits = c.FieldBits
 	}
 	return bits
 }
@@ -40,31 +39,31 @@ func (e EccCurveType) FieldBytes() int {
 
 func (e EccCurveType) SecurityLevel() int {
 	level := 0
-	switch e {
-	case K256:
-		level = 128
+	if c := lookup(e); c != nil {
+		level = c.SecurityLevel
 	}
 	return level
 }
 
 func (e EccCurveType) PointBytes() int {
+	if c := lookup(e); c != nil && c.PointBytes != 0 {
+		return c.PointBytes
+	}
 	return 1 + e.FieldBytes()
 }
 
 func (e EccCurveType) Tag() uint8 {
 	tag := uint8(0)
-	switch e {
-	case K256:
-		tag = 1
+	if lookup(e) != nil {
+		tag = uint8(e)
 	}
 	return tag
 }
 
 func (e EccCurveType) String() string {
 	s := ""
-	switch e {
-	case K256:
-		s = "secp256k1"
+	if c := lookup(e); c != nil {
+		s = c.Name
 	}
 	return s
 }
@@ -0,0 +1,187 @@
+package dealings
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/mega"
+	ro2 "github.com/PlatONnetwork/tecdsa/ro"
+	"github.com/PlatONnetwork/tecdsa/zk"
+	"github.com/tidwall/btree"
+)
+
+const batchVerificationDst = "ic-crypto-tecdsa-batch-verify-dealings"
+
+// BatchPubliclyVerify runs PubliclyVerify on the dealings for one transcript
+// and returns the result for every dealer: nil if the dealing is valid.
+//
+// The consistency of the constant terms of reshared dealings with the
+// transcript they reshare, C_i(0) == P1(i) for each dealer i, is checked at
+// once with a random linear combination evaluated as a single
+// multi-scalar multiplication. Only if that fails are the dealings checked
+// one by one to find the offending dealers.
+//
+// The proofs of possession and the zero knowledge proofs are in commitment
+// form, so their verification equations are folded the same way into a
+// zk.BatchVerifier. Again the proofs are only verified per dealing if the
+// combined check fails.
+func BatchPubliclyVerify(dealings *btree.Map[common.NodeIndex, *IDkgDealingInternal], curveType curve.EccCurveType, transcriptType IDkgTranscriptOperationInternal, reconstructionThreshold int, numberOfReceivers int, ad []byte) *btree.Map[common.NodeIndex, error] {
+	results, _ := BatchPubliclyVerifyContext(common.Sequential(), dealings, curveType, transcriptType, reconstructionThreshold, numberOfReceivers, ad)
+	return results
+}
+
+// BatchPubliclyVerifyContext is BatchPubliclyVerify with the per dealing
+// checks spread over the worker pool of ctx. It fails only if ctx ends
+// before all dealings were verified.
+func BatchPubliclyVerifyContext(ctx context.Context, dealings *btree.Map[common.NodeIndex, *IDkgDealingInternal], curveType curve.EccCurveType, transcriptType IDkgTranscriptOperationInternal, reconstructionThreshold int, numberOfReceivers int, ad []byte) (*btree.Map[common.NodeIndex, error], error) {
+	var results btree.Map[common.NodeIndex, error]
+	var remaining btree.Map[common.NodeIndex, *IDkgDealingInternal]
+	dealings.Scan(func(dealerIndex common.NodeIndex, dealing *IDkgDealingInternal) bool {
+		if err := dealing.verifyStructure(curveType, transcriptType, reconstructionThreshold, dealerIndex, numberOfReceivers); err != nil {
+			results.Set(dealerIndex, err)
+		} else {
+			remaining.Set(dealerIndex, dealing)
+		}
+		return true
+	})
+
+	if t, ok := transcriptType.(*ReshareOfUnmaskedTranscript); ok && remaining.Len() > 0 {
+		if batchVerifyConstantTerms(curveType, t, &remaining, ad) != nil {
+			dealers, values := remaining.KeyValues()
+			consistent := make([]bool, len(dealers))
+			err := common.ParallelFor(ctx, len(dealers), func(i int) error {
+				consistent[i] = t.P1.EvaluateAt(dealers[i]).Equal(values[i].Commitment.ConstantTerm()) == 1
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			for i, dealerIndex := range dealers {
+				if !consistent[i] {
+					results.Set(dealerIndex, &common.ErrInvalidDealing{Dealer: dealerIndex, Err: common.ErrInvalidCommitment})
+					remaining.Delete(dealerIndex)
+				}
+			}
+		}
+	}
+
+	dealers, values := remaining.KeyValues()
+	if batchVerifyProofs(ctx, curveType, transcriptType, dealers, values, ad) == nil {
+		for _, dealerIndex := range dealers {
+			results.Set(dealerIndex, nil)
+		}
+		return &results, nil
+	}
+	errs := make([]error, len(dealers))
+	err := common.ParallelFor(ctx, len(dealers), func(i int) error {
+		errs[i] = values[i].verifyProofs(transcriptType, dealers[i], ad)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, dealerIndex := range dealers {
+		results.Set(dealerIndex, errs[i])
+	}
+	return &results, nil
+}
+
+// BatchPrivateVerify runs PrivateVerify on the dealings for one receiver and
+// returns the result for every dealer: nil if the receiver's share is
+// valid.
+func BatchPrivateVerify(dealings *btree.Map[common.NodeIndex, *IDkgDealingInternal], curveType curve.EccCurveType, privateKey *mega.MEGaPrivateKey, publicKey *mega.MEGaPublicKey, ad []byte, recipientIndex common.NodeIndex) *btree.Map[common.NodeIndex, error] {
+	results, _ := BatchPrivateVerifyContext(common.Sequential(), dealings, curveType, privateKey, publicKey, ad, recipientIndex)
+	return results
+}
+
+// BatchPrivateVerifyContext is BatchPrivateVerify with the dealings
+// decrypted on the worker pool of ctx.
+func BatchPrivateVerifyContext(ctx context.Context, dealings *btree.Map[common.NodeIndex, *IDkgDealingInternal], curveType curve.EccCurveType, privateKey *mega.MEGaPrivateKey, publicKey *mega.MEGaPublicKey, ad []byte, recipientIndex common.NodeIndex) (*btree.Map[common.NodeIndex, error], error) {
+	dealers, values := dealings.KeyValues()
+	errs := make([]error, len(dealers))
+	err := common.ParallelFor(ctx, len(dealers), func(i int) error {
+		errs[i] = values[i].PrivateVerify(curveType, privateKey, publicKey, ad, dealers[i], recipientIndex)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var results btree.Map[common.NodeIndex, error]
+	for i, dealerIndex := range dealers {
+		results.Set(dealerIndex, errs[i])
+	}
+	return &results, nil
+}
+
+// batchVerifyProofs checks the proofs of all the dealings with one
+// zk.BatchVerifier. The per dealing parts, which hash the proofs and the
+// bases of the proofs of possession, are built on the worker pool of ctx.
+func batchVerifyProofs(ctx context.Context, curveType curve.EccCurveType, transcriptType IDkgTranscriptOperationInternal, dealers []common.NodeIndex, values []*IDkgDealingInternal, ad []byte) error {
+	if len(dealers) == 0 {
+		return nil
+	}
+	parts := make([]*zk.BatchVerifier, len(dealers))
+	err := common.ParallelFor(ctx, len(dealers), func(i int) error {
+		parts[i] = zk.NewBatchVerifier(curveType)
+		return values[i].addProofs(parts[i], transcriptType, dealers[i], ad)
+	})
+	if err != nil {
+		return err
+	}
+	batch := zk.NewBatchVerifier(curveType)
+	for _, part := range parts {
+		if err := batch.Append(part); err != nil {
+			return err
+		}
+	}
+	return batch.Verify()
+}
+
+// batchVerifyConstantTerms checks sum(r_i * (P1(i) - C_i(0))) == 0 for
+// coefficients r_i derived from all the inputs, which holds with
+// overwhelming probability only if every term is zero. P1(i) is expanded
+// as sum(i^k * P1_k), so the whole sum is one multi-scalar multiplication
+// over the points of P1 and the constant terms.
+func batchVerifyConstantTerms(curveType curve.EccCurveType, t *ReshareOfUnmaskedTranscript, dealings *btree.Map[common.NodeIndex, *IDkgDealingInternal], ad []byte) error {
+	dealers, values := dealings.KeyValues()
+	constantTerms := make([]curve.EccPoint, len(values), len(values))
+	indexes := make([]byte, 4*len(dealers))
+	for i := range values {
+		constantTerms[i] = values[i].Commitment.ConstantTerm()
+		binary.BigEndian.PutUint32(indexes[4*i:], uint32(dealers[i]))
+	}
+	ro := ro2.NewRandomOracle(batchVerificationDst)
+	ro.AddBytesString("associated_data", ad)
+	ro.AddBytesString("dealer_indexes", indexes)
+	ro.AddPoints("transcript_commitment", t.P1.Points())
+	ro.AddPoints("constant_terms", constantTerms)
+	coefficients, err := ro.OutputScalars(curveType, len(dealers))
+	if err != nil {
+		return err
+	}
+
+	p1 := t.P1.Points()
+	scalars := make([]curve.EccScalar, len(p1), len(p1)+len(dealers))
+	for k := range scalars {
+		scalars[k] = curve.Scalar.Zero(curveType)
+	}
+	for i, dealerIndex := range dealers {
+		// r_i * i^k for every coefficient of P1
+		x := curve.Scalar.FromNodeIndex(curveType, dealerIndex)
+		term := coefficients[i].Clone()
+		for k := range p1 {
+			scalars[k] = scalars[k].Add(scalars[k], term)
+			term = term.Mul(term, x)
+		}
+		scalars = append(scalars, coefficients[i].Negated())
+	}
+	sum, err := curve.Point.MultiScalarMul(append(append([]curve.EccPoint{}, p1...), constantTerms...), scalars)
+	if err != nil {
+		return err
+	}
+	if sum.Equal(curve.Point.Identity(curveType)) != 1 {
+		return common.ErrInvalidCommitment
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package dealings
+
+import (
+	"errors"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	poly2 "github.com/PlatONnetwork/tecdsa/poly"
+	seed2 "github.com/PlatONnetwork/tecdsa/seed"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/btree"
+	"testing"
+)
+
+type batchCase struct {
+	transcript IDkgTranscriptOperationInternal
+	shares     func(dealerIndex common.NodeIndex) SecretShares
+}
+
+func batchCases(t *testing.T, curveType curve.EccCurveType, threshold int) []batchCase {
+	rng := genRng()
+	a := poly2.Poly.Random(curveType, threshold, rng)
+	b := poly2.Poly.Random(curveType, threshold, rng)
+	mask := poly2.Poly.Random(curveType, threshold, rng)
+	simple, err := poly2.SimpleCM.Create(a, threshold)
+	assert.Nil(t, err)
+	masked, err := poly2.PedersenCM.Create(a, mask, threshold)
+	assert.Nil(t, err)
+	right, err := poly2.PedersenCM.Create(b, mask, threshold)
+	assert.Nil(t, err)
+	at := func(p *poly2.Polynomial, dealerIndex common.NodeIndex) curve.EccScalar {
+		return p.EvaluateAt(curve.Scalar.FromNodeIndex(curveType, dealerIndex))
+	}
+	return []batchCase{
+		{&RandomTranscript{}, func(common.NodeIndex) SecretShares { return &RandomSecret{} }},
+		{&ReshareOfUnmaskedTranscript{P1: simple}, func(i common.NodeIndex) SecretShares {
+			return &ReshareOfUnmaskedSecret{S1: at(a, i)}
+		}},
+		{&ReshareOfMaskedTranscript{P1: masked}, func(i common.NodeIndex) SecretShares {
+			return &ReshareOfMaskedSecret{S1: at(a, i), S2: at(mask, i)}
+		}},
+		{&UnmaskedTimesMaskedTranscript{Left: simple, Right: right}, func(i common.NodeIndex) SecretShares {
+			return &UnmaskedTimesMaskedSecret{at(a, i), [2]curve.EccScalar{at(b, i), at(mask, i)}}
+		}},
+	}
+}
+
+func TestBatchPubliclyVerifyAcceptsValidDealings(t *testing.T) {
+	ad := []byte{1, 2, 3}
+	threshold := 2
+	for _, curveType := range []curve.EccCurveType{curve.K256, curve.ED25519} {
+		_, publicKeys := genPrivateKeys(curveType, 4)
+		for _, c := range batchCases(t, curveType, threshold) {
+			dealings := new(btree.Map[common.NodeIndex, *IDkgDealingInternal])
+			for dealerIndex := common.NodeIndex(0); dealerIndex < 4; dealerIndex++ {
+				dealing, err := NewIDkgDealingInternal(c.shares(dealerIndex), curveType, seed2.FromRng(genRng()), threshold, publicKeys, dealerIndex, ad)
+				assert.Nil(t, err)
+				assert.Nil(t, dealing.PubliclyVerify(curveType, c.transcript, threshold, dealerIndex, len(publicKeys), ad))
+				dealings.Set(dealerIndex, dealing)
+			}
+			dealers, values := dealings.KeyValues()
+			assert.Nil(t, batchVerifyProofs(common.Sequential(), curveType, c.transcript, dealers, values, ad))
+			results := BatchPubliclyVerify(dealings, curveType, c.transcript, threshold, len(publicKeys), ad)
+			assert.Equal(t, dealings.Keys(), results.Keys())
+			results.Scan(func(dealerIndex common.NodeIndex, err error) bool {
+				assert.Nil(t, err, "%T dealer %d", c.transcript, dealerIndex)
+				return true
+			})
+		}
+	}
+}
+
+func TestBatchPubliclyVerifyIdentifiesOffendingDealers(t *testing.T) {
+	curveType := curve.P256
+	ad := []byte{1, 2, 3}
+	threshold := 2
+	_, publicKeys := genPrivateKeys(curveType, 5)
+	for _, c := range batchCases(t, curveType, threshold) {
+		dealings := new(btree.Map[common.NodeIndex, *IDkgDealingInternal])
+		for dealerIndex := common.NodeIndex(0); dealerIndex < 5; dealerIndex++ {
+			dealing, err := NewIDkgDealingInternal(c.shares(dealerIndex), curveType, seed2.FromRng(genRng()), threshold, publicKeys, dealerIndex, ad)
+			assert.Nil(t, err)
+			dealings.Set(dealerIndex, dealing)
+		}
+		// dealer 1 deals the share of dealer 2, dealer 3 signs for other
+		// associated data and dealer 4 uses the wrong threshold
+		wrongShare, err := NewIDkgDealingInternal(c.shares(2), curveType, seed2.FromRng(genRng()), threshold, publicKeys, 1, ad)
+		assert.Nil(t, err)
+		dealings.Set(1, wrongShare)
+		wrongAd, err := NewIDkgDealingInternal(c.shares(3), curveType, seed2.FromRng(genRng()), threshold, publicKeys, 3, []byte("other"))
+		assert.Nil(t, err)
+		dealings.Set(3, wrongAd)
+		wrongThreshold, err := NewIDkgDealingInternal(c.shares(4), curveType, seed2.FromRng(genRng()), threshold+1, publicKeys, 4, ad)
+		assert.Nil(t, err)
+		dealings.Set(4, wrongThreshold)
+
+		results := BatchPubliclyVerify(dealings, curveType, c.transcript, threshold, len(publicKeys), ad)
+		dealings.Scan(func(dealerIndex common.NodeIndex, dealing *IDkgDealingInternal) bool {
+			err, ok := results.Get(dealerIndex)
+			assert.True(t, ok)
+			individual := dealing.PubliclyVerify(curveType, c.transcript, threshold, dealerIndex, len(publicKeys), ad)
+			assert.Equal(t, individual == nil, err == nil, "%T dealer %d", c.transcript, dealerIndex)
+			return true
+		})
+		for _, dealerIndex := range []common.NodeIndex{0, 2} {
+			err, _ := results.Get(dealerIndex)
+			assert.Nil(t, err)
+		}
+		for _, dealerIndex := range []common.NodeIndex{3, 4} {
+			err, _ := results.Get(dealerIndex)
+			blamed, ok := common.MisbehavingDealer(err)
+			assert.True(t, ok)
+			assert.Equal(t, dealerIndex, blamed)
+		}
+		err, _ = results.Get(1)
+		if _, random := c.transcript.(*RandomTranscript); random {
+			assert.Nil(t, err)
+		} else {
+			assert.True(t, errors.Is(err, common.ErrMisbehavingDealer), "%T", c.transcript)
+		}
+	}
+}
@@ -1,14 +1,31 @@
 package dealings
 
 import (
+	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/pkg/errors"
 )
 
+const (
+	Summation     = CombinedCommitmentType(1)
+	Interpolation = CombinedCommitmentType(2)
+)
+
+var (
+	CombinedCM = combinedCommitment{}
+)
+
+type CombinedCommitmentType int
+
 type CombinedCommitment interface {
 	poly.PolynomialCommitment
 	Serialize() ([]byte, error)
+	SerializeCombined() ([]byte, error)
+	CombinationType() CombinedCommitmentType
 }
 
+type combinedCommitment struct{}
+
 type SummationCommitment struct {
 	poly.PolynomialCommitment
 }
@@ -17,6 +34,17 @@ func (s SummationCommitment) Serialize() ([]byte, error) {
 	return s.PolynomialCommitment.Serialize()
 }
 
+// SerializeCombined encodes the commitment together with how it was
+// combined, so that CombinedCM.Deserialize restores the same wrapper.
+// Serialize keeps encoding the commitment alone.
+func (s SummationCommitment) SerializeCombined() ([]byte, error) {
+	return serializeCombined(Summation, s.PolynomialCommitment)
+}
+
+func (SummationCommitment) CombinationType() CombinedCommitmentType {
+	return Summation
+}
+
 type InterpolationCommitment struct {
 	poly.PolynomialCommitment
 }
@@ -24,3 +52,81 @@ type InterpolationCommitment struct {
 func (i InterpolationCommitment) Serialize() ([]byte, error) {
 	return i.PolynomialCommitment.Serialize()
 }
+
+func (i InterpolationCommitment) SerializeCombined() ([]byte, error) {
+	return serializeCombined(Interpolation, i.PolynomialCommitment)
+}
+
+func (InterpolationCommitment) CombinationType() CombinedCommitmentType {
+	return Interpolation
+}
+
+// combinedCommitmentWire is the versioned encoding of a combined
+// commitment. Exactly one of the commitment fields is set.
+type combinedCommitmentWire struct {
+	Version            uint8
+	CombinationType    CombinedCommitmentType
+	SimpleCommitment   *poly.SimpleCommitment   `cbor:",omitempty" json:",omitempty"`
+	PedersenCommitment *poly.PedersenCommitment `cbor:",omitempty" json:",omitempty"`
+}
+
+func splitCommitment(c poly.PolynomialCommitment) (*poly.SimpleCommitment, *poly.PedersenCommitment, error) {
+	switch cm := c.(type) {
+	case *poly.SimpleCommitment:
+		return cm, nil, nil
+	case *poly.PedersenCommitment:
+		return nil, cm, nil
+	}
+	return nil, nil, common.ErrUnexpectedCommitmentType
+}
+
+// joinCombined rebuilds the wrapper from its decoded parts. It accepts the
+// combinations NewTranscriptInternal produces: a summation of Pedersen
+// commitments, or an interpolation of either type.
+func joinCombined(combinationType CombinedCommitmentType, simple *poly.SimpleCommitment, pedersen *poly.PedersenCommitment) (CombinedCommitment, error) {
+	var c poly.PolynomialCommitment
+	switch {
+	case simple != nil && pedersen == nil:
+		c = simple
+	case pedersen != nil && simple == nil:
+		c = pedersen
+	default:
+		return nil, errors.Wrap(common.ErrInvalidCommitment, "combined commitment needs exactly one commitment")
+	}
+	switch combinationType {
+	case Summation:
+		if c.Type() != poly.Pedersen {
+			return nil, common.ErrUnexpectedCommitmentType
+		}
+		return &SummationCommitment{c}, nil
+	case Interpolation:
+		return &InterpolationCommitment{c}, nil
+	}
+	return nil, errors.Wrap(common.ErrUnexpectedCommitmentType, "unknown combination type")
+}
+
+func serializeCombined(combinationType CombinedCommitmentType, c poly.PolynomialCommitment) ([]byte, error) {
+	simple, pedersen, err := splitCommitment(c)
+	if err != nil {
+		return nil, err
+	}
+	return common.MarshalCanonical(&combinedCommitmentWire{
+		Version:            common.WireVersion,
+		CombinationType:    combinationType,
+		SimpleCommitment:   simple,
+		PedersenCommitment: pedersen,
+	})
+}
+
+// Deserialize decodes a combined commitment produced by SerializeCombined
+// into the wrapper type it was encoded from.
+func (combinedCommitment) Deserialize(data []byte) (CombinedCommitment, error) {
+	var w combinedCommitmentWire
+	if err := common.UnmarshalStrict(data, &w); err != nil {
+		return nil, err
+	}
+	if w.Version != common.WireVersion {
+		return nil, errors.Wrapf(common.ErrInvalidEncoding, "unsupported combined commitment version %d", w.Version)
+	}
+	return joinCombined(w.CombinationType, w.SimpleCommitment, w.PedersenCommitment)
+}
@@ -1,11 +1,11 @@
 package dealings
 
 import (
+	"context"
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
 	"github.com/PlatONnetwork/tecdsa/mega"
 	"github.com/PlatONnetwork/tecdsa/poly"
-	"github.com/pkg/errors"
 	"github.com/tidwall/btree"
 )
 
@@ -40,30 +40,31 @@ func (c commitmentOpening) FromDealingsAndOpenings(verifiedDealings *btree.Map[c
 		commitmentOpenings = append(commitmentOpenings, opening)
 		return true
 	})
+	defer zeroizeOpenings(commitmentOpenings)
 	if err != nil {
 		return nil, err
 	}
 	return c.CombineOpenings(nodeIndexOpenings, commitmentOpenings, transcriptCommitment, receiverIndex, secretKey.CurveType())
 }
 func (c commitmentOpening) FromDealings(verifiedDealings *btree.Map[common.NodeIndex, *IDkgDealingInternal], transcriptCommitment CombinedCommitment, contextData []byte, receiverIndex common.NodeIndex, secretKey *mega.MEGaPrivateKey, publicKey *mega.MEGaPublicKey) (poly.CommitmentOpening, error) {
-	nodeIndexOpenings := make([]common.NodeIndex, 0, verifiedDealings.Len())
-	commitmentOpenings := make([]poly.CommitmentOpening, 0, verifiedDealings.Len())
-	var err error
-	verifiedDealings.Scan(func(dealerIndex common.NodeIndex, dealing *IDkgDealingInternal) bool {
-		var opening poly.CommitmentOpening
-		if opening, err = dealing.Ciphertext.DecryptAndCheck(dealing.Commitment, contextData, dealerIndex, receiverIndex, secretKey, publicKey); err != nil {
-			return false
-		}
+	return c.FromDealingsContext(common.Sequential(), verifiedDealings, transcriptCommitment, contextData, receiverIndex, secretKey, publicKey)
+}
 
-		nodeIndexOpenings = append(nodeIndexOpenings, dealerIndex)
-		commitmentOpenings = append(commitmentOpenings, opening)
-		return true
+// FromDealingsContext is FromDealings with the dealings decrypted on the
+// worker pool of ctx.
+func (c commitmentOpening) FromDealingsContext(ctx context.Context, verifiedDealings *btree.Map[common.NodeIndex, *IDkgDealingInternal], transcriptCommitment CombinedCommitment, contextData []byte, receiverIndex common.NodeIndex, secretKey *mega.MEGaPrivateKey, publicKey *mega.MEGaPublicKey) (poly.CommitmentOpening, error) {
+	nodeIndexOpenings, dealings := verifiedDealings.KeyValues()
+	commitmentOpenings := make([]poly.CommitmentOpening, len(dealings), len(dealings))
+	defer zeroizeOpenings(commitmentOpenings)
+	err := common.ParallelFor(ctx, len(dealings), func(i int) error {
+		opening, err := dealings[i].Ciphertext.DecryptAndCheck(dealings[i].Commitment, contextData, nodeIndexOpenings[i], receiverIndex, secretKey, publicKey)
+		commitmentOpenings[i] = opening
+		return err
 	})
 	if err != nil {
 		return nil, err
 	}
 	return c.CombineOpenings(nodeIndexOpenings, commitmentOpenings, transcriptCommitment, receiverIndex, secretKey.CurveType())
-
 }
 
 func (commitmentOpening) CombineOpenings(nodeIndexOpenings []common.NodeIndex, commitmentOpenings []poly.CommitmentOpening, transcriptCommitment CombinedCommitment, receiverIndex common.NodeIndex, curveType curve.EccCurveType) (poly.CommitmentOpening, error) {
@@ -79,7 +80,7 @@ func (commitmentOpening) CombineOpenings(nodeIndexOpenings []common.NodeIndex, c
 				combinedValue = combinedValue.Add(combinedValue, o[0])
 				combinedMask = combinedMask.Add(combinedMask, o[1])
 			default:
-				return nil, errors.New("unexpected commitment type")
+				return nil, common.ErrUnexpectedCommitmentType
 			}
 		}
 		opening = poly.PedersenCommitmentOpening([2]curve.EccScalar{combinedValue, combinedMask})
@@ -95,7 +96,7 @@ func (commitmentOpening) CombineOpenings(nodeIndexOpenings []common.NodeIndex, c
 					xValues = append(xValues, dealerIndex)
 					values = append(values, o[0])
 				default:
-					return nil, errors.New("unexpected commitment type")
+					return nil, common.ErrUnexpectedCommitmentType
 				}
 			}
 
@@ -121,7 +122,7 @@ func (commitmentOpening) CombineOpenings(nodeIndexOpenings []common.NodeIndex, c
 					values = append(values, o[0])
 					masks = append(masks, o[1])
 				default:
-					return nil, errors.New("unexpected commitment type")
+					return nil, common.ErrUnexpectedCommitmentType
 				}
 			}
 			coefficients, err := poly.Lagrange.AtZero(curveType, xValues)
@@ -139,5 +140,23 @@ func (commitmentOpening) CombineOpenings(nodeIndexOpenings []common.NodeIndex, c
 			opening = poly.PedersenCommitmentOpening([2]curve.EccScalar{combinedValue, combinedMask})
 		}
 	}
-	return transcriptCommitment.ReturnOpeningIfConsistent(receiverIndex, opening)
+	if opening == nil {
+		return nil, common.ErrUnexpectedCommitmentType
+	}
+	consistent, err := transcriptCommitment.ReturnOpeningIfConsistent(receiverIndex, opening)
+	if err != nil {
+		opening.Zeroize()
+		return nil, err
+	}
+	return consistent, nil
+}
+
+// zeroizeOpenings wipes the per dealer openings once they have been combined
+// into the receiver's share.
+func zeroizeOpenings(openings []poly.CommitmentOpening) {
+	for _, o := range openings {
+		if o != nil {
+			o.Zeroize()
+		}
+	}
 }
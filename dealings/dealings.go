@@ -1,6 +1,7 @@
 package dealings
 
 import (
+	"context"
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
 	"github.com/PlatONnetwork/tecdsa/mega"
@@ -11,6 +12,10 @@ import (
 )
 
 func EncryptAndCommitSinglePolynomial(poly *poly2.Polynomial, num int, recipients []*mega.MEGaPublicKey, dealerIndex common.NodeIndex, ad []byte, seed *seed.Seed) (mega.MEGaCiphertext, poly2.PolynomialCommitment, error) {
+	return encryptAndCommitSinglePolynomial(common.Sequential(), poly, num, recipients, dealerIndex, ad, seed)
+}
+
+func encryptAndCommitSinglePolynomial(ctx context.Context, poly *poly2.Polynomial, num int, recipients []*mega.MEGaPublicKey, dealerIndex common.NodeIndex, ad []byte, seed *seed.Seed) (mega.MEGaCiphertext, poly2.PolynomialCommitment, error) {
 	curveType := poly.CurveType()
 	plaintexts := make([]curve.EccScalar, len(recipients), len(recipients))
 
@@ -19,8 +24,9 @@ func EncryptAndCommitSinglePolynomial(poly *poly2.Polynomial, num int, recipient
 		vs := poly.EvaluateAt(scalar)
 		plaintexts[idx] = vs
 	}
+	defer curve.Scalar.Zeroize(plaintexts...)
 
-	ciphertext, err := mega.EncryptCiphertextSingle(seed, plaintexts, recipients, dealerIndex, ad)
+	ciphertext, err := mega.EncryptCiphertextSingleContext(ctx, seed, plaintexts, recipients, dealerIndex, ad)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -32,6 +38,10 @@ func EncryptAndCommitSinglePolynomial(poly *poly2.Polynomial, num int, recipient
 }
 
 func EncryptAndCommitPairPolynomial(values *poly2.Polynomial, mask *poly2.Polynomial, num int, recipients []*mega.MEGaPublicKey, dealerIndex common.NodeIndex, ad []byte, seed *seed.Seed) (mega.MEGaCiphertext, poly2.PolynomialCommitment, error) {
+	return encryptAndCommitPairPolynomial(common.Sequential(), values, mask, num, recipients, dealerIndex, ad, seed)
+}
+
+func encryptAndCommitPairPolynomial(ctx context.Context, values *poly2.Polynomial, mask *poly2.Polynomial, num int, recipients []*mega.MEGaPublicKey, dealerIndex common.NodeIndex, ad []byte, seed *seed.Seed) (mega.MEGaCiphertext, poly2.PolynomialCommitment, error) {
 	curveType := values.CurveType()
 	plaintexts := make([][2]curve.EccScalar, len(recipients), len(recipients))
 	for idx, _ := range recipients {
@@ -40,7 +50,12 @@ func EncryptAndCommitPairPolynomial(values *poly2.Polynomial, mask *poly2.Polyno
 		ms := mask.EvaluateAt(scalar)
 		plaintexts[idx] = [2]curve.EccScalar{vs, ms}
 	}
-	ciphertext, err := mega.EncryptCiphertextPair(seed, plaintexts, recipients, dealerIndex, ad)
+	defer func() {
+		for _, p := range plaintexts {
+			curve.Scalar.Zeroize(p[:]...)
+		}
+	}()
+	ciphertext, err := mega.EncryptCiphertextPairContext(ctx, seed, plaintexts, recipients, dealerIndex, ad)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -58,13 +73,21 @@ type IDkgDealingInternal struct {
 }
 
 func NewIDkgDealingInternal(shares SecretShares, curveType curve.EccCurveType, seed *seed.Seed, threshold int, recipients []*mega.MEGaPublicKey, dealerIndex common.NodeIndex, ad []byte) (*IDkgDealingInternal, error) {
+	return NewIDkgDealingInternalContext(common.Sequential(), shares, curveType, seed, threshold, recipients, dealerIndex, ad)
+}
+
+// NewIDkgDealingInternalContext is NewIDkgDealingInternal with the
+// encryption to the recipients spread over the worker pool of ctx. It
+// returns the same dealing as the sequential function for the same seed.
+func NewIDkgDealingInternalContext(ctx context.Context, shares SecretShares, curveType curve.EccCurveType, seed *seed.Seed, threshold int, recipients []*mega.MEGaPublicKey, dealerIndex common.NodeIndex, ad []byte) (*IDkgDealingInternal, error) {
 	if threshold == 0 || threshold > len(recipients) {
-		return nil, errors.New("invalid threshold")
+		return nil, common.ErrInvalidThreshold
 	}
 
 	numCoefficients := threshold
 	polyRng := seed.Derive("ic-crypto-tecdsa-create-dealing-polynomials").Rng()
 	megaSeed := seed.Derive("ic-crypto-tecdsa-create-dealing-mega-encrypt")
+	defer megaSeed.Zeroize()
 	var commitment poly2.PolynomialCommitment
 	var ciphertext mega.MEGaCiphertext
 	var proof ZkProof
@@ -73,7 +96,9 @@ func NewIDkgDealingInternal(shares SecretShares, curveType curve.EccCurveType, s
 	case *RandomSecret:
 		values := poly2.Poly.Random(curveType, numCoefficients, polyRng)
 		mask := poly2.Poly.Random(curveType, numCoefficients, polyRng)
-		ciphertext, commitment, err = EncryptAndCommitPairPolynomial(values, mask, numCoefficients, recipients, dealerIndex, ad, megaSeed)
+		defer values.Zeroize()
+		defer mask.Zeroize()
+		ciphertext, commitment, err = encryptAndCommitPairPolynomial(ctx, values, mask, numCoefficients, recipients, dealerIndex, ad, megaSeed)
 		if err != nil {
 			return nil, err
 		}
@@ -82,7 +107,8 @@ func NewIDkgDealingInternal(shares SecretShares, curveType curve.EccCurveType, s
 		if err != nil {
 			return nil, err
 		}
-		ciphertext, commitment, err = EncryptAndCommitSinglePolynomial(values, numCoefficients, recipients, dealerIndex, ad, megaSeed)
+		defer values.Zeroize()
+		ciphertext, commitment, err = encryptAndCommitSinglePolynomial(ctx, values, numCoefficients, recipients, dealerIndex, ad, megaSeed)
 		if err != nil {
 			return nil, err
 		}
@@ -91,7 +117,8 @@ func NewIDkgDealingInternal(shares SecretShares, curveType curve.EccCurveType, s
 		if err != nil {
 			return nil, err
 		}
-		if ciphertext, commitment, err = EncryptAndCommitSinglePolynomial(values, numCoefficients, recipients, dealerIndex, ad, megaSeed); err != nil {
+		defer values.Zeroize()
+		if ciphertext, commitment, err = encryptAndCommitSinglePolynomial(ctx, values, numCoefficients, recipients, dealerIndex, ad, megaSeed); err != nil {
 			return nil, err
 		}
 		if p, err := zk.ProofOfEqualOpeningsIns.Create(seed.Derive(zk.ProofOfEqualOpeningsDst), s.S1, s.S2, ad); err != nil {
@@ -103,12 +130,18 @@ func NewIDkgDealingInternal(shares SecretShares, curveType curve.EccCurveType, s
 	case *UnmaskedTimesMaskedSecret:
 		product := s.Left.Clone().Mul(s.Left, s.Right[0])
 		productMasking := curve.Scalar.Random(curveType, polyRng)
+		defer curve.Scalar.Zeroize(product, productMasking)
 		values, err := poly2.Poly.RandomWithConstant(product, numCoefficients, polyRng)
 		if err != nil {
 			return nil, err
 		}
+		defer values.Zeroize()
 		mask, err := poly2.Poly.RandomWithConstant(productMasking, numCoefficients, polyRng)
-		if ciphertext, commitment, err = EncryptAndCommitPairPolynomial(values, mask, numCoefficients, recipients, dealerIndex, ad, megaSeed); err != nil {
+		if err != nil {
+			return nil, err
+		}
+		defer mask.Zeroize()
+		if ciphertext, commitment, err = encryptAndCommitPairPolynomial(ctx, values, mask, numCoefficients, recipients, dealerIndex, ad, megaSeed); err != nil {
 			return nil, err
 		}
 		pf, err := zk.ProofOfProductIns.Create(seed.Derive(zk.ProofOfProductDst), s.Left, s.Right[0], s.Right[1], product, productMasking, ad)
@@ -119,77 +152,117 @@ func NewIDkgDealingInternal(shares SecretShares, curveType curve.EccCurveType, s
 
 }
 func (dealing IDkgDealingInternal) PubliclyVerify(curveType curve.EccCurveType, transcriptType IDkgTranscriptOperationInternal, reconstructionThreshold int, dealerIndex common.NodeIndex, numberOfReceivers int, ad []byte) error {
+	if err := dealing.verifyStructure(curveType, transcriptType, reconstructionThreshold, dealerIndex, numberOfReceivers); err != nil {
+		return err
+	}
+	if t, ok := transcriptType.(*ReshareOfUnmaskedTranscript); ok {
+		if t.P1.EvaluateAt(dealerIndex).Equal(dealing.Commitment.ConstantTerm()) != 1 {
+			return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: common.ErrInvalidCommitment}
+		}
+	}
+	return dealing.verifyProofs(transcriptType, dealerIndex, ad)
+}
+
+// verifyStructure checks that the dealing has the shape the transcript
+// operation expects: sizes, curves, ciphertext, commitment and proof types.
+// It does no expensive group operations.
+func (dealing IDkgDealingInternal) verifyStructure(curveType curve.EccCurveType, transcriptType IDkgTranscriptOperationInternal, reconstructionThreshold int, dealerIndex common.NodeIndex, numberOfReceivers int) error {
 	if dealing.Commitment.Len() != reconstructionThreshold {
-		return errors.New("invalid commitment")
+		return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: common.ErrInvalidCommitment}
 	}
 	if dealing.Commitment.CurveType() != curveType {
-		return errors.New("curve mismatch")
+		return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: common.ErrCurveMismatch}
 	}
-	if err := dealing.Ciphertext.CheckValidity(numberOfReceivers, ad, dealerIndex); err != nil {
-		return err
+	if dealing.Ciphertext.Recipients() != numberOfReceivers {
+		return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: errors.Wrap(common.ErrInvalidCiphertext, "invalid recipients")}
 	}
 
-	if _, ok := transcriptType.(*RandomTranscript); ok && dealing.Proof == nil {
-		if err := dealing.Commitment.VerifyIs(poly2.Pedersen, curveType); err != nil {
-			return err
-		}
-		if err := dealing.Ciphertext.VerifyIs(mega.CiphertextPairs, curveType); err != nil {
-			return err
-		}
-	} else if t, ok := transcriptType.(*ReshareOfMaskedTranscript); ok && dealing.Proof != nil && dealing.Proof.Type() == ProofOfMaskedResharing {
-		if err := dealing.Commitment.VerifyIs(poly2.Simple, curveType); err != nil {
-			return err
+	var commitmentType poly2.PolynomialCommitmentType
+	var ciphertextType mega.MEGaCiphertextType
+	switch t := transcriptType.(type) {
+	case *RandomTranscript:
+		if dealing.Proof != nil {
+			return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: errors.Wrap(common.ErrUnexpectedProof, "proof for another transcript operation")}
 		}
-		if err := dealing.Ciphertext.VerifyIs(mega.CiphertextSingle, curveType); err != nil {
-			return err
+		commitmentType, ciphertextType = poly2.Pedersen, mega.CiphertextPairs
+	case *ReshareOfMaskedTranscript:
+		if dealing.Proof == nil || dealing.Proof.Type() != ProofOfMaskedResharing {
+			return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: errors.Wrap(common.ErrUnexpectedProof, "proof for another transcript operation")}
 		}
-		if err := dealing.Proof.(*MaskedResharingProof).Verify(t.P1.EvaluateAt(dealerIndex), dealing.Commitment.ConstantTerm(), ad); err != nil {
-			return err
-		}
-	} else if t, ok := transcriptType.(*ReshareOfUnmaskedTranscript); ok && dealing.Proof == nil {
-		if err := dealing.Commitment.VerifyIs(poly2.Simple, curveType); err != nil {
-			return err
+		commitmentType, ciphertextType = poly2.Simple, mega.CiphertextSingle
+	case *ReshareOfUnmaskedTranscript:
+		if dealing.Proof != nil {
+			return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: errors.Wrap(common.ErrUnexpectedProof, "proof for another transcript operation")}
 		}
 		if err := t.P1.VerifyIs(poly2.Simple, curveType); err != nil {
-			return err
-		}
-		if err := dealing.Ciphertext.VerifyIs(mega.CiphertextSingle, curveType); err != nil {
-			return err
+			return errors.Wrap(err, "reshared commitment")
 		}
-		switch c := t.P1.(type) {
-		case *poly2.PedersenCommitment:
-			return errors.New("unexpected commitment type")
-		case *poly2.SimpleCommitment:
-			if c.EvaluateAt(dealerIndex).Equal(dealing.Commitment.ConstantTerm()) != 1 {
-				return errors.New("invalid commitment")
-			}
-		}
-	} else if t, ok := transcriptType.(*UnmaskedTimesMaskedTranscript); ok && dealing.Proof.Type() == ProofOfProduct {
-		if err := dealing.Commitment.VerifyIs(poly2.Pedersen, curveType); err != nil {
-			return err
-		}
-		if err := dealing.Ciphertext.VerifyIs(mega.CiphertextPairs, curveType); err != nil {
-			return err
+		commitmentType, ciphertextType = poly2.Simple, mega.CiphertextSingle
+	case *UnmaskedTimesMaskedTranscript:
+		if dealing.Proof == nil || dealing.Proof.Type() != ProofOfProduct {
+			return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: errors.Wrap(common.ErrUnexpectedProof, "proof for another transcript operation")}
 		}
 		if err := t.Left.VerifyIs(poly2.Simple, curveType); err != nil {
-			return err
+			return errors.Wrap(err, "left factor")
 		}
 		if err := t.Right.VerifyIs(poly2.Pedersen, curveType); err != nil {
-			return err
-		}
-		if err := dealing.Proof.(*ProductProof).Verify(t.Left.EvaluateAt(dealerIndex), t.Right.EvaluateAt(dealerIndex), dealing.Commitment.ConstantTerm(), ad); err != nil {
-			return err
+			return errors.Wrap(err, "right factor")
 		}
-	} else {
-		return errors.New("invalid proof")
+		commitmentType, ciphertextType = poly2.Pedersen, mega.CiphertextPairs
+	default:
+		return common.ErrUnknownOperation
+	}
+	if err := dealing.Commitment.VerifyIs(commitmentType, curveType); err != nil {
+		return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: err}
+	}
+	if err := dealing.Ciphertext.VerifyIs(ciphertextType, curveType); err != nil {
+		return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: err}
+	}
+	return nil
+}
+
+// verifyProofs verifies the proof of possession of the ciphertext and the
+// zero knowledge proof of the dealing. verifyStructure must have passed.
+func (dealing IDkgDealingInternal) verifyProofs(transcriptType IDkgTranscriptOperationInternal, dealerIndex common.NodeIndex, ad []byte) error {
+	c := dealing.Ciphertext
+	if err := mega.VerifyPop(c.CType(), ad, dealerIndex, c.Ephemeral(), c.PopPublic(), c.Proof()); err != nil {
+		// the proof of possession belongs to the ciphertext
+		return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: err}
+	}
+	var err error
+	switch t := transcriptType.(type) {
+	case *ReshareOfMaskedTranscript:
+		err = dealing.Proof.(*MaskedResharingProof).Verify(t.P1.EvaluateAt(dealerIndex), dealing.Commitment.ConstantTerm(), ad)
+	case *UnmaskedTimesMaskedTranscript:
+		err = dealing.Proof.(*ProductProof).Verify(t.Left.EvaluateAt(dealerIndex), t.Right.EvaluateAt(dealerIndex), dealing.Commitment.ConstantTerm(), ad)
+	}
+	if err != nil {
+		return &common.ErrInvalidProof{Dealer: dealerIndex, Err: err}
+	}
+	return nil
+}
+
+// addProofs adds the checks of verifyProofs to batch.
+func (dealing IDkgDealingInternal) addProofs(batch *zk.BatchVerifier, transcriptType IDkgTranscriptOperationInternal, dealerIndex common.NodeIndex, ad []byte) error {
+	c := dealing.Ciphertext
+	if err := mega.AddPopToBatch(batch, c.CType(), ad, dealerIndex, c.Ephemeral(), c.PopPublic(), c.Proof()); err != nil {
+		return err
+	}
+	switch t := transcriptType.(type) {
+	case *ReshareOfMaskedTranscript:
+		return batch.AddEqualOpenings(dealing.Proof.(*MaskedResharingProof).ProofOfEqualOpenings, t.P1.EvaluateAt(dealerIndex), dealing.Commitment.ConstantTerm(), ad)
+	case *UnmaskedTimesMaskedTranscript:
+		return batch.AddProduct(dealing.Proof.(*ProductProof).ProofOfProduct, t.Left.EvaluateAt(dealerIndex), t.Right.EvaluateAt(dealerIndex), dealing.Commitment.ConstantTerm(), ad)
 	}
 	return nil
 }
 
 func (dealing IDkgDealingInternal) PrivateVerify(curveType curve.EccCurveType, privateKey *mega.MEGaPrivateKey, publicKey *mega.MEGaPublicKey, ad []byte, dealerIndex common.NodeIndex, recipientIndex common.NodeIndex) error {
 	if privateKey.CurveType() != curveType || publicKey.CurveType() != curveType || dealing.Commitment.ConstantTerm().CurveType() != curveType {
-		return errors.New("curve mismatch")
+		return common.ErrCurveMismatch
 	}
-	_, err := dealing.Ciphertext.DecryptAndCheck(dealing.Commitment, ad, dealerIndex, recipientIndex, privateKey, publicKey)
-	return err
+	if _, err := dealing.Ciphertext.DecryptAndCheck(dealing.Commitment, ad, dealerIndex, recipientIndex, privateKey, publicKey); err != nil {
+		return &common.ErrInvalidDealing{Dealer: dealerIndex, Err: err}
+	}
+	return nil
 }
@@ -1,7 +1,9 @@
 package dealings
 
 import (
+	"context"
 	crand "crypto/rand"
+	"errors"
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
 	"github.com/PlatONnetwork/tecdsa/mega"
@@ -9,6 +11,7 @@ import (
 	"github.com/PlatONnetwork/tecdsa/rand"
 	seed2 "github.com/PlatONnetwork/tecdsa/seed"
 	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/btree"
 	"testing"
 )
 
@@ -111,5 +114,148 @@ func TestInvalidCreateDealingRequests(t *testing.T) {
 
 	shares := &RandomSecret{}
 	_, err := NewIDkgDealingInternal(shares, curveType, seed2.FromRng(rng), len(publicKeys)+1, publicKeys, dealerIndex, ad)
+	assert.True(t, errors.Is(err, common.ErrInvalidThreshold))
+}
+
+func TestVerificationErrorsIdentifyTheDealer(t *testing.T) {
+	curveType := curve.K256
+	rng := genRng()
+	ad := []byte{1, 2, 3}
+	privateKeys, publicKeys := genPrivateKeys(curveType, 4)
+	dealerIndex := common.NodeIndex(2)
+
+	random, err := NewIDkgDealingInternal(&RandomSecret{}, curveType, seed2.FromRng(rng), 2, publicKeys, dealerIndex, ad)
+	assert.Nil(t, err)
+	err = random.PubliclyVerify(curveType, &RandomTranscript{}, 2, dealerIndex, len(publicKeys), []byte("other"))
+	var invalidDealing *common.ErrInvalidDealing
+	assert.True(t, errors.As(err, &invalidDealing))
+	assert.Equal(t, dealerIndex, invalidDealing.Dealer)
+	err = random.PubliclyVerify(curveType, &RandomTranscript{}, 3, dealerIndex, len(publicKeys), ad)
+	assert.True(t, errors.Is(err, common.ErrInvalidCommitment))
+	assert.True(t, errors.Is(err, common.ErrMisbehavingDealer))
+
+	random.Ciphertext.(*mega.MEGaCiphertextPair).CTexts[1][0] = curve.Scalar.Random(curveType, rng)
+	err = random.PrivateVerify(curveType, privateKeys[1], publicKeys[1], ad, dealerIndex, 1)
+	dealer, ok := common.MisbehavingDealer(err)
+	assert.True(t, ok)
+	assert.Equal(t, dealerIndex, dealer)
+
+	secret, mask := curve.Scalar.Random(curveType, rng), curve.Scalar.Random(curveType, rng)
+	masked, err := NewIDkgDealingInternal(&ReshareOfMaskedSecret{S1: secret, S2: mask}, curveType, seed2.FromRng(rng), 2, publicKeys, dealerIndex, ad)
+	assert.Nil(t, err)
+	err = masked.PubliclyVerify(curveType, &RandomTranscript{}, 2, dealerIndex, len(publicKeys), ad)
+	assert.True(t, errors.As(err, &invalidDealing))
+	assert.Equal(t, dealerIndex, invalidDealing.Dealer)
+	assert.True(t, errors.Is(err, common.ErrUnexpectedProof))
+	other := poly2.PedersenCM.New([]curve.EccPoint{curve.Point.Pedersen(mask, secret), curve.Point.Pedersen(mask, secret)})
+	err = masked.PubliclyVerify(curveType, &ReshareOfMaskedTranscript{P1: other}, 2, dealerIndex, len(publicKeys), ad)
+	var invalidProof *common.ErrInvalidProof
+	assert.True(t, errors.As(err, &invalidProof))
+	assert.Equal(t, dealerIndex, invalidProof.Dealer)
+	assert.True(t, errors.Is(err, common.ErrProofVerification))
+
+	verified := new(btree.Map[common.NodeIndex, *IDkgDealingInternal])
+	verified.Set(dealerIndex, masked)
+	_, err = NewTranscriptInternal(curveType, 2, verified, &ReshareOfMaskedTranscript{P1: other})
+	var insufficient *common.ErrInsufficientDealings
+	assert.True(t, errors.As(err, &insufficient))
+	assert.Equal(t, 1, insufficient.Have)
+	assert.Equal(t, 2, insufficient.Need)
+	assert.False(t, errors.Is(err, common.ErrMisbehavingDealer))
+}
+
+func TestParallelVariantsMatchTheSequentialOnes(t *testing.T) {
+	curveType := curve.K256
+	ad := []byte{1, 2, 3}
+	threshold := 3
+	privateKeys, publicKeys := genPrivateKeys(curveType, 7)
+	ctx := common.WithParallelism(context.Background(), 4)
+
+	verified := new(btree.Map[common.NodeIndex, *IDkgDealingInternal])
+	for dealerIndex := common.NodeIndex(0); dealerIndex < 5; dealerIndex++ {
+		seed := seed2.FromBytes([]byte{byte(dealerIndex)})
+		sequential, err := NewIDkgDealingInternal(&RandomSecret{}, curveType, seed, threshold, publicKeys, dealerIndex, ad)
+		assert.Nil(t, err)
+		parallel, err := NewIDkgDealingInternalContext(ctx, &RandomSecret{}, curveType, seed, threshold, publicKeys, dealerIndex, ad)
+		assert.Nil(t, err)
+		expected, err := sequential.Serialize()
+		assert.Nil(t, err)
+		actual, err := parallel.Serialize()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, actual)
+		verified.Set(dealerIndex, parallel)
+	}
+
+	public, err := BatchPubliclyVerifyContext(ctx, verified, curveType, &RandomTranscript{}, threshold, len(publicKeys), ad)
+	assert.Nil(t, err)
+	assert.Equal(t, BatchPubliclyVerify(verified, curveType, &RandomTranscript{}, threshold, len(publicKeys), ad).Values(), public.Values())
+	private, err := BatchPrivateVerifyContext(ctx, verified, curveType, privateKeys[2], publicKeys[2], ad, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []error{nil, nil, nil, nil, nil}, private.Values())
+
+	transcript, err := NewTranscriptInternal(curveType, threshold, verified, &RandomTranscript{})
+	assert.Nil(t, err)
+	parallelTranscript, err := NewTranscriptInternalContext(ctx, curveType, threshold, verified, &RandomTranscript{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, transcript.CombinedCommitment.Equal(parallelTranscript.CombinedCommitment))
+
+	for receiver := range privateKeys {
+		expected, err := CommitmentOpening.FromDealings(verified, transcript.CombinedCommitment, ad, common.NodeIndex(receiver), privateKeys[receiver], publicKeys[receiver])
+		assert.Nil(t, err)
+		actual, err := CommitmentOpening.FromDealingsContext(ctx, verified, transcript.CombinedCommitment, ad, common.NodeIndex(receiver), privateKeys[receiver], publicKeys[receiver])
+		assert.Nil(t, err)
+		assert.Equal(t, expected.ToCommitmentOpeningBytes(), actual.ToCommitmentOpeningBytes())
+	}
+
+	// reshare the transcript to exercise the parallel interpolation
+	reshared := new(btree.Map[common.NodeIndex, *IDkgDealingInternal])
+	for dealerIndex := common.NodeIndex(0); dealerIndex < 4; dealerIndex++ {
+		opening, err := CommitmentOpening.FromDealings(verified, transcript.CombinedCommitment, ad, dealerIndex, privateKeys[dealerIndex], publicKeys[dealerIndex])
+		assert.Nil(t, err)
+		o := opening.(poly2.PedersenCommitmentOpening)
+		dealing, err := NewIDkgDealingInternalContext(ctx, &ReshareOfMaskedSecret{S1: o[0], S2: o[1]}, curveType, seed2.FromRng(genRng()), threshold, publicKeys, dealerIndex, ad)
+		assert.Nil(t, err)
+		reshared.Set(dealerIndex, dealing)
+	}
+	operation := &ReshareOfMaskedTranscript{P1: transcript.CombinedCommitment}
+	sequentialReshare, err := NewTranscriptInternal(curveType, threshold, reshared, operation)
+	assert.Nil(t, err)
+	parallelReshare, err := NewTranscriptInternalContext(ctx, curveType, threshold, reshared, operation)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sequentialReshare.CombinedCommitment.Equal(parallelReshare.CombinedCommitment))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = NewIDkgDealingInternalContext(canceled, &RandomSecret{}, curveType, seed2.FromRng(genRng()), threshold, publicKeys, 0, ad)
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = BatchPubliclyVerifyContext(canceled, verified, curveType, &RandomTranscript{}, threshold, len(publicKeys), ad)
+	assert.True(t, errors.Is(err, context.Canceled))
+	_, err = CommitmentOpening.FromDealingsContext(canceled, verified, transcript.CombinedCommitment, ad, 0, privateKeys[0], publicKeys[0])
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestRequiredDealings(t *testing.T) {
+	points := func(n int) []curve.EccPoint {
+		r := make([]curve.EccPoint, n, n)
+		for i := range r {
+			r[i] = curve.Point.GeneratorG(curve.K256)
+		}
+		return r
+	}
+	cases := []struct {
+		operation IDkgTranscriptOperationInternal
+		need      int
+	}{
+		{&RandomTranscript{}, 1},
+		{&ReshareOfMaskedTranscript{P1: poly2.PedersenCM.New(points(3))}, 3},
+		{&ReshareOfUnmaskedTranscript{P1: poly2.SimpleCM.New(points(2))}, 2},
+		{&UnmaskedTimesMaskedTranscript{Left: poly2.SimpleCM.New(points(2)), Right: poly2.PedersenCM.New(points(3))}, 4},
+	}
+	for _, c := range cases {
+		need, err := RequiredDealings(c.operation)
+		assert.Nil(t, err)
+		assert.Equal(t, c.need, need)
+	}
+	_, err := RequiredDealings(nil)
 	assert.NotNil(t, err)
 }
@@ -0,0 +1,258 @@
+package dealings
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/mega"
+	poly2 "github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/PlatONnetwork/tecdsa/zk"
+	"github.com/pkg/errors"
+)
+
+var (
+	Dealing = idkgDealing{}
+)
+
+type idkgDealing struct{}
+
+// idkgDealingWire is the versioned encoding of a dealing. Exactly one of
+// the ciphertext and one of the commitment fields is set, and at most one
+// of the proof fields.
+type idkgDealingWire struct {
+	Version              uint8
+	CurveType            curve.EccCurveType
+	CiphertextSingle     *mega.MEGaCiphertextSingle `cbor:",omitempty" json:",omitempty"`
+	CiphertextPair       *mega.MEGaCiphertextPair   `cbor:",omitempty" json:",omitempty"`
+	SimpleCommitment     *poly2.SimpleCommitment    `cbor:",omitempty" json:",omitempty"`
+	PedersenCommitment   *poly2.PedersenCommitment  `cbor:",omitempty" json:",omitempty"`
+	MaskedResharingProof *zk.ProofOfEqualOpenings   `cbor:",omitempty" json:",omitempty"`
+	ProductProof         *zk.ProofOfProduct         `cbor:",omitempty" json:",omitempty"`
+}
+
+func (dealing IDkgDealingInternal) wire() (*idkgDealingWire, error) {
+	if dealing.Commitment == nil {
+		return nil, errors.Wrap(common.ErrInvalidCommitment, "dealing without commitment")
+	}
+	w := &idkgDealingWire{
+		Version:   common.WireVersion,
+		CurveType: dealing.Commitment.CurveType(),
+	}
+	switch c := dealing.Ciphertext.(type) {
+	case *mega.MEGaCiphertextSingle:
+		w.CiphertextSingle = c
+	case *mega.MEGaCiphertextPair:
+		w.CiphertextPair = c
+	default:
+		return nil, errors.Wrap(common.ErrInvalidCiphertext, "unexpected ciphertext type")
+	}
+	switch c := dealing.Commitment.(type) {
+	case *poly2.SimpleCommitment:
+		w.SimpleCommitment = c
+	case *poly2.PedersenCommitment:
+		w.PedersenCommitment = c
+	default:
+		return nil, common.ErrUnexpectedCommitmentType
+	}
+	switch p := dealing.Proof.(type) {
+	case nil:
+	case *MaskedResharingProof:
+		w.MaskedResharingProof = p.ProofOfEqualOpenings
+	case *ProductProof:
+		w.ProductProof = p.ProofOfProduct
+	default:
+		return nil, errors.Wrap(common.ErrUnexpectedProof, "unexpected proof type")
+	}
+	return w, nil
+}
+
+// fromWire checks that the parts of the dealing fit together: the
+// ciphertext type matches the commitment type, a proof only comes with the
+// commitment it is about, and everything is on the dealing's curve.
+func (dealing *IDkgDealingInternal) fromWire(w *idkgDealingWire) error {
+	if w.Version != common.WireVersion {
+		return errors.Wrapf(common.ErrInvalidEncoding, "unsupported dealing version %d", w.Version)
+	}
+	if _, ok := curve.Lookup(w.CurveType); !ok {
+		return common.ErrUnknownCurve
+	}
+	var d IDkgDealingInternal
+	switch {
+	case w.CiphertextSingle != nil && w.CiphertextPair == nil && w.SimpleCommitment != nil && w.PedersenCommitment == nil:
+		d.Ciphertext, d.Commitment = w.CiphertextSingle, w.SimpleCommitment
+	case w.CiphertextPair != nil && w.CiphertextSingle == nil && w.PedersenCommitment != nil && w.SimpleCommitment == nil:
+		d.Ciphertext, d.Commitment = w.CiphertextPair, w.PedersenCommitment
+	default:
+		return errors.Wrap(common.ErrUnexpectedCommitmentType, "inconsistent ciphertext and commitment")
+	}
+	switch {
+	case w.MaskedResharingProof != nil && w.ProductProof == nil && w.SimpleCommitment != nil:
+		if w.MaskedResharingProof.CurveType() != w.CurveType {
+			return common.ErrCurveMismatch
+		}
+		d.Proof = &MaskedResharingProof{w.MaskedResharingProof}
+	case w.ProductProof != nil && w.MaskedResharingProof == nil && w.PedersenCommitment != nil:
+		if w.ProductProof.CurveType() != w.CurveType {
+			return common.ErrCurveMismatch
+		}
+		d.Proof = &ProductProof{w.ProductProof}
+	case w.MaskedResharingProof != nil || w.ProductProof != nil:
+		return errors.Wrap(common.ErrUnexpectedProof, "proof for the other commitment type")
+	}
+	if err := d.Ciphertext.VerifyIs(d.Ciphertext.CType(), w.CurveType); err != nil {
+		return err
+	}
+	if err := d.Commitment.VerifyIs(d.Commitment.Type(), w.CurveType); err != nil {
+		return err
+	}
+	*dealing = d
+	return nil
+}
+
+// Deserialize decodes a dealing produced by Serialize. It only checks the
+// encoding; PubliclyVerify must still be called before the dealing is used.
+func (idkgDealing) Deserialize(data []byte) (*IDkgDealingInternal, error) {
+	return common.DeserializeWire[IDkgDealingInternal](data)
+}
+
+// Serialize encodes the dealing as canonical CBOR, the format nodes use to
+// exchange dealings.
+func (dealing IDkgDealingInternal) Serialize() ([]byte, error) {
+	return dealing.MarshalCBOR()
+}
+
+func (dealing IDkgDealingInternal) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(dealing.wire())
+}
+
+func (dealing *IDkgDealingInternal) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, dealing.fromWire)
+}
+
+func (dealing IDkgDealingInternal) MarshalJSON() ([]byte, error) {
+	return common.MarshalWireJSON(dealing.wire())
+}
+
+func (dealing *IDkgDealingInternal) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalWireJSON(data, dealing.fromWire)
+}
+
+var (
+	Transcript = idkgTranscript{}
+)
+
+type idkgTranscript struct{}
+
+// idkgTranscriptWire is the versioned encoding of a transcript, used to
+// persist key and presignature transcripts.
+type idkgTranscriptWire struct {
+	Version                 uint8
+	CurveType               curve.EccCurveType
+	ReconstructionThreshold int
+	OperationType           TranscriptOperationType
+	CombinationType         CombinedCommitmentType
+	SimpleCommitment        *poly2.SimpleCommitment   `cbor:",omitempty" json:",omitempty"`
+	PedersenCommitment      *poly2.PedersenCommitment `cbor:",omitempty" json:",omitempty"`
+}
+
+// transcriptCombination returns how NewTranscriptInternal combines the
+// commitments of an operation, and the type of the result.
+func transcriptCombination(operationType TranscriptOperationType) (CombinedCommitmentType, poly2.PolynomialCommitmentType, error) {
+	switch operationType {
+	case RandomOperation:
+		return Summation, poly2.Pedersen, nil
+	case ReshareOfMaskedOperation, ReshareOfUnmaskedOperation:
+		return Interpolation, poly2.Simple, nil
+	case UnmaskedTimesMaskedOperation:
+		return Interpolation, poly2.Pedersen, nil
+	}
+	return 0, 0, common.ErrUnknownOperation
+}
+
+func (t IDkgTranscriptInternal) wire() (*idkgTranscriptWire, error) {
+	if t.CombinedCommitment == nil {
+		return nil, errors.Wrap(common.ErrInvalidCommitment, "transcript without commitment")
+	}
+	var inner poly2.PolynomialCommitment
+	switch c := t.CombinedCommitment.(type) {
+	case *SummationCommitment:
+		inner = c.PolynomialCommitment
+	case *InterpolationCommitment:
+		inner = c.PolynomialCommitment
+	default:
+		return nil, errors.Wrap(common.ErrUnexpectedCommitmentType, "unexpected combined commitment type")
+	}
+	simple, pedersen, err := splitCommitment(inner)
+	if err != nil {
+		return nil, err
+	}
+	return &idkgTranscriptWire{
+		Version:                 common.WireVersion,
+		CurveType:               t.CombinedCommitment.CurveType(),
+		ReconstructionThreshold: t.ReconstructionThreshold,
+		OperationType:           t.OperationType,
+		CombinationType:         t.CombinedCommitment.CombinationType(),
+		SimpleCommitment:        simple,
+		PedersenCommitment:      pedersen,
+	}, nil
+}
+
+// fromWire checks that the commitment is on the transcript's curve, has
+// ReconstructionThreshold points and was combined the way the operation
+// combines commitments.
+func (t *IDkgTranscriptInternal) fromWire(w *idkgTranscriptWire) error {
+	if w.Version != common.WireVersion {
+		return errors.Wrapf(common.ErrInvalidEncoding, "unsupported transcript version %d", w.Version)
+	}
+	if _, ok := curve.Lookup(w.CurveType); !ok {
+		return common.ErrUnknownCurve
+	}
+	combined, err := joinCombined(w.CombinationType, w.SimpleCommitment, w.PedersenCommitment)
+	if err != nil {
+		return err
+	}
+	combinationType, commitmentType, err := transcriptCombination(w.OperationType)
+	if err != nil {
+		return err
+	}
+	if combined.CombinationType() != combinationType {
+		return errors.Wrap(common.ErrUnexpectedCommitmentType, "unexpected combination for the transcript operation")
+	}
+	if err := combined.VerifyIs(commitmentType, w.CurveType); err != nil {
+		return err
+	}
+	if w.ReconstructionThreshold <= 0 || combined.Len() != w.ReconstructionThreshold {
+		return errors.Wrap(common.ErrInvalidThreshold, "invalid reconstruction threshold")
+	}
+	*t = IDkgTranscriptInternal{
+		CombinedCommitment:      combined,
+		ReconstructionThreshold: w.ReconstructionThreshold,
+		OperationType:           w.OperationType,
+	}
+	return nil
+}
+
+// Deserialize decodes a transcript produced by Serialize.
+func (idkgTranscript) Deserialize(data []byte) (*IDkgTranscriptInternal, error) {
+	return common.DeserializeWire[IDkgTranscriptInternal](data)
+}
+
+// Serialize encodes the transcript as canonical CBOR for storage.
+func (t IDkgTranscriptInternal) Serialize() ([]byte, error) {
+	return t.MarshalCBOR()
+}
+
+func (t IDkgTranscriptInternal) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(t.wire())
+}
+
+func (t *IDkgTranscriptInternal) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, t.fromWire)
+}
+
+func (t IDkgTranscriptInternal) MarshalJSON() ([]byte, error) {
+	return common.MarshalWireJSON(t.wire())
+}
+
+func (t *IDkgTranscriptInternal) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalWireJSON(data, t.fromWire)
+}
@@ -0,0 +1,161 @@
+package dealings
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/mega"
+	poly2 "github.com/PlatONnetwork/tecdsa/poly"
+	seed2 "github.com/PlatONnetwork/tecdsa/seed"
+	"github.com/PlatONnetwork/tecdsa/testutils/wiretest"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/btree"
+	"testing"
+)
+
+func allDealingShares(curveType curve.EccCurveType) []SecretShares {
+	rng := genRng()
+	random := func() curve.EccScalar { return curve.Scalar.Random(curveType, rng) }
+	return []SecretShares{
+		&RandomSecret{},
+		&ReshareOfUnmaskedSecret{random()},
+		&ReshareOfMaskedSecret{S1: random(), S2: random()},
+		&UnmaskedTimesMaskedSecret{random(), [2]curve.EccScalar{random(), random()}},
+	}
+}
+
+func TestDealingSerializationRoundTrips(t *testing.T) {
+	ad := []byte{1, 2, 3}
+	for _, curveType := range []curve.EccCurveType{curve.K256, curve.P256, curve.ED25519} {
+		privateKeys, publicKeys := genPrivateKeys(curveType, 4)
+		for _, shares := range allDealingShares(curveType) {
+			dealerIndex := common.NodeIndex(1)
+			dealing, err := NewIDkgDealingInternal(shares, curveType, seed2.FromRng(genRng()), 2, publicKeys, dealerIndex, ad)
+			assert.Nil(t, err)
+			bytes, err := dealing.Serialize()
+			assert.Nil(t, err)
+
+			decoded, err := Dealing.Deserialize(bytes)
+			assert.Nil(t, err)
+			assert.Equal(t, dealing.Ciphertext.CType(), decoded.Ciphertext.CType())
+			assert.Equal(t, 1, dealing.Commitment.Equal(decoded.Commitment))
+			assert.Equal(t, dealing.Proof == nil, decoded.Proof == nil)
+			if dealing.Proof != nil {
+				assert.Equal(t, dealing.Proof.Type(), decoded.Proof.Type())
+			}
+			again, err := decoded.Serialize()
+			assert.Nil(t, err)
+			assert.Equal(t, bytes, again)
+			for i := range privateKeys {
+				assert.Nil(t, decoded.PrivateVerify(curveType, privateKeys[i], publicKeys[i], ad, dealerIndex, common.NodeIndex(i)))
+			}
+
+			js, err := json.Marshal(dealing)
+			assert.Nil(t, err)
+			var fromJson IDkgDealingInternal
+			assert.Nil(t, json.Unmarshal(js, &fromJson))
+			again, err = fromJson.Serialize()
+			assert.Nil(t, err)
+			assert.Equal(t, bytes, again)
+		}
+	}
+}
+
+func TestDealingDeserializationIsStrict(t *testing.T) {
+	curveType := curve.K256
+	ad := []byte{1, 2, 3}
+	_, publicKeys := genPrivateKeys(curveType, 3)
+	secret := curve.Scalar.Random(curveType, genRng())
+	dealing, err := NewIDkgDealingInternal(&ReshareOfMaskedSecret{S1: secret, S2: secret}, curveType, seed2.FromRng(genRng()), 2, publicKeys, 0, ad)
+	assert.Nil(t, err)
+	bytes, err := dealing.Serialize()
+	assert.Nil(t, err)
+
+	_, err = Dealing.Deserialize(append(bytes, 0))
+	assert.NotNil(t, err)
+	_, err = Dealing.Deserialize(bytes[:len(bytes)-1])
+	assert.NotNil(t, err)
+
+	_, err = Dealing.Deserialize(wiretest.Reencode(t, bytes, func(w *idkgDealingWire) {}))
+	assert.Nil(t, err)
+	invalid := map[string]func(w *idkgDealingWire){
+		"version":       func(w *idkgDealingWire) { w.Version = 2 },
+		"curve":         func(w *idkgDealingWire) { w.CurveType = curve.P256 },
+		"unknown curve": func(w *idkgDealingWire) { w.CurveType = curve.EccCurveType(42) },
+		"no commitment": func(w *idkgDealingWire) { w.SimpleCommitment = nil },
+		"no ciphertext": func(w *idkgDealingWire) { w.CiphertextSingle = nil },
+		"two commitments": func(w *idkgDealingWire) {
+			w.PedersenCommitment = poly2.PedersenCM.New(w.SimpleCommitment.Points())
+		},
+		"proof for the other commitment": func(w *idkgDealingWire) {
+			w.MaskedResharingProof, w.SimpleCommitment = nil, nil
+			w.PedersenCommitment = poly2.PedersenCM.New(dealing.Commitment.Points())
+		},
+	}
+	wiretest.AssertRejected(t, bytes, Dealing.Deserialize, invalid)
+
+	// a ciphertext from another curve
+	_, p256Keys := genPrivateKeys(curve.P256, 3)
+	other, err := NewIDkgDealingInternal(&ReshareOfUnmaskedSecret{curve.Scalar.Random(curve.P256, genRng())}, curve.P256, seed2.FromRng(genRng()), 2, p256Keys, 0, ad)
+	assert.Nil(t, err)
+	_, err = Dealing.Deserialize(wiretest.Reencode(t, bytes, func(w *idkgDealingWire) {
+		w.CiphertextSingle = other.Ciphertext.(*mega.MEGaCiphertextSingle)
+	}))
+	assert.NotNil(t, err)
+
+	// the version as a two byte integer instead of the shortest form
+	assert.Equal(t, []byte("\xa5\x67Version\x01"), bytes[:10])
+	long := append([]byte("\xa5\x67Version\x18\x01"), bytes[10:]...)
+	var w idkgDealingWire
+	assert.Nil(t, cbor.Unmarshal(long, &w))
+	_, err = Dealing.Deserialize(long)
+	assert.NotNil(t, err)
+
+	var fromJson IDkgDealingInternal
+	js, err := json.Marshal(dealing)
+	assert.Nil(t, err)
+	assert.NotNil(t, json.Unmarshal(append(js[:len(js)-1], []byte(`,"Extra":1}`)...), &fromJson))
+}
+
+func TestTranscriptDeserializationIsStrict(t *testing.T) {
+	curveType := curve.K256
+	_, publicKeys := genPrivateKeys(curveType, 3)
+	verified := new(btree.Map[common.NodeIndex, *IDkgDealingInternal])
+	for dealer := 0; dealer < 3; dealer++ {
+		dealing, err := NewIDkgDealingInternal(&RandomSecret{}, curveType, seed2.FromRng(genRng()), 2, publicKeys, common.NodeIndex(dealer), nil)
+		assert.Nil(t, err)
+		verified.Set(common.NodeIndex(dealer), dealing)
+	}
+	transcript, err := NewTranscriptInternal(curveType, 2, verified, &RandomTranscript{})
+	assert.Nil(t, err)
+	bytes, err := transcript.Serialize()
+	assert.Nil(t, err)
+	decoded, err := Transcript.Deserialize(bytes)
+	assert.Nil(t, err)
+	assert.IsType(t, &SummationCommitment{}, decoded.CombinedCommitment)
+	js, err := json.Marshal(transcript)
+	assert.Nil(t, err)
+	var fromJson IDkgTranscriptInternal
+	assert.Nil(t, json.Unmarshal(js, &fromJson))
+	assert.Equal(t, 1, transcript.CombinedCommitment.Equal(fromJson.CombinedCommitment))
+
+	_, err = Transcript.Deserialize(append(bytes, 0))
+	assert.NotNil(t, err)
+	invalid := map[string]func(w *idkgTranscriptWire){
+		"version":           func(w *idkgTranscriptWire) { w.Version = 2 },
+		"curve":             func(w *idkgTranscriptWire) { w.CurveType = curve.P256 },
+		"threshold":         func(w *idkgTranscriptWire) { w.ReconstructionThreshold = 3 },
+		"operation":         func(w *idkgTranscriptWire) { w.OperationType = UnmaskedTimesMaskedOperation },
+		"unknown operation": func(w *idkgTranscriptWire) { w.OperationType = 0 },
+		"combination":       func(w *idkgTranscriptWire) { w.CombinationType = Interpolation },
+		"commitment type": func(w *idkgTranscriptWire) {
+			w.SimpleCommitment, w.PedersenCommitment = poly2.SimpleCM.New(w.PedersenCommitment.Points()), nil
+		},
+		"no commitment": func(w *idkgTranscriptWire) { w.PedersenCommitment = nil },
+	}
+	wiretest.AssertRejected(t, bytes, Transcript.Deserialize, invalid)
+	_, err = Transcript.Deserialize(wiretest.Reencode(t, bytes, invalid["unknown operation"]))
+	assert.True(t, errors.Is(err, common.ErrUnknownOperation))
+}
@@ -1,6 +1,7 @@
 package dealings
 
 import (
+	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
 	"github.com/PlatONnetwork/tecdsa/poly"
 	"github.com/pkg/errors"
@@ -52,12 +53,12 @@ func (secretShares) From(cob1 poly.CommitmentOpeningBytes, cob2 poly.CommitmentO
 					Right: [2]curve.EccScalar{s2, s3},
 				}
 			} else {
-				return nil, errors.New("inconsistent combination of commitment types")
+				return nil, errors.Wrap(common.ErrUnexpectedCommitmentType, "inconsistent combination of commitment types")
 			}
 		}
 	case *poly.PedersenCommitmentOpeningBytes:
 		if cob2 != nil {
-			return nil, errors.New("inconsistent combination of commitment types")
+			return nil, errors.Wrap(common.ErrUnexpectedCommitmentType, "inconsistent combination of commitment types")
 		}
 		share = &ReshareOfMaskedSecret{S1: cm1[0].ToScalar(), S2: cm1[1].ToScalar()}
 	}
@@ -1,14 +1,25 @@
 package dealings
 
 import (
+	"context"
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
 	"github.com/PlatONnetwork/tecdsa/poly"
-	"github.com/pkg/errors"
 	"github.com/tidwall/btree"
 )
 
-type IDkgTranscriptOperationInternal interface{}
+const (
+	RandomOperation              = TranscriptOperationType(1)
+	ReshareOfMaskedOperation     = TranscriptOperationType(2)
+	ReshareOfUnmaskedOperation   = TranscriptOperationType(3)
+	UnmaskedTimesMaskedOperation = TranscriptOperationType(4)
+)
+
+type TranscriptOperationType int
+
+type IDkgTranscriptOperationInternal interface {
+	Type() TranscriptOperationType
+}
 
 type RandomTranscript struct{}
 type ReshareOfUnmaskedTranscript struct {
@@ -22,7 +33,29 @@ type UnmaskedTimesMaskedTranscript struct {
 	Right poly.PolynomialCommitment
 }
 
+func (RandomTranscript) Type() TranscriptOperationType {
+	return RandomOperation
+}
+
+func (ReshareOfUnmaskedTranscript) Type() TranscriptOperationType {
+	return ReshareOfUnmaskedOperation
+}
+
+func (ReshareOfMaskedTranscript) Type() TranscriptOperationType {
+	return ReshareOfMaskedOperation
+}
+
+func (UnmaskedTimesMaskedTranscript) Type() TranscriptOperationType {
+	return UnmaskedTimesMaskedOperation
+}
+
 func CombineCommitmentsViaInterpolation(commitmentType poly.PolynomialCommitmentType, curveType curve.EccCurveType, reconstructionThreshold int, verifiedDealings *btree.Map[common.NodeIndex, *IDkgDealingInternal]) (CombinedCommitment, error) {
+	return combineCommitmentsViaInterpolation(common.Sequential(), commitmentType, curveType, reconstructionThreshold, verifiedDealings)
+}
+
+// combineCommitmentsViaInterpolation interpolates each point of the
+// combined commitment on the worker pool of ctx.
+func combineCommitmentsViaInterpolation(ctx context.Context, commitmentType poly.PolynomialCommitmentType, curveType curve.EccCurveType, reconstructionThreshold int, verifiedDealings *btree.Map[common.NodeIndex, *IDkgDealingInternal]) (CombinedCommitment, error) {
 	commitments := make([]poly.PolynomialCommitment, 0, verifiedDealings.Len())
 	indexes := make([]common.NodeIndex, 0, verifiedDealings.Len())
 	verifiedDealings.Scan(func(index common.NodeIndex, dealing *IDkgDealingInternal) bool {
@@ -34,17 +67,18 @@ func CombineCommitmentsViaInterpolation(commitmentType poly.PolynomialCommitment
 	if err != nil {
 		return nil, err
 	}
-	combined := make([]curve.EccPoint, 0, reconstructionThreshold)
-	for i := 0; i < reconstructionThreshold; i++ {
+	combined := make([]curve.EccPoint, reconstructionThreshold, reconstructionThreshold)
+	err = common.ParallelFor(ctx, reconstructionThreshold, func(i int) error {
 		values := make([]curve.EccPoint, 0, len(commitments))
 		for _, commitment := range commitments {
 			values = append(values, commitment.Points()[i])
 		}
 		point, err := coefficients.InterpolatePoint(values)
-		if err != nil {
-			return nil, err
-		}
-		combined = append(combined, point)
+		combined[i] = point
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 	var cm poly.PolynomialCommitment
 	switch commitmentType {
@@ -57,14 +91,27 @@ func CombineCommitmentsViaInterpolation(commitmentType poly.PolynomialCommitment
 }
 
 func NewTranscriptInternal(curveType curve.EccCurveType, reconstructionThreshold int, verifiedDealings *btree.Map[common.NodeIndex, *IDkgDealingInternal], operationMode IDkgTranscriptOperationInternal) (*IDkgTranscriptInternal, error) {
+	return NewTranscriptInternalContext(common.Sequential(), curveType, reconstructionThreshold, verifiedDealings, operationMode)
+}
+
+// NewTranscriptInternalContext is NewTranscriptInternal with the commitment
+// interpolation spread over the worker pool of ctx.
+func NewTranscriptInternalContext(ctx context.Context, curveType curve.EccCurveType, reconstructionThreshold int, verifiedDealings *btree.Map[common.NodeIndex, *IDkgDealingInternal], operationMode IDkgTranscriptOperationInternal) (*IDkgTranscriptInternal, error) {
 	for _, dealing := range verifiedDealings.Values() {
 		if len(dealing.Commitment.Points()) != reconstructionThreshold {
-			return nil, errors.New("unexpected commitment type")
+			return nil, common.ErrUnexpectedCommitmentType
 		}
 	}
 
+	need, err := RequiredDealings(operationMode)
+	if err != nil {
+		return nil, err
+	}
+	if verifiedDealings.Len() < need {
+		return nil, &common.ErrInsufficientDealings{Have: verifiedDealings.Len(), Need: need}
+	}
+
 	var combinedCommitment CombinedCommitment
-	var err error
 
 	switch o := operationMode.(type) {
 	case *RandomTranscript:
@@ -74,7 +121,7 @@ func NewTranscriptInternal(curveType curve.EccCurveType, reconstructionThreshold
 		}
 		for _, dealing := range verifiedDealings.Values() {
 			if dealing.Commitment.Type() != poly.Pedersen {
-				return nil, errors.New("unexpected commitment type")
+				return nil, common.ErrUnexpectedCommitmentType
 			}
 			c := dealing.Commitment.Points()
 			for i := 0; i < reconstructionThreshold; i++ {
@@ -84,42 +131,57 @@ func NewTranscriptInternal(curveType curve.EccCurveType, reconstructionThreshold
 		combinedCommitment = &SummationCommitment{poly.PedersenCM.New(combined)}
 	case *ReshareOfMaskedTranscript:
 		if o.P1.Type() != poly.Pedersen {
-			return nil, errors.New("unexpected commitment type")
+			return nil, common.ErrUnexpectedCommitmentType
 		}
-		if verifiedDealings.Len() < len(o.P1.Points()) {
-			return nil, errors.New("insufficient dealings")
-		}
-		combinedCommitment, err = CombineCommitmentsViaInterpolation(poly.Simple, curveType, reconstructionThreshold, verifiedDealings)
+		combinedCommitment, err = combineCommitmentsViaInterpolation(ctx, poly.Simple, curveType, reconstructionThreshold, verifiedDealings)
 		if err != nil {
 			return nil, err
 		}
 	case *ReshareOfUnmaskedTranscript:
 		if o.P1.Type() != poly.Simple {
-			return nil, errors.New("unexpected commitment type")
-		}
-		if verifiedDealings.Len() < len(o.P1.Points()) {
-			return nil, errors.New("insufficient dealings")
+			return nil, common.ErrUnexpectedCommitmentType
 		}
-		combinedCommitment, err = CombineCommitmentsViaInterpolation(poly.Simple, curveType, reconstructionThreshold, verifiedDealings)
+		combinedCommitment, err = combineCommitmentsViaInterpolation(ctx, poly.Simple, curveType, reconstructionThreshold, verifiedDealings)
 		if err != nil {
 			return nil, err
 		}
 		if o.P1.Points()[0].Equal(combinedCommitment.Points()[0]) != 1 {
-			return nil, errors.New("invalid commitment")
+			return nil, common.ErrInvalidCommitment
 		}
 	case *UnmaskedTimesMaskedTranscript:
 		if o.Left.Type() != poly.Simple || o.Right.Type() != poly.Pedersen {
-			return nil, errors.New("unexpected commitment type")
+			return nil, common.ErrUnexpectedCommitmentType
 		}
-		if verifiedDealings.Len() < len(o.Left.Points())+len(o.Right.Points())-1 {
-			return nil, errors.New("insufficient dealings")
-		}
-		combinedCommitment, err = CombineCommitmentsViaInterpolation(poly.Pedersen, curveType, reconstructionThreshold, verifiedDealings)
+		combinedCommitment, err = combineCommitmentsViaInterpolation(ctx, poly.Pedersen, curveType, reconstructionThreshold, verifiedDealings)
 		if err != nil {
 			return nil, err
 		}
+	default:
+		return nil, common.ErrUnknownOperation
+	}
+	return &IDkgTranscriptInternal{
+		CombinedCommitment:      combinedCommitment,
+		ReconstructionThreshold: reconstructionThreshold,
+		OperationType:           operationMode.Type(),
+	}, nil
+}
+
+// RequiredDealings returns the number of verified dealings that
+// NewTranscriptInternal needs to combine into a transcript for operationMode:
+// one for a random transcript, the threshold of the reshared transcript, and
+// the degree of the product plus one for a multiplication.
+func RequiredDealings(operationMode IDkgTranscriptOperationInternal) (int, error) {
+	switch o := operationMode.(type) {
+	case *RandomTranscript:
+		return 1, nil
+	case *ReshareOfMaskedTranscript:
+		return o.P1.Len(), nil
+	case *ReshareOfUnmaskedTranscript:
+		return o.P1.Len(), nil
+	case *UnmaskedTimesMaskedTranscript:
+		return o.Left.Len() + o.Right.Len() - 1, nil
 	}
-	return &IDkgTranscriptInternal{CombinedCommitment: combinedCommitment}, nil
+	return 0, common.ErrUnknownOperation
 }
 
 /// Reconstruct a secret share from a set of openings
@@ -138,7 +200,7 @@ func NewTranscriptInternal(curveType curve.EccCurveType, reconstructionThreshold
 func ReconstructShareFromOpenings(dealing *IDkgDealingInternal, openings *btree.Map[common.NodeIndex, poly.CommitmentOpening], shareIndex common.NodeIndex) (poly.CommitmentOpening, error) {
 	reconstructionThreshold := dealing.Commitment.Len()
 	if openings.Len() < reconstructionThreshold {
-		return nil, errors.New("insufficient openings")
+		return nil, &common.ErrInsufficientOpenings{Have: openings.Len(), Need: reconstructionThreshold}
 	}
 	curveType := dealing.Commitment.CurveType()
 	index := curve.Scalar.FromNodeIndex(curveType, shareIndex)
@@ -154,7 +216,7 @@ func ReconstructShareFromOpenings(dealing *IDkgDealingInternal, openings *btree.
 				xValues = append(xValues, receiverIndex)
 				values = append(values, o[0])
 			case poly.PedersenCommitmentOpening:
-				err = errors.New("unexpected commitment type")
+				err = common.ErrUnexpectedCommitmentType
 				return false
 			}
 			return true
@@ -182,7 +244,7 @@ func ReconstructShareFromOpenings(dealing *IDkgDealingInternal, openings *btree.
 				values = append(values, o[0])
 				masks = append(masks, o[1])
 			case poly.SimpleCommitmentOpening:
-				err = errors.New("unexpected commitment type")
+				err = common.ErrUnexpectedCommitmentType
 				return false
 			}
 			return true
@@ -7,7 +7,9 @@ import (
 )
 
 type IDkgTranscriptInternal struct {
-	CombinedCommitment CombinedCommitment
+	CombinedCommitment      CombinedCommitment
+	ReconstructionThreshold int
+	OperationType           TranscriptOperationType
 }
 
 func (t IDkgTranscriptInternal) ConstantTerm() curve.EccPoint {
@@ -3,6 +3,7 @@ module github.com/PlatONnetwork/tecdsa
 go 1.18
 
 require (
+	filippo.io/edwards25519 v1.0.0-rc.1
 	github.com/btcsuite/btcd v0.21.0-beta.0.20201114000516-e9c7a5ac6401
 	github.com/coinbase/kryptology v1.8.0
 	github.com/fxamacker/cbor/v2 v2.4.0
@@ -16,7 +17,6 @@ require (
 )
 
 require (
-	filippo.io/edwards25519 v1.0.0-rc.1 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/kr/pretty v0.3.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
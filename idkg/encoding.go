@@ -0,0 +1,319 @@
+package idkg
+
+import (
+	"crypto/sha256"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/complaints"
+	"github.com/PlatONnetwork/tecdsa/dealings"
+	"github.com/pkg/errors"
+	"github.com/tidwall/btree"
+)
+
+var (
+	Messages = idkgMessages{}
+)
+
+type idkgMessages struct{}
+
+// The messages are encoded as canonical CBOR maps holding the wire version
+// and the encodings of their parts from the dealings and complaints
+// packages. Messages.Serialize wraps them with their type so that a node
+// can decode what it receives without knowing what to expect.
+
+type messageWire struct {
+	Version uint8
+	Type    MessageType
+	Message []byte
+}
+
+type dealingMessageWire struct {
+	Version uint8
+	Dealing *dealings.IDkgDealingInternal
+}
+
+type supportMessageWire struct {
+	Version uint8
+	Dealer  common.NodeIndex
+	Digest  []byte
+}
+
+// transcriptMessageWire lists the dealers in increasing order, each with
+// its dealing.
+type transcriptMessageWire struct {
+	Version  uint8
+	Dealers  []common.NodeIndex
+	Dealings []*dealings.IDkgDealingInternal
+}
+
+type complaintMessageWire struct {
+	Version   uint8
+	Dealer    common.NodeIndex
+	Complaint *complaints.IDkgComplaintInternal
+}
+
+type openingMessageWire struct {
+	Version    uint8
+	Dealer     common.NodeIndex
+	Complainer common.NodeIndex
+	Opening    *complaints.IDkgOpeningInternal
+}
+
+func checkVersion(version uint8) error {
+	if version != common.WireVersion {
+		return errors.Wrapf(common.ErrInvalidEncoding, "unsupported message version %d", version)
+	}
+	return nil
+}
+
+func checkIndex(index common.NodeIndex) error {
+	if index < 0 {
+		return errors.Wrapf(common.ErrInvalidEncoding, "invalid node index %d", index)
+	}
+	return nil
+}
+
+// Serialize encodes msg together with its type.
+func (idkgMessages) Serialize(msg Message) ([]byte, error) {
+	if msg == nil {
+		return nil, errors.New("missing message")
+	}
+	data, err := msg.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return common.MarshalCanonical(&messageWire{
+		Version: common.WireVersion,
+		Type:    msg.Type(),
+		Message: data,
+	})
+}
+
+// Deserialize decodes a message produced by Messages.Serialize. The
+// contents of the message are only checked by Engine.Handle.
+func (idkgMessages) Deserialize(data []byte) (Message, error) {
+	var w messageWire
+	if err := common.UnmarshalStrict(data, &w); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(w.Version); err != nil {
+		return nil, err
+	}
+	switch w.Type {
+	case DealingMessageType:
+		return deserialize[DealingMessage](w.Message)
+	case SupportMessageType:
+		return deserialize[SupportMessage](w.Message)
+	case TranscriptMessageType:
+		return deserialize[TranscriptMessage](w.Message)
+	case ComplaintMessageType:
+		return deserialize[ComplaintMessage](w.Message)
+	case OpeningMessageType:
+		return deserialize[OpeningMessage](w.Message)
+	}
+	return nil, errors.Wrapf(common.ErrInvalidEncoding, "unknown message type %d", w.Type)
+}
+
+// Encode and Decode make Messages the transport.Codec of the protocol.
+func (m idkgMessages) Encode(payload interface{}) ([]byte, error) {
+	msg, ok := payload.(Message)
+	if !ok {
+		return nil, errors.Errorf("unexpected payload %T", payload)
+	}
+	return m.Serialize(msg)
+}
+
+func (m idkgMessages) Decode(data []byte) (interface{}, error) {
+	return m.Deserialize(data)
+}
+
+// deserialize decodes a message of type T, returning a nil Message rather
+// than a nil *T on failure.
+func deserialize[T any, P interface {
+	*T
+	Message
+	UnmarshalCBOR([]byte) error
+}](data []byte) (Message, error) {
+	m, err := common.DeserializeWire[T, P](data)
+	if err != nil {
+		return nil, err
+	}
+	return P(m), nil
+}
+
+func (m DealingMessage) wire() (*dealingMessageWire, error) {
+	if m.Dealing == nil {
+		return nil, errors.New("missing dealing")
+	}
+	return &dealingMessageWire{Version: common.WireVersion, Dealing: m.Dealing}, nil
+}
+
+func (m *DealingMessage) fromWire(w *dealingMessageWire) error {
+	if err := checkVersion(w.Version); err != nil {
+		return err
+	}
+	if w.Dealing == nil {
+		return errors.Wrap(common.ErrInvalidEncoding, "missing dealing")
+	}
+	m.Dealing = w.Dealing
+	return nil
+}
+
+func (m DealingMessage) Serialize() ([]byte, error) {
+	return m.MarshalCBOR()
+}
+
+func (m DealingMessage) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(m.wire())
+}
+
+func (m *DealingMessage) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, m.fromWire)
+}
+
+func (m SupportMessage) wire() (*supportMessageWire, error) {
+	return &supportMessageWire{Version: common.WireVersion, Dealer: m.Dealer, Digest: m.Digest}, nil
+}
+
+func (m *SupportMessage) fromWire(w *supportMessageWire) error {
+	if err := checkVersion(w.Version); err != nil {
+		return err
+	}
+	if err := checkIndex(w.Dealer); err != nil {
+		return err
+	}
+	if len(w.Digest) != sha256.Size {
+		return errors.Wrap(common.ErrInvalidEncoding, "invalid dealing digest")
+	}
+	m.Dealer, m.Digest = w.Dealer, w.Digest
+	return nil
+}
+
+func (m SupportMessage) Serialize() ([]byte, error) {
+	return m.MarshalCBOR()
+}
+
+func (m SupportMessage) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(m.wire())
+}
+
+func (m *SupportMessage) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, m.fromWire)
+}
+
+func (m TranscriptMessage) wire() (*transcriptMessageWire, error) {
+	if m.Dealings == nil {
+		return nil, errors.New("missing dealings")
+	}
+	dealers, values := m.Dealings.KeyValues()
+	for _, dealing := range values {
+		if dealing == nil {
+			return nil, errors.New("missing dealing")
+		}
+	}
+	return &transcriptMessageWire{Version: common.WireVersion, Dealers: dealers, Dealings: values}, nil
+}
+
+func (m *TranscriptMessage) fromWire(w *transcriptMessageWire) error {
+	if err := checkVersion(w.Version); err != nil {
+		return err
+	}
+	if len(w.Dealers) != len(w.Dealings) {
+		return errors.Wrap(common.ErrInvalidEncoding, "dealers and dealings differ in number")
+	}
+	selected := new(btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal])
+	for i, dealer := range w.Dealers {
+		if err := checkIndex(dealer); err != nil {
+			return err
+		}
+		if i > 0 && dealer <= w.Dealers[i-1] {
+			return errors.Wrap(common.ErrInvalidEncoding, "dealers not in increasing order")
+		}
+		if w.Dealings[i] == nil {
+			return errors.Wrapf(common.ErrInvalidEncoding, "missing dealing of dealer %d", dealer)
+		}
+		selected.Set(dealer, w.Dealings[i])
+	}
+	m.Dealings = selected
+	return nil
+}
+
+func (m TranscriptMessage) Serialize() ([]byte, error) {
+	return m.MarshalCBOR()
+}
+
+func (m TranscriptMessage) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(m.wire())
+}
+
+func (m *TranscriptMessage) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, m.fromWire)
+}
+
+func (m ComplaintMessage) wire() (*complaintMessageWire, error) {
+	if m.Complaint == nil {
+		return nil, errors.New("missing complaint")
+	}
+	return &complaintMessageWire{Version: common.WireVersion, Dealer: m.Dealer, Complaint: m.Complaint}, nil
+}
+
+func (m *ComplaintMessage) fromWire(w *complaintMessageWire) error {
+	if err := checkVersion(w.Version); err != nil {
+		return err
+	}
+	if err := checkIndex(w.Dealer); err != nil {
+		return err
+	}
+	if w.Complaint == nil {
+		return errors.Wrap(common.ErrInvalidEncoding, "missing complaint")
+	}
+	m.Dealer, m.Complaint = w.Dealer, w.Complaint
+	return nil
+}
+
+func (m ComplaintMessage) Serialize() ([]byte, error) {
+	return m.MarshalCBOR()
+}
+
+func (m ComplaintMessage) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(m.wire())
+}
+
+func (m *ComplaintMessage) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, m.fromWire)
+}
+
+func (m OpeningMessage) wire() (*openingMessageWire, error) {
+	if m.Opening == nil {
+		return nil, errors.New("missing opening")
+	}
+	return &openingMessageWire{Version: common.WireVersion, Dealer: m.Dealer, Complainer: m.Complainer, Opening: m.Opening}, nil
+}
+
+func (m *OpeningMessage) fromWire(w *openingMessageWire) error {
+	if err := checkVersion(w.Version); err != nil {
+		return err
+	}
+	if err := checkIndex(w.Dealer); err != nil {
+		return err
+	}
+	if err := checkIndex(w.Complainer); err != nil {
+		return err
+	}
+	if w.Opening == nil {
+		return errors.Wrap(common.ErrInvalidEncoding, "missing opening")
+	}
+	m.Dealer, m.Complainer, m.Opening = w.Dealer, w.Complainer, w.Opening
+	return nil
+}
+
+func (m OpeningMessage) Serialize() ([]byte, error) {
+	return m.MarshalCBOR()
+}
+
+func (m OpeningMessage) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(m.wire())
+}
+
+func (m *OpeningMessage) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, m.fromWire)
+}
@@ -0,0 +1,99 @@
+package idkg
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/dealings"
+	"github.com/PlatONnetwork/tecdsa/testutils"
+	"github.com/PlatONnetwork/tecdsa/testutils/wiretest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEngineRunsOnDecodedMessages(t *testing.T) {
+	c := newTestCommittee(curve.K256, 4, 2)
+	// as in TestEngineRecoversCorruptedSharesFromOpenings, so that every
+	// type of message is sent
+	n := c.network(t, "decoded", &dealings.RandomTranscript{}, func(i int) dealings.SecretShares {
+		if i == 0 {
+			return &dealings.RandomSecret{}
+		}
+		return nil
+	})
+	dealing, err := dealings.NewIDkgDealingInternal(&dealings.RandomSecret{}, c.curveType, c.seed.Derive("dealing"), c.threshold, c.pk, 1, c.ad)
+	require.NoError(t, err)
+	corrupted, err := testutils.CorruptDealing(dealing, []common.NodeIndex{2}, c.seed.Derive("corrupt"))
+	require.NoError(t, err)
+	n.queue = append(n.queue, envelope{1, 1, &DealingMessage{Dealing: corrupted}})
+	n.broadcast(1, []Message{&DealingMessage{Dealing: corrupted}})
+
+	encoded := make(map[MessageType][]byte)
+	n.tamper = func(e envelope) Message {
+		data, err := Messages.Serialize(e.msg)
+		require.NoError(t, err)
+		decoded, err := Messages.Deserialize(data)
+		require.NoError(t, err)
+		assert.Equal(t, e.msg.Type(), decoded.Type())
+		again, err := Messages.Serialize(decoded)
+		require.NoError(t, err)
+		assert.Equal(t, data, again)
+		encoded[e.msg.Type()], err = e.msg.Serialize()
+		require.NoError(t, err)
+		return decoded
+	}
+	n.run(t)
+	assert.Empty(t, n.errors)
+	n.assertAgreement(t)
+	require.Len(t, encoded, 5)
+
+	_, err = Messages.Deserialize(nil)
+	assert.Error(t, err)
+	_, err = Messages.Serialize(&DealingMessage{})
+	assert.Error(t, err)
+	data, err := Messages.Serialize(&SupportMessage{Dealer: 1, Digest: make([]byte, 32)})
+	require.NoError(t, err)
+	wiretest.AssertRejected(t, data, Messages.Deserialize, map[string]func(w *messageWire){
+		"version": func(w *messageWire) { w.Version++ },
+		"type":    func(w *messageWire) { w.Type = 9 },
+		"body":    func(w *messageWire) { w.Type = DealingMessageType },
+	})
+	_, err = Messages.Deserialize(wiretest.Reencode(t, data, func(w *messageWire) { w.Version++ }))
+	assert.ErrorIs(t, err, common.ErrInvalidEncoding)
+
+	decode := func(messageType MessageType) func(data []byte) (Message, error) {
+		return func(data []byte) (Message, error) {
+			wrapped, err := common.MarshalCanonical(&messageWire{Version: common.WireVersion, Type: messageType, Message: data})
+			require.NoError(t, err)
+			return Messages.Deserialize(wrapped)
+		}
+	}
+	wiretest.AssertRejected(t, encoded[DealingMessageType], decode(DealingMessageType), map[string]func(w *dealingMessageWire){
+		"version": func(w *dealingMessageWire) { w.Version++ },
+		"dealing": func(w *dealingMessageWire) { w.Dealing = nil },
+	})
+	wiretest.AssertRejected(t, encoded[SupportMessageType], decode(SupportMessageType), map[string]func(w *supportMessageWire){
+		"version": func(w *supportMessageWire) { w.Version++ },
+		"dealer":  func(w *supportMessageWire) { w.Dealer = -1 },
+		"digest":  func(w *supportMessageWire) { w.Digest = w.Digest[1:] },
+	})
+	wiretest.AssertRejected(t, encoded[TranscriptMessageType], decode(TranscriptMessageType), map[string]func(w *transcriptMessageWire){
+		"version":  func(w *transcriptMessageWire) { w.Version++ },
+		"dealers":  func(w *transcriptMessageWire) { w.Dealers = w.Dealers[1:] },
+		"order":    func(w *transcriptMessageWire) { w.Dealers[0], w.Dealers[1] = w.Dealers[1], w.Dealers[0] },
+		"dealer":   func(w *transcriptMessageWire) { w.Dealers[0] = -1 },
+		"dealing":  func(w *transcriptMessageWire) { w.Dealings[1] = nil },
+		"dealings": func(w *transcriptMessageWire) { w.Dealings = nil },
+	})
+	wiretest.AssertRejected(t, encoded[ComplaintMessageType], decode(ComplaintMessageType), map[string]func(w *complaintMessageWire){
+		"version":   func(w *complaintMessageWire) { w.Version++ },
+		"dealer":    func(w *complaintMessageWire) { w.Dealer = -1 },
+		"complaint": func(w *complaintMessageWire) { w.Complaint = nil },
+	})
+	wiretest.AssertRejected(t, encoded[OpeningMessageType], decode(OpeningMessageType), map[string]func(w *openingMessageWire){
+		"version":    func(w *openingMessageWire) { w.Version++ },
+		"dealer":     func(w *openingMessageWire) { w.Dealer = -1 },
+		"complainer": func(w *openingMessageWire) { w.Complainer = -1 },
+		"opening":    func(w *openingMessageWire) { w.Opening = nil },
+	})
+}
@@ -0,0 +1,636 @@
+package idkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/complaints"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/dealings"
+	"github.com/PlatONnetwork/tecdsa/mega"
+	"github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/PlatONnetwork/tecdsa/seed"
+	"github.com/pkg/errors"
+	"github.com/tidwall/btree"
+	"sort"
+	"time"
+)
+
+// Config describes one transcript and the part the local node plays in it.
+// Nodes are identified by their index; dealers are 0..Dealers-1 and
+// receivers are the indexes of Receivers.
+type Config struct {
+	CurveType               curve.EccCurveType
+	Operation               dealings.IDkgTranscriptOperationInternal
+	ReconstructionThreshold int
+	// VerificationThreshold is the number of receivers that must support a
+	// dealing before it may be part of the transcript. Defaults to
+	// ReconstructionThreshold, which leaves enough receivers to answer
+	// complaints against the dealing.
+	VerificationThreshold int
+	// Dealers defaults to the number of receivers.
+	Dealers   int
+	Receivers []*mega.MEGaPublicKey
+	// Coordinator chooses the dealings of the transcript.
+	Coordinator common.NodeIndex
+	Ad          []byte
+
+	Self common.NodeIndex
+	// Shares is the secret the node deals, nil if it does not deal.
+	Shares dealings.SecretShares
+	// PrivateKey is the key of the node as a receiver, nil if it does not
+	// receive.
+	PrivateKey *mega.MEGaPrivateKey
+	Seed       *seed.Seed
+	// Retransmit is the interval at which Run broadcasts the messages of
+	// the node again, so that lost messages do not stall the protocol.
+	// Defaults to DefaultRetransmit.
+	Retransmit time.Duration
+}
+
+const DefaultRetransmit = time.Second
+
+// pendingKey identifies a complaint or an opening received before the
+// transcript, so that a retransmission replaces the earlier copy.
+type pendingKey struct {
+	msgType            MessageType
+	from               common.NodeIndex
+	dealer, complainer common.NodeIndex
+}
+
+func (k pendingKey) less(other pendingKey) bool {
+	if k.from != other.from {
+		return k.from < other.from
+	}
+	if k.msgType != other.msgType {
+		return k.msgType < other.msgType
+	}
+	if k.dealer != other.dealer {
+		return k.dealer < other.dealer
+	}
+	return k.complainer < other.complainer
+}
+
+// receivedDealing is a publicly verified dealing and the digest its
+// supporters sign off on.
+type receivedDealing struct {
+	dealing *dealings.IDkgDealingInternal
+	digest  []byte
+}
+
+type complaintKey struct {
+	dealer, complainer common.NodeIndex
+}
+
+// Engine runs the protocol for one transcript on one node:
+//
+//	dealing -> support -> transcript -> complaints -> openings -> share
+//
+// It consumes messages from Handle and returns the messages the node must
+// broadcast in reply. The messages the node sends are also applied to its
+// own state, so they need not be delivered back to it. An Engine is not
+// safe for concurrent use.
+type Engine struct {
+	config Config
+	need   int
+
+	started bool
+	outbox  []Message
+	sent    []Message
+	pending map[pendingKey]Message
+	// buffered counts the pending messages of each sender.
+	buffered map[common.NodeIndex]int
+
+	dealings btree.Map[common.NodeIndex, *receivedDealing]
+	rejected btree.Map[common.NodeIndex, error]
+	// private holds the result of verifying the node's own share of each
+	// dealing.
+	private btree.Map[common.NodeIndex, error]
+	// supports maps each dealer to the supporters and the digest of the
+	// dealing they support.
+	supports btree.Map[common.NodeIndex, *btree.Map[common.NodeIndex, []byte]]
+	proposal *btree.Map[common.NodeIndex, *receivedDealing]
+
+	transcript *dealings.IDkgTranscriptInternal
+	selected   *btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal]
+	complaints map[complaintKey]bool
+	// openings collects, for each dealing the node complained about, the
+	// shares other receivers opened.
+	openings btree.Map[common.NodeIndex, *btree.Map[common.NodeIndex, poly.CommitmentOpening]]
+	share    poly.CommitmentOpening
+}
+
+// NewEngine checks the configuration and returns an engine waiting for
+// Start. The transcript is formed from at least ReconstructionThreshold
+// dealings, and never from fewer than the operation requires.
+func NewEngine(config Config) (*Engine, error) {
+	if _, ok := curve.Lookup(config.CurveType); !ok {
+		return nil, common.ErrUnknownCurve
+	}
+	if config.Operation == nil {
+		return nil, errors.New("missing transcript operation")
+	}
+	if config.ReconstructionThreshold <= 0 || config.ReconstructionThreshold > len(config.Receivers) {
+		return nil, errors.New("invalid reconstruction threshold")
+	}
+	if config.VerificationThreshold == 0 {
+		config.VerificationThreshold = config.ReconstructionThreshold
+	}
+	if config.VerificationThreshold < 0 || config.VerificationThreshold > len(config.Receivers) {
+		return nil, errors.New("invalid verification threshold")
+	}
+	if config.Retransmit == 0 {
+		config.Retransmit = DefaultRetransmit
+	}
+	if config.Retransmit < 0 {
+		return nil, errors.New("invalid retransmission interval")
+	}
+	if config.Dealers == 0 {
+		config.Dealers = len(config.Receivers)
+	}
+	for _, pk := range config.Receivers {
+		if pk.CurveType() != config.CurveType {
+			return nil, common.ErrCurveMismatch
+		}
+	}
+	if config.Seed == nil {
+		return nil, errors.New("missing seed")
+	}
+	if config.Shares != nil && int(config.Self) >= config.Dealers {
+		return nil, errors.New("the node is not a dealer")
+	}
+	if config.PrivateKey != nil {
+		if !config.isReceiver(config.Self) {
+			return nil, errors.New("the node is not a receiver")
+		}
+		if config.PrivateKey.CurveType() != config.CurveType {
+			return nil, common.ErrCurveMismatch
+		}
+	}
+	need, err := dealings.RequiredDealings(config.Operation)
+	if err != nil {
+		return nil, err
+	}
+	if need < config.ReconstructionThreshold {
+		need = config.ReconstructionThreshold
+	}
+	if need > config.Dealers {
+		return nil, &common.ErrInsufficientDealings{Have: config.Dealers, Need: need}
+	}
+	return &Engine{
+		config:     config,
+		need:       need,
+		complaints: make(map[complaintKey]bool),
+		pending:    make(map[pendingKey]Message),
+		buffered:   make(map[common.NodeIndex]int),
+	}, nil
+}
+
+func (c Config) isReceiver(index common.NodeIndex) bool {
+	return int(index) < len(c.Receivers)
+}
+
+func (c Config) isDealer(index common.NodeIndex) bool {
+	return int(index) < c.Dealers
+}
+
+// Start creates the node's dealing, if it deals. It returns the messages
+// to broadcast; calling it again returns none.
+func (e *Engine) Start() ([]Message, error) {
+	if e.started {
+		return nil, nil
+	}
+	e.started = true
+	var err error
+	if e.config.Shares != nil {
+		var dealing *dealings.IDkgDealingInternal
+		dealing, err = dealings.NewIDkgDealingInternal(e.config.Shares, e.config.CurveType, e.config.Seed.Derive("ic-crypto-tecdsa-engine-dealing"), e.config.ReconstructionThreshold, e.config.Receivers, e.config.Self, e.config.Ad)
+		if err == nil {
+			err = e.emit(&DealingMessage{Dealing: dealing})
+		}
+	}
+	return e.flush(), err
+}
+
+// Handle processes a message sent by from and returns the messages to
+// broadcast in reply. Messages that arrive before the state they refer to,
+// such as complaints before the transcript, are kept and processed later.
+// An error reports a message that had to be rejected, attributed to its
+// sender where possible with the typed errors of package common; the
+// engine stays usable and the messages are still returned.
+func (e *Engine) Handle(from common.NodeIndex, msg Message) ([]Message, error) {
+	err := e.handle(from, msg)
+	return e.flush(), err
+}
+
+// Transcript returns the transcript once the dealings are agreed on.
+func (e *Engine) Transcript() *dealings.IDkgTranscriptInternal {
+	return e.transcript
+}
+
+// Share returns the node's opening of the transcript once it is known,
+// which may need openings from other receivers.
+func (e *Engine) Share() poly.CommitmentOpening {
+	return e.share
+}
+
+// Done reports whether the transcript and, for a receiver, its share are
+// known.
+func (e *Engine) Done() bool {
+	return e.transcript != nil && (e.config.PrivateKey == nil || e.share != nil)
+}
+
+// Sent returns every message the node has broadcast so far. Handling a
+// message again has no effect, so they can be sent again to nodes that may
+// have missed them.
+func (e *Engine) Sent() []Message {
+	return append([]Message(nil), e.sent...)
+}
+
+func (e *Engine) flush() []Message {
+	out := e.outbox
+	e.outbox = nil
+	return out
+}
+
+func (e *Engine) emit(msg Message) error {
+	e.outbox = append(e.outbox, msg)
+	e.sent = append(e.sent, msg)
+	return e.handle(e.config.Self, msg)
+}
+
+func (e *Engine) handle(from common.NodeIndex, msg Message) error {
+	switch m := msg.(type) {
+	case *DealingMessage:
+		return e.handleDealing(from, m)
+	case *SupportMessage:
+		return e.handleSupport(from, m)
+	case *TranscriptMessage:
+		return e.handleTranscript(from, m)
+	case *ComplaintMessage:
+		return e.handleComplaint(from, m)
+	case *OpeningMessage:
+		return e.handleOpening(from, m)
+	}
+	return errors.New("unexpected message type")
+}
+
+func (e *Engine) handleDealing(from common.NodeIndex, m *DealingMessage) error {
+	if !e.config.isDealer(from) {
+		return errors.Errorf("dealing from node %d which is not a dealer", from)
+	}
+	if m.Dealing == nil {
+		return &common.ErrInvalidDealing{Dealer: from, Err: errors.New("empty dealing")}
+	}
+	if e.transcript != nil {
+		return nil
+	}
+	if _, ok := e.rejected.Get(from); ok {
+		return nil
+	}
+	// a retransmitted dealing is recognized before the costly verification
+	if known, ok := e.dealings.Get(from); ok {
+		if digest, err := dealingDigest(m.Dealing); err == nil && bytes.Equal(known.digest, digest) {
+			return nil
+		}
+	}
+	received, err := e.verifyDealing(from, m.Dealing)
+	if err != nil {
+		e.rejected.Set(from, err)
+		return err
+	}
+	if known, ok := e.dealings.Get(from); ok {
+		if bytes.Equal(known.digest, received.digest) {
+			return nil
+		}
+		return &common.ErrInvalidDealing{Dealer: from, Err: errors.New("conflicting dealings")}
+	}
+	if err := e.accept(from, received); err != nil {
+		return err
+	}
+	return e.progress()
+}
+
+// verifyDealing publicly verifies the dealing of dealer.
+func (e *Engine) verifyDealing(dealer common.NodeIndex, dealing *dealings.IDkgDealingInternal) (*receivedDealing, error) {
+	config := e.config
+	if err := dealing.PubliclyVerify(config.CurveType, config.Operation, config.ReconstructionThreshold, dealer, len(config.Receivers), config.Ad); err != nil {
+		if _, ok := common.MisbehavingDealer(err); !ok {
+			err = &common.ErrInvalidDealing{Dealer: dealer, Err: err}
+		}
+		return nil, err
+	}
+	digest, err := dealingDigest(dealing)
+	if err != nil {
+		return nil, &common.ErrInvalidDealing{Dealer: dealer, Err: err}
+	}
+	return &receivedDealing{dealing: dealing, digest: digest}, nil
+}
+
+func dealingDigest(dealing *dealings.IDkgDealingInternal) ([]byte, error) {
+	data, err := dealing.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(data)
+	return digest[:], nil
+}
+
+// accept records a publicly verified dealing and, if the node's share of it
+// is valid, supports it.
+func (e *Engine) accept(dealer common.NodeIndex, received *receivedDealing) error {
+	e.dealings.Set(dealer, received)
+	config := e.config
+	if config.PrivateKey == nil {
+		return nil
+	}
+	err := received.dealing.PrivateVerify(config.CurveType, config.PrivateKey, config.Receivers[config.Self], config.Ad, dealer, config.Self)
+	e.private.Set(dealer, err)
+	if err != nil {
+		return nil
+	}
+	return e.emit(&SupportMessage{Dealer: dealer, Digest: received.digest})
+}
+
+func (e *Engine) handleSupport(from common.NodeIndex, m *SupportMessage) error {
+	if !e.config.isReceiver(from) {
+		return errors.Errorf("support from node %d which is not a receiver", from)
+	}
+	if !e.config.isDealer(m.Dealer) {
+		return errors.Errorf("support for node %d which is not a dealer", m.Dealer)
+	}
+	supports, ok := e.supports.Get(m.Dealer)
+	if !ok {
+		supports = new(btree.Map[common.NodeIndex, []byte])
+		e.supports.Set(m.Dealer, supports)
+	}
+	supports.Set(from, m.Digest)
+	return e.progress()
+}
+
+func (e *Engine) supported(dealer common.NodeIndex, received *receivedDealing) bool {
+	supports, ok := e.supports.Get(dealer)
+	if !ok {
+		return false
+	}
+	count := 0
+	supports.Scan(func(_ common.NodeIndex, digest []byte) bool {
+		if bytes.Equal(digest, received.digest) {
+			count++
+		}
+		return count < e.config.VerificationThreshold
+	})
+	return count >= e.config.VerificationThreshold
+}
+
+// handleTranscript adopts the dealings proposed by the coordinator,
+// replacing those the node received directly if they differ.
+func (e *Engine) handleTranscript(from common.NodeIndex, m *TranscriptMessage) error {
+	if from != e.config.Coordinator {
+		return errors.Errorf("transcript proposal from node %d which is not the coordinator", from)
+	}
+	if m.Dealings == nil || m.Dealings.Len() < e.need {
+		have := 0
+		if m.Dealings != nil {
+			have = m.Dealings.Len()
+		}
+		return &common.ErrInsufficientDealings{Have: have, Need: e.need}
+	}
+	if e.proposal != nil {
+		if !e.isProposal(m.Dealings) {
+			return errors.New("conflicting transcript proposals")
+		}
+		return nil
+	}
+	proposal := new(btree.Map[common.NodeIndex, *receivedDealing])
+	var err error
+	m.Dealings.Scan(func(dealer common.NodeIndex, dealing *dealings.IDkgDealingInternal) bool {
+		if !e.config.isDealer(dealer) || dealing == nil {
+			err = errors.Errorf("invalid dealer %d in the transcript proposal", dealer)
+			return false
+		}
+		var received *receivedDealing
+		if received, err = e.verifyDealing(dealer, dealing); err != nil {
+			err = errors.Wrap(err, "invalid dealing in the transcript proposal")
+			return false
+		}
+		proposal.Set(dealer, received)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	// the proposal is only recorded once its dealings are accepted, so that
+	// the supports emitted meanwhile cannot form the transcript early
+	dealers, values := proposal.KeyValues()
+	for i, dealer := range dealers {
+		if known, ok := e.dealings.Get(dealer); ok && bytes.Equal(known.digest, values[i].digest) {
+			continue
+		}
+		e.rejected.Delete(dealer)
+		if err := e.accept(dealer, values[i]); err != nil {
+			return err
+		}
+	}
+	e.proposal = proposal
+	return e.progress()
+}
+
+// isProposal reports whether proposed holds the dealings of the recorded
+// proposal, as when the coordinator retransmits it.
+func (e *Engine) isProposal(proposed *btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal]) bool {
+	if e.proposal.Len() != proposed.Len() {
+		return false
+	}
+	equal := true
+	e.proposal.Scan(func(dealer common.NodeIndex, x *receivedDealing) bool {
+		y, ok := proposed.Get(dealer)
+		equal = ok && y != nil
+		if equal {
+			digest, err := dealingDigest(y)
+			equal = err == nil && bytes.Equal(x.digest, digest)
+		}
+		return equal
+	})
+	return equal
+}
+
+// progress proposes the transcript once enough dealings are supported, if
+// the node coordinates, and forms it once every proposed dealing is.
+func (e *Engine) progress() error {
+	if e.transcript != nil {
+		return nil
+	}
+	if e.proposal == nil && e.config.Self == e.config.Coordinator {
+		proposal := new(btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal])
+		e.dealings.Scan(func(dealer common.NodeIndex, received *receivedDealing) bool {
+			if e.supported(dealer, received) {
+				proposal.Set(dealer, received.dealing)
+			}
+			return proposal.Len() < e.need
+		})
+		if proposal.Len() == e.need {
+			return e.emit(&TranscriptMessage{Dealings: proposal})
+		}
+	}
+	if e.proposal == nil {
+		return nil
+	}
+	selected := new(btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal])
+	complete := true
+	e.proposal.Scan(func(dealer common.NodeIndex, received *receivedDealing) bool {
+		complete = e.supported(dealer, received)
+		selected.Set(dealer, received.dealing)
+		return complete
+	})
+	if !complete {
+		return nil
+	}
+	transcript, err := dealings.NewTranscriptInternal(e.config.CurveType, e.config.ReconstructionThreshold, selected, e.config.Operation)
+	if err != nil {
+		return err
+	}
+	e.transcript, e.selected = transcript, selected
+	if err := e.openShare(); err != nil {
+		return err
+	}
+	keys := make([]pendingKey, 0, len(e.pending))
+	for key := range e.pending {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].less(keys[j]) })
+	pending := e.pending
+	e.pending, e.buffered = nil, nil
+	for _, key := range keys {
+		if perr := e.handle(key.from, pending[key]); perr != nil && err == nil {
+			err = perr
+		}
+	}
+	return err
+}
+
+// buffer keeps a message received before the transcript until it is
+// formed. A sender has at most one complaint and one opening for the node
+// per dealer to send, so anything beyond that is refused.
+func (e *Engine) buffer(key pendingKey, msg Message) error {
+	if _, ok := e.pending[key]; !ok {
+		if e.buffered[key.from] >= 2*e.config.Dealers {
+			return errors.Errorf("too many early messages from node %d", key.from)
+		}
+		e.buffered[key.from]++
+	}
+	e.pending[key] = msg
+	return nil
+}
+
+// openShare decrypts the node's share of the transcript, or complains
+// about the dealings it cannot decrypt.
+func (e *Engine) openShare() error {
+	config := e.config
+	if config.PrivateKey == nil {
+		return nil
+	}
+	allVerified := true
+	e.selected.Scan(func(dealer common.NodeIndex, _ *dealings.IDkgDealingInternal) bool {
+		err, ok := e.private.Get(dealer)
+		allVerified = ok && err == nil
+		return allVerified
+	})
+	if allVerified {
+		share, err := dealings.CommitmentOpening.FromDealings(e.selected, e.transcript.CombinedCommitment, config.Ad, config.Self, config.PrivateKey, config.Receivers[config.Self])
+		if err != nil {
+			return err
+		}
+		e.share = share
+		return nil
+	}
+	generated, err := complaints.GenerateComplaints(e.selected, config.Ad, config.Self, config.PrivateKey, config.Receivers[config.Self], config.Seed.Derive("ic-crypto-tecdsa-engine-complaints"))
+	if err != nil {
+		return err
+	}
+	dealers, values := generated.KeyValues()
+	for i := range dealers {
+		e.openings.Set(dealers[i], new(btree.Map[common.NodeIndex, poly.CommitmentOpening]))
+		if err := e.emit(&ComplaintMessage{Dealer: dealers[i], Complaint: values[i]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Engine) handleComplaint(from common.NodeIndex, m *ComplaintMessage) error {
+	if !e.config.isReceiver(from) {
+		return errors.Errorf("complaint from node %d which is not a receiver", from)
+	}
+	if m.Complaint == nil {
+		return errors.Wrapf(common.ErrInvalidComplaint, "empty complaint from node %d", from)
+	}
+	if e.transcript == nil {
+		return e.buffer(pendingKey{msgType: ComplaintMessageType, from: from, dealer: m.Dealer}, m)
+	}
+	dealing, ok := e.selected.Get(m.Dealer)
+	if !ok {
+		return errors.Wrapf(common.ErrInvalidComplaint, "complaint from node %d against dealer %d which is not in the transcript", from, m.Dealer)
+	}
+	key := complaintKey{dealer: m.Dealer, complainer: from}
+	if e.complaints[key] {
+		return nil
+	}
+	config := e.config
+	if err := m.Complaint.Verify(dealing, m.Dealer, from, config.Receivers[from], config.Ad); err != nil {
+		return errors.Wrapf(err, "complaint from node %d against dealer %d", from, m.Dealer)
+	}
+	e.complaints[key] = true
+	if from == config.Self || config.PrivateKey == nil {
+		return nil
+	}
+	if err, ok := e.private.Get(m.Dealer); !ok || err != nil {
+		return nil
+	}
+	opening, err := complaints.CreateOpening(dealing, m.Dealer, from, config.Self, config.PrivateKey, config.Ad)
+	if err != nil {
+		return err
+	}
+	return e.emit(&OpeningMessage{Dealer: m.Dealer, Complainer: from, Opening: opening})
+}
+
+func (e *Engine) handleOpening(from common.NodeIndex, m *OpeningMessage) error {
+	if m.Complainer != e.config.Self || from == e.config.Self {
+		return nil
+	}
+	if !e.config.isReceiver(from) {
+		return errors.Errorf("opening from node %d which is not a receiver", from)
+	}
+	if e.transcript == nil {
+		return e.buffer(pendingKey{msgType: OpeningMessageType, from: from, dealer: m.Dealer, complainer: m.Complainer}, m)
+	}
+	if e.share != nil {
+		return nil
+	}
+	openings, ok := e.openings.Get(m.Dealer)
+	if !ok {
+		return errors.Wrapf(common.ErrInvalidOpening, "opening from node %d for dealer %d which was not complained about", from, m.Dealer)
+	}
+	dealing, _ := e.selected.Get(m.Dealer)
+	if err := complaints.VerifyOpening(m.Opening, dealing, e.config.Self, from); err != nil {
+		return errors.Wrapf(err, "opening from node %d for dealer %d", from, m.Dealer)
+	}
+	openings.Set(from, m.Opening.Opening)
+
+	complete := true
+	e.openings.Scan(func(dealer common.NodeIndex, openings *btree.Map[common.NodeIndex, poly.CommitmentOpening]) bool {
+		dealing, _ := e.selected.Get(dealer)
+		complete = openings.Len() >= dealing.Commitment.Len()
+		return complete
+	})
+	if !complete {
+		return nil
+	}
+	config := e.config
+	share, err := dealings.CommitmentOpening.FromDealingsAndOpenings(e.selected, &e.openings, e.transcript.CombinedCommitment, config.Ad, config.Self, config.PrivateKey, config.Receivers[config.Self])
+	if err != nil {
+		return err
+	}
+	e.share = share
+	return nil
+}
@@ -0,0 +1,298 @@
+package idkg
+
+import (
+	"errors"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/complaints"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/dealings"
+	"github.com/PlatONnetwork/tecdsa/mega"
+	"github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/PlatONnetwork/tecdsa/rand"
+	seed2 "github.com/PlatONnetwork/tecdsa/seed"
+	"github.com/PlatONnetwork/tecdsa/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/btree"
+	"testing"
+)
+
+type envelope struct {
+	from, to common.NodeIndex
+	msg      Message
+}
+
+// testNetwork broadcasts the messages of the engines and delivers them in
+// a random order. tamper may replace or drop (by returning nil) a message
+// on its way to one node.
+type testNetwork struct {
+	engines []*Engine
+	queue   []envelope
+	rng     rand.Rand
+	tamper  func(e envelope) Message
+	errors  []error
+}
+
+func (n *testNetwork) broadcast(from common.NodeIndex, msgs []Message) {
+	for _, msg := range msgs {
+		for to := range n.engines {
+			if common.NodeIndex(to) != from {
+				n.queue = append(n.queue, envelope{from, common.NodeIndex(to), msg})
+			}
+		}
+	}
+}
+
+func (n *testNetwork) run(t *testing.T) {
+	for i, engine := range n.engines {
+		out, err := engine.Start()
+		assert.Nil(t, err)
+		n.broadcast(common.NodeIndex(i), out)
+	}
+	for len(n.queue) > 0 {
+		i := int(n.rng.Uint32()) % len(n.queue)
+		e := n.queue[i]
+		n.queue = append(n.queue[:i], n.queue[i+1:]...)
+		msg := e.msg
+		if n.tamper != nil {
+			if msg = n.tamper(e); msg == nil {
+				continue
+			}
+		}
+		out, err := n.engines[e.to].Handle(e.from, msg)
+		if err != nil {
+			n.errors = append(n.errors, err)
+		}
+		n.broadcast(e.to, out)
+	}
+}
+
+type testCommittee struct {
+	curveType curve.EccCurveType
+	threshold int
+	sk        []*mega.MEGaPrivateKey
+	pk        []*mega.MEGaPublicKey
+	ad        []byte
+	seed      *seed2.Seed
+}
+
+func newTestCommittee(curveType curve.EccCurveType, nodes int, threshold int) *testCommittee {
+	seed := seed2.FromBytes([]byte("idkg engine test"))
+	rng := seed.Derive("keys").Rng()
+	c := &testCommittee{curveType: curveType, threshold: threshold, ad: []byte("ad"), seed: seed}
+	for i := 0; i < nodes; i++ {
+		sk := mega.PrivateKey.GeneratePrivateKey(curveType, rng)
+		c.sk = append(c.sk, sk)
+		c.pk = append(c.pk, sk.PublicKey())
+	}
+	return c
+}
+
+// engines creates an engine for each node, dealing the shares returned by
+// shares.
+func (c *testCommittee) engines(t *testing.T, name string, operation dealings.IDkgTranscriptOperationInternal, shares func(i int) dealings.SecretShares) []*Engine {
+	var engines []*Engine
+	for i := range c.pk {
+		engine, err := NewEngine(Config{
+			CurveType:               c.curveType,
+			Operation:               operation,
+			ReconstructionThreshold: c.threshold,
+			Receivers:               c.pk,
+			Ad:                      c.ad,
+			Self:                    common.NodeIndex(i),
+			Shares:                  shares(i),
+			PrivateKey:              c.sk[i],
+			Seed:                    c.seed.Derive(name).Derive(string(rune('a' + i))),
+		})
+		assert.Nil(t, err)
+		engines = append(engines, engine)
+	}
+	return engines
+}
+
+func (c *testCommittee) network(t *testing.T, name string, operation dealings.IDkgTranscriptOperationInternal, shares func(i int) dealings.SecretShares) *testNetwork {
+	return &testNetwork{
+		engines: c.engines(t, name, operation, shares),
+		rng:     c.seed.Derive(name + "-delivery").Rng(),
+	}
+}
+
+func (n *testNetwork) assertAgreement(t *testing.T) *dealings.IDkgTranscriptInternal {
+	return assertAgreement(t, n.engines)
+}
+
+func assertAgreement(t *testing.T, engines []*Engine) *dealings.IDkgTranscriptInternal {
+	transcript := engines[0].Transcript()
+	if !assert.NotNil(t, transcript) {
+		return nil
+	}
+	for i, engine := range engines {
+		assert.True(t, engine.Done(), i)
+		assert.Equal(t, 1, transcript.CombinedCommitment.Equal(engine.Transcript().CombinedCommitment))
+		assert.True(t, transcript.CombinedCommitment.CheckOpening(common.NodeIndex(i), engine.Share()))
+	}
+	return transcript
+}
+
+func TestEngineFormsTranscriptsEndToEnd(t *testing.T) {
+	c := newTestCommittee(curve.K256, 4, 2)
+	random := c.network(t, "random", &dealings.RandomTranscript{}, func(int) dealings.SecretShares { return &dealings.RandomSecret{} })
+	random.run(t)
+	assert.Empty(t, random.errors)
+	masked := random.assertAgreement(t)
+
+	reshare := func(name string) *dealings.IDkgTranscriptInternal {
+		n := c.network(t, name, &dealings.ReshareOfMaskedTranscript{P1: masked.CombinedCommitment}, func(i int) dealings.SecretShares {
+			o := random.engines[i].Share().(poly.PedersenCommitmentOpening)
+			return &dealings.ReshareOfMaskedSecret{S1: o[0], S2: o[1]}
+		})
+		n.run(t)
+		assert.Empty(t, n.errors)
+		return n.assertAgreement(t)
+	}
+	first, second := reshare("reshare-1"), reshare("reshare-2")
+	assert.Equal(t, dealings.ReshareOfMaskedOperation, first.OperationType)
+	assert.Equal(t, 1, first.ConstantTerm().Equal(second.ConstantTerm()))
+}
+
+func TestEngineRecoversCorruptedSharesFromOpenings(t *testing.T) {
+	c := newTestCommittee(curve.K256, 4, 2)
+	// only nodes 0 and 1 deal, so both dealings are in the transcript, and
+	// node 1 sends everyone a dealing with a bad share for node 2
+	n := c.network(t, "corrupted", &dealings.RandomTranscript{}, func(i int) dealings.SecretShares {
+		if i == 0 {
+			return &dealings.RandomSecret{}
+		}
+		return nil
+	})
+	dealing, err := dealings.NewIDkgDealingInternal(&dealings.RandomSecret{}, c.curveType, c.seed.Derive("dealing"), c.threshold, c.pk, 1, c.ad)
+	assert.Nil(t, err)
+	corrupted, err := testutils.CorruptDealing(dealing, []common.NodeIndex{2}, c.seed.Derive("corrupt"))
+	assert.Nil(t, err)
+	n.queue = append(n.queue, envelope{1, 1, &DealingMessage{Dealing: corrupted}})
+	n.broadcast(1, []Message{&DealingMessage{Dealing: corrupted}})
+
+	var complaints, openings int
+	n.tamper = func(e envelope) Message {
+		switch e.msg.(type) {
+		case *ComplaintMessage:
+			complaints++
+		case *OpeningMessage:
+			openings++
+		}
+		return e.msg
+	}
+	n.run(t)
+	assert.Empty(t, n.errors)
+	n.assertAgreement(t)
+	// the complaint of node 2 reached the three other nodes, and the opening
+	// each of them broadcast in reply reached three nodes as well
+	assert.Equal(t, 3, complaints)
+	assert.Equal(t, 9, openings)
+}
+
+func TestEngineBuffersRetransmittedEarlyMessagesOnce(t *testing.T) {
+	c := newTestCommittee(curve.K256, 4, 2)
+	engine := c.engines(t, "early", &dealings.RandomTranscript{}, func(int) dealings.SecretShares { return nil })[0]
+	for i := 0; i < 10; i++ {
+		_, err := engine.Handle(2, &ComplaintMessage{Dealer: 1, Complaint: &complaints.IDkgComplaintInternal{}})
+		assert.Nil(t, err)
+		_, err = engine.Handle(2, &OpeningMessage{Dealer: 1, Complainer: 0, Opening: &complaints.IDkgOpeningInternal{}})
+		assert.Nil(t, err)
+	}
+	assert.Len(t, engine.pending, 2)
+
+	// a sender may complain about and open the share of each of the four
+	// dealers once
+	var err error
+	for dealer := common.NodeIndex(0); err == nil; dealer++ {
+		_, err = engine.Handle(3, &ComplaintMessage{Dealer: dealer, Complaint: &complaints.IDkgComplaintInternal{}})
+	}
+	assert.EqualError(t, err, "too many early messages from node 3")
+	assert.Len(t, engine.pending, 10)
+	_, err = engine.Handle(3, &ComplaintMessage{Dealer: 0, Complaint: &complaints.IDkgComplaintInternal{}})
+	assert.Nil(t, err)
+}
+
+func TestEngineRejectsMisbehavingNodes(t *testing.T) {
+	c := newTestCommittee(curve.K256, 4, 2)
+	n := c.network(t, "misbehaving", &dealings.RandomTranscript{}, func(int) dealings.SecretShares { return &dealings.RandomSecret{} })
+	var replayed *DealingMessage
+	n.tamper = func(e envelope) Message {
+		if m, ok := e.msg.(*DealingMessage); ok && e.from == 0 && replayed == nil {
+			replayed = m
+		}
+		// node 3 sends some nodes the dealing of node 0 as its own, which
+		// they reject; the others may still agree on its real dealing
+		if _, ok := e.msg.(*DealingMessage); ok && e.from == 3 && replayed != nil {
+			return replayed
+		}
+		return e.msg
+	}
+	n.run(t)
+	transcript := n.assertAgreement(t)
+	assert.NotEmpty(t, n.errors)
+	for _, err := range n.errors {
+		dealer, ok := common.MisbehavingDealer(err)
+		assert.True(t, ok)
+		assert.Equal(t, common.NodeIndex(3), dealer)
+	}
+	if transcript == nil {
+		return
+	}
+	all := new(btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal])
+	n.engines[0].dealings.Scan(func(dealer common.NodeIndex, received *receivedDealing) bool {
+		all.Set(dealer, received.dealing)
+		return true
+	})
+	_, err := n.engines[1].Handle(0, &TranscriptMessage{Dealings: all})
+	assert.EqualError(t, err, "conflicting transcript proposals")
+	_, err = n.engines[1].Handle(2, &TranscriptMessage{Dealings: all})
+	assert.NotNil(t, err)
+	_, err = n.engines[1].Handle(7, &SupportMessage{Dealer: 0})
+	assert.NotNil(t, err)
+}
+
+func TestEngineWaitsForEnoughDealings(t *testing.T) {
+	c := newTestCommittee(curve.K256, 4, 2)
+	random := c.network(t, "random", &dealings.RandomTranscript{}, func(int) dealings.SecretShares { return &dealings.RandomSecret{} })
+	random.run(t)
+	masked := random.assertAgreement(t)
+
+	// only one dealer of a threshold two transcript
+	n := c.network(t, "one-dealer", &dealings.ReshareOfMaskedTranscript{P1: masked.CombinedCommitment}, func(i int) dealings.SecretShares {
+		if i != 0 {
+			return nil
+		}
+		o := random.engines[i].Share().(poly.PedersenCommitmentOpening)
+		return &dealings.ReshareOfMaskedSecret{S1: o[0], S2: o[1]}
+	})
+	n.run(t)
+	assert.Empty(t, n.errors)
+	for _, engine := range n.engines {
+		assert.False(t, engine.Done())
+		assert.Nil(t, engine.Transcript())
+	}
+
+	// a product of two threshold two transcripts needs three dealers
+	product := &dealings.UnmaskedTimesMaskedTranscript{Left: poly.SimpleCM.New(masked.CombinedCommitment.Points()), Right: masked.CombinedCommitment}
+	_, err := NewEngine(Config{
+		CurveType:               c.curveType,
+		Operation:               product,
+		ReconstructionThreshold: c.threshold,
+		Dealers:                 2,
+		Receivers:               c.pk,
+		Seed:                    c.seed,
+	})
+	assert.True(t, errors.Is(err, common.ErrInsufficientQuorum))
+	engine, err := NewEngine(Config{
+		CurveType:               c.curveType,
+		Operation:               product,
+		ReconstructionThreshold: c.threshold,
+		Receivers:               c.pk,
+		Self:                    1,
+		Seed:                    c.seed,
+	})
+	assert.Nil(t, err)
+	_, err = engine.Handle(0, &TranscriptMessage{Dealings: new(btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal])})
+	assert.True(t, errors.Is(err, common.ErrInsufficientQuorum))
+}
@@ -0,0 +1,81 @@
+package idkg
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/complaints"
+	"github.com/PlatONnetwork/tecdsa/dealings"
+	"github.com/tidwall/btree"
+)
+
+const (
+	DealingMessageType    = MessageType(1)
+	SupportMessageType    = MessageType(2)
+	TranscriptMessageType = MessageType(3)
+	ComplaintMessageType  = MessageType(4)
+	OpeningMessageType    = MessageType(5)
+)
+
+type MessageType int
+
+// Message is one step of the protocol, broadcast to every node of the
+// transcript. The sender is not part of the message: the transport must
+// authenticate it and pass it to Engine.Handle. Messages.Serialize and
+// Messages.Deserialize encode messages for the wire.
+type Message interface {
+	Type() MessageType
+	Serialize() ([]byte, error)
+}
+
+// DealingMessage carries the dealing of its sender.
+type DealingMessage struct {
+	Dealing *dealings.IDkgDealingInternal
+}
+
+// SupportMessage states that its sender verified the dealing of Dealer
+// with the given Digest, including its own share.
+type SupportMessage struct {
+	Dealer common.NodeIndex
+	Digest []byte
+}
+
+// TranscriptMessage is sent by the coordinator and fixes the dealings that
+// all nodes combine into the transcript. It carries the dealings so that a
+// dealer that sent different dealings to different nodes cannot split them.
+type TranscriptMessage struct {
+	Dealings *btree.Map[common.NodeIndex, *dealings.IDkgDealingInternal]
+}
+
+// ComplaintMessage is sent by a receiver whose share of the dealing of
+// Dealer does not decrypt correctly.
+type ComplaintMessage struct {
+	Dealer    common.NodeIndex
+	Complaint *complaints.IDkgComplaintInternal
+}
+
+// OpeningMessage answers the complaint of Complainer against Dealer with the
+// sender's share of that dealing.
+type OpeningMessage struct {
+	Dealer     common.NodeIndex
+	Complainer common.NodeIndex
+	Opening    *complaints.IDkgOpeningInternal
+}
+
+func (DealingMessage) Type() MessageType {
+	return DealingMessageType
+}
+
+func (SupportMessage) Type() MessageType {
+	return SupportMessageType
+}
+
+func (TranscriptMessage) Type() MessageType {
+	return TranscriptMessageType
+}
+
+func (ComplaintMessage) Type() MessageType {
+	return ComplaintMessageType
+}
+
+func (OpeningMessage) Type() MessageType {
+	return OpeningMessageType
+}
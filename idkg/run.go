@@ -0,0 +1,83 @@
+package idkg
+
+import (
+	"context"
+	"github.com/PlatONnetwork/tecdsa/transport"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// Run drives engine over network until ctx is done: it broadcasts what Start
+// returns and hands every Message received to Handle. The network should
+// use Messages as its codec. onDone is called once
+// the engine is done and onError with every message it rejects; both may be
+// nil. A node keeps running after it is done to answer the complaints of the
+// others, so Run returns nil when ctx ends after the engine is done. Every
+// Config.Retransmit Run broadcasts all the messages of the node again, so a
+// message lost by the network only delays the protocol.
+func Run(ctx context.Context, engine *Engine, network transport.Network, onDone func(), onError func(error)) error {
+	report := func(err error) {
+		if onError != nil {
+			onError(err)
+		}
+	}
+	done := false
+	out, err := engine.Start()
+	if err != nil {
+		return err
+	}
+	next := time.Now().Add(engine.config.Retransmit)
+	for {
+		if !time.Now().Before(next) {
+			out = engine.Sent()
+			next = time.Now().Add(engine.config.Retransmit)
+		}
+		for _, msg := range out {
+			if err := network.Broadcast(ctx, msg); err != nil {
+				return finished(ctx, done, err)
+			}
+		}
+		if !done && engine.Done() {
+			done = true
+			if onDone != nil {
+				onDone()
+			}
+		}
+		e, err := receive(ctx, network, next)
+		if err == context.DeadlineExceeded && ctx.Err() == nil {
+			out = nil
+			continue
+		}
+		if errors.Is(err, transport.ErrUndecodable) {
+			report(err)
+			out = nil
+			continue
+		}
+		if err != nil {
+			return finished(ctx, done, err)
+		}
+		msg, ok := e.Payload.(Message)
+		if !ok {
+			report(errors.Errorf("unexpected payload from node %d", e.From))
+			out = nil
+			continue
+		}
+		if out, err = engine.Handle(e.From, msg); err != nil {
+			report(err)
+		}
+	}
+}
+
+// receive waits for the next message until the deadline.
+func receive(ctx context.Context, network transport.Network, deadline time.Time) (*transport.Envelope, error) {
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	return network.Receive(ctx)
+}
+
+func finished(ctx context.Context, done bool, err error) error {
+	if done && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,118 @@
+package idkg
+
+import (
+	"context"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/dealings"
+	"github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/PlatONnetwork/tecdsa/transport"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runOverNetwork runs each engine on its own goroutine until all of them
+// are done.
+func runOverNetwork(t *testing.T, engines []*Engine, network *transport.SimulatedNetwork, whilePartitioned time.Duration) []error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	var mu sync.Mutex
+	var rejected []error
+	var done, stopped sync.WaitGroup
+	done.Add(len(engines))
+	for i, engine := range engines {
+		node, err := network.Node(common.NodeIndex(i))
+		assert.Nil(t, err)
+		stopped.Add(1)
+		go func(engine *Engine) {
+			defer stopped.Done()
+			err := Run(ctx, engine, node, done.Done, func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				rejected = append(rejected, err)
+			})
+			assert.Nil(t, err)
+		}(engine)
+	}
+	if whilePartitioned > 0 {
+		time.Sleep(whilePartitioned)
+		network.Heal()
+	}
+	done.Wait()
+	cancel()
+	stopped.Wait()
+	return rejected
+}
+
+func TestRunGeneratesKeysOverASimulatedNetwork(t *testing.T) {
+	c := newTestCommittee(curve.K256, 4, 2)
+	newNetwork := func() *transport.SimulatedNetwork {
+		network, err := transport.NewSimulatedNetwork(transport.SimulatedConfig{
+			Nodes:      len(c.pk),
+			MaxLatency: 2 * time.Millisecond,
+			Reorder:    true,
+			Seed:       c.seed,
+			Codec:      Messages,
+		})
+		assert.Nil(t, err)
+		return network
+	}
+
+	network := newNetwork()
+	defer network.Close()
+	network.Partition([]common.NodeIndex{0, 1})
+	random := c.engines(t, "random", &dealings.RandomTranscript{}, func(int) dealings.SecretShares { return &dealings.RandomSecret{} })
+	assert.Empty(t, runOverNetwork(t, random, network, 20*time.Millisecond))
+	masked := assertAgreement(t, random)
+	if masked == nil {
+		return
+	}
+
+	network = newNetwork()
+	defer network.Close()
+	key := c.engines(t, "key", &dealings.ReshareOfMaskedTranscript{P1: masked.CombinedCommitment}, func(i int) dealings.SecretShares {
+		o := random[i].Share().(poly.PedersenCommitmentOpening)
+		return &dealings.ReshareOfMaskedSecret{S1: o[0], S2: o[1]}
+	})
+	assert.Empty(t, runOverNetwork(t, key, network, 0))
+	unmasked := assertAgreement(t, key)
+	if unmasked == nil {
+		return
+	}
+
+	// the shares of the key interpolate to the secret of the public key
+	indexes := make([]common.NodeIndex, 0, len(key))
+	values := make([]curve.EccScalar, 0, len(key))
+	for i, engine := range key {
+		indexes = append(indexes, common.NodeIndex(i))
+		values = append(values, engine.Share().(poly.SimpleCommitmentOpening)[0])
+	}
+	coefficients, err := poly.Lagrange.AtZero(c.curveType, indexes)
+	assert.Nil(t, err)
+	secret, err := coefficients.InterpolateScalar(values)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, curve.Point.MulByG(secret).Equal(unmasked.ConstantTerm()))
+}
+
+func TestRunRetransmitsLostMessages(t *testing.T) {
+	c := newTestCommittee(curve.K256, 4, 2)
+	network, err := transport.NewSimulatedNetwork(transport.SimulatedConfig{
+		Nodes:      len(c.pk),
+		MaxLatency: 2 * time.Millisecond,
+		Reorder:    true,
+		DropRate:   0.3,
+		Seed:       c.seed,
+		Codec:      Messages,
+	})
+	assert.Nil(t, err)
+	defer network.Close()
+	random := c.engines(t, "lossy", &dealings.RandomTranscript{}, func(int) dealings.SecretShares { return &dealings.RandomSecret{} })
+	for _, engine := range random {
+		engine.config.Retransmit = 20 * time.Millisecond
+	}
+	assert.Empty(t, runOverNetwork(t, random, network, 0))
+	assertAgreement(t, random)
+	assert.NotZero(t, network.Stats().Dropped)
+}
@@ -1,9 +1,22 @@
 package key
 
+import "github.com/PlatONnetwork/tecdsa/curve"
+
 type MasterEcdsaPublicKey struct {
+	// CurveType of PublicKey. Keys created before other curves were
+	// supported leave it unset, which means K256.
+	CurveType curve.EccCurveType
 	PublicKey []byte
 }
 
+// Curve returns the curve of the key, K256 when CurveType is unset.
+func (m MasterEcdsaPublicKey) Curve() curve.EccCurveType {
+	if m.CurveType == 0 {
+		return curve.K256
+	}
+	return m.CurveType
+}
+
 type EcdsaPublicKey struct {
 	PublicKey []byte
 	ChainKey  []byte
@@ -0,0 +1,12 @@
+package key
+
+import (
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMasterEcdsaPublicKeyDefaultsToK256(t *testing.T) {
+	assert.Equal(t, curve.K256, MasterEcdsaPublicKey{}.Curve())
+	assert.Equal(t, curve.P256, MasterEcdsaPublicKey{CurveType: curve.P256}.Curve())
+}
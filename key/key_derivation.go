@@ -91,7 +91,7 @@ func ckdpub(pk curve.EccPoint, chainKey []byte, index DerivationIndex) (curve.Ec
 
 func (d *DerivationPath) DeriveTweak(pk curve.EccPoint) (curve.EccScalar, []byte, error) {
 	curveType := pk.CurveType()
-	if curveType != curve.K256 {
+	if curveType != curve.K256 && curveType != curve.P256 {
 		return nil, nil, errors.New("invalid curve type")
 	}
 	derivedKey := pk.Clone()
@@ -1,6 +1,7 @@
 package mega
 
 import (
+	"context"
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
 	"github.com/PlatONnetwork/tecdsa/poly"
@@ -63,6 +64,13 @@ type MEGaCiphertext interface {
 }
 
 func EncryptCiphertextSingle(seed *seed.Seed, plaintexts []curve.EccScalar, recipients []*MEGaPublicKey, dealerIndex common.NodeIndex, ad []byte) (*MEGaCiphertextSingle, error) {
+	return EncryptCiphertextSingleContext(common.Sequential(), seed, plaintexts, recipients, dealerIndex, ad)
+}
+
+// EncryptCiphertextSingleContext is EncryptCiphertextSingle with the
+// per-recipient encryptions spread over the worker pool of ctx. The
+// ciphertext is the same as the sequential one.
+func EncryptCiphertextSingleContext(ctx context.Context, seed *seed.Seed, plaintexts []curve.EccScalar, recipients []*MEGaPublicKey, dealerIndex common.NodeIndex, ad []byte) (*MEGaCiphertextSingle, error) {
 	if err := checkPlaintexts(plaintexts, recipients); err != nil {
 		return nil, err
 	}
@@ -71,16 +79,20 @@ func EncryptCiphertextSingle(seed *seed.Seed, plaintexts []curve.EccScalar, reci
 	if err != nil {
 		return nil, err
 	}
+	defer beta.Zeroize()
 	ctexts := make([]curve.EccScalar, len(recipients))
-	for index := 0; index < len(recipients); index++ {
+	err = common.ParallelFor(ctx, len(recipients), func(index int) error {
 		pubkey, ptext := recipients[index], plaintexts[index]
 		ubeta := pubkey.point.Clone().ScalarMul(pubkey.point, beta)
 		hm, err := megaHashToScalars(CiphertextSingle, dealerIndex, common.NodeIndex(index), ad, pubkey.point, v, ubeta)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		ctext := hm[0].Add(hm[0], ptext)
-		ctexts[index] = ctext
+		ctexts[index] = hm[0].Add(hm[0], ptext)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return &MEGaCiphertextSingle{
 		EphemeralKey: v,
@@ -92,20 +104,20 @@ func EncryptCiphertextSingle(seed *seed.Seed, plaintexts []curve.EccScalar, reci
 
 func checkPlaintexts(plaintexts []curve.EccScalar, recipients []*MEGaPublicKey) error {
 	if len(plaintexts) != len(recipients) {
-		return errors.New("Must be as many plaintexts as recipients")
+		return errors.Wrap(common.ErrInvalidRecipients, "Must be as many plaintexts as recipients")
 	}
 	if len(plaintexts) == 0 {
-		return errors.New("Must encrypt at least one plaintext")
+		return errors.Wrap(common.ErrInvalidRecipients, "Must encrypt at least one plaintext")
 	}
 	curveType := plaintexts[0].CurveType()
 	for i := range plaintexts {
 		if plaintexts[i].CurveType() != curveType {
-			return errors.New("curve type mismatch")
+			return common.ErrCurveMismatch
 		}
 	}
 	for i := range recipients {
 		if recipients[i].CurveType() != curveType {
-			return errors.New("curve type mismatch")
+			return common.ErrCurveMismatch
 		}
 	}
 	return nil
@@ -113,20 +125,20 @@ func checkPlaintexts(plaintexts []curve.EccScalar, recipients []*MEGaPublicKey)
 
 func checkPlaintextsPair(plaintexts [][2]curve.EccScalar, recipients []*MEGaPublicKey) error {
 	if len(plaintexts) != len(recipients) {
-		return errors.New("Must be as many plaintexts as recipients")
+		return errors.Wrap(common.ErrInvalidRecipients, "Must be as many plaintexts as recipients")
 	}
 	if len(plaintexts) == 0 {
-		return errors.New("Must encrypt at least one plaintext")
+		return errors.Wrap(common.ErrInvalidRecipients, "Must encrypt at least one plaintext")
 	}
 	curveType := plaintexts[0][0].CurveType()
 	for i := range plaintexts {
 		if plaintexts[i][0].CurveType() != curveType || plaintexts[i][1].CurveType() != curveType {
-			return errors.New("curve type mismatch")
+			return common.ErrCurveMismatch
 		}
 	}
 	for i := range recipients {
 		if recipients[i].CurveType() != curveType {
-			return errors.New("curve type mismatch")
+			return common.ErrCurveMismatch
 		}
 	}
 	return nil
@@ -174,21 +186,21 @@ func (m MEGaCiphertextSingle) Proof() *zk.ProofOfDLogEquivalence {
 
 func (m MEGaCiphertextSingle) CheckValidity(expectedRecipients int, ad []byte, dealerIndex common.NodeIndex) error {
 	if m.Recipients() != expectedRecipients {
-		return errors.New("invalid recipients")
+		return errors.Wrap(common.ErrInvalidCiphertext, "invalid recipients")
 	}
 	return m.VerifyPop(ad, dealerIndex)
 }
 func (m MEGaCiphertextSingle) VerifyIs(ctype MEGaCiphertextType, curveType curve.EccCurveType) error {
 	if m.EphemeralKey.CurveType() != curveType || m.PopPublicKey.CurveType() != curveType || m.PopProof.CurveType() != curveType {
-		return errors.New("curve mismatch")
+		return common.ErrCurveMismatch
 	}
 	for _, c := range m.CTexts {
 		if c.CurveType() != curveType {
-			return errors.New("curve mismatch")
+			return common.ErrCurveMismatch
 		}
 	}
 	if m.CType() != ctype {
-		return errors.New("inconsistent ciphertext")
+		return common.ErrInvalidCiphertext
 	}
 	return nil
 }
@@ -199,13 +211,15 @@ func (m MEGaCiphertextSingle) VerifyPop(ad []byte, dealerIndex common.NodeIndex)
 
 func (m MEGaCiphertextSingle) DecryptFromSharedSecret(ad []byte, dealerIndex common.NodeIndex, recipientIndex common.NodeIndex, recipientPublicKey *MEGaPublicKey, sharedSecret curve.EccPoint) (curve.EccScalar, error) {
 	if len(m.CTexts) <= int(recipientIndex) {
-		return nil, errors.New("invalid index")
+		return nil, errors.Wrap(common.ErrInvalidRecipients, "invalid index")
 	}
 	hm, err := megaHashToScalars(CiphertextSingle, dealerIndex, recipientIndex, ad, recipientPublicKey.point, m.EphemeralKey, sharedSecret)
 	if err != nil {
 		return nil, err
 	}
-	return m.CTexts[int(recipientIndex)].Clone().Sub(m.CTexts[int(recipientIndex)], hm[0]), nil
+	ptext := m.CTexts[int(recipientIndex)].Clone().Sub(m.CTexts[int(recipientIndex)], hm[0])
+	curve.Scalar.Zeroize(hm...)
+	return ptext, nil
 }
 func (m MEGaCiphertextSingle) Decrypt(ad []byte, dealerIndex common.NodeIndex, recipientIndex common.NodeIndex, privateKey *MEGaPrivateKey, recipientPublicKey *MEGaPublicKey) (curve.EccScalar, error) {
 	if err := m.VerifyPop(ad, dealerIndex); err != nil {
@@ -221,7 +235,8 @@ func (m MEGaCiphertextSingle) DecryptAndCheck(commitment poly.PolynomialCommitme
 	}
 	opening := poly.SimpleCommitmentOpening{scalar}
 	if !commitment.CheckOpening(receiverIndex, opening) {
-		return nil, errors.New("invalid commitment")
+		opening.Zeroize()
+		return nil, common.ErrInvalidCommitment
 	}
 	return opening, nil
 }
@@ -234,6 +249,12 @@ type MEGaCiphertextPair struct {
 }
 
 func EncryptCiphertextPair(seed *seed.Seed, plaintexts [][2]curve.EccScalar, recipients []*MEGaPublicKey, dealerIndex common.NodeIndex, ad []byte) (*MEGaCiphertextPair, error) {
+	return EncryptCiphertextPairContext(common.Sequential(), seed, plaintexts, recipients, dealerIndex, ad)
+}
+
+// EncryptCiphertextPairContext is EncryptCiphertextPair with the
+// per-recipient encryptions spread over the worker pool of ctx.
+func EncryptCiphertextPairContext(ctx context.Context, seed *seed.Seed, plaintexts [][2]curve.EccScalar, recipients []*MEGaPublicKey, dealerIndex common.NodeIndex, ad []byte) (*MEGaCiphertextPair, error) {
 	if err := checkPlaintextsPair(plaintexts, recipients); err != nil {
 		return nil, err
 	}
@@ -241,17 +262,22 @@ func EncryptCiphertextPair(seed *seed.Seed, plaintexts [][2]curve.EccScalar, rec
 	if err != nil {
 		return nil, err
 	}
+	defer beta.Zeroize()
 	ctexts := make([][2]curve.EccScalar, len(recipients))
-	for index := 0; index < len(recipients); index++ {
+	err = common.ParallelFor(ctx, len(recipients), func(index int) error {
 		pubkey, ptext := recipients[index], plaintexts[index]
 		ubeta := pubkey.point.Clone().ScalarMul(pubkey.point, beta)
 		hm, err := megaHashToScalars(CiphertextPairs, dealerIndex, common.NodeIndex(index), ad, pubkey.point, v, ubeta)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		ctext0 := hm[0].Add(hm[0], ptext[0])
 		ctext1 := hm[1].Add(hm[1], ptext[1])
 		ctexts[index] = [2]curve.EccScalar{ctext0, ctext1}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return &MEGaCiphertextPair{
 		EphemeralKey: v,
@@ -287,7 +313,7 @@ func (m MEGaCiphertextPair) VerifyPop(ad []byte, dealerIndex common.NodeIndex) e
 
 func (m MEGaCiphertextPair) DecryptFromSharedSecret(ad []byte, dealerIndex common.NodeIndex, recipientIndex common.NodeIndex, recipientPublicKey *MEGaPublicKey, sharedSecret curve.EccPoint) ([2]curve.EccScalar, error) {
 	if len(m.CTexts) <= int(recipientIndex) {
-		return [2]curve.EccScalar{}, errors.New("invalid index")
+		return [2]curve.EccScalar{}, errors.Wrap(common.ErrInvalidRecipients, "invalid index")
 	}
 	hm, err := megaHashToScalars(CiphertextPairs, dealerIndex, recipientIndex, ad, recipientPublicKey.point, m.EphemeralKey, sharedSecret)
 	if err != nil {
@@ -295,6 +321,7 @@ func (m MEGaCiphertextPair) DecryptFromSharedSecret(ad []byte, dealerIndex commo
 	}
 	ptext0 := m.CTexts[int(recipientIndex)][0].Clone().Sub(m.CTexts[int(recipientIndex)][0], hm[0])
 	ptext1 := m.CTexts[int(recipientIndex)][1].Clone().Sub(m.CTexts[int(recipientIndex)][1], hm[1])
+	curve.Scalar.Zeroize(hm...)
 	return [2]curve.EccScalar{ptext0, ptext1}, nil
 
 }
@@ -308,22 +335,22 @@ func (m MEGaCiphertextPair) Decrypt(ad []byte, dealerIndex common.NodeIndex, rec
 
 func (m MEGaCiphertextPair) CheckValidity(expectedRecipients int, ad []byte, dealerIndex common.NodeIndex) error {
 	if m.Recipients() != expectedRecipients {
-		return errors.New("invalid recipients")
+		return errors.Wrap(common.ErrInvalidCiphertext, "invalid recipients")
 	}
 	return m.VerifyPop(ad, dealerIndex)
 }
 
 func (m MEGaCiphertextPair) VerifyIs(ctype MEGaCiphertextType, curveType curve.EccCurveType) error {
 	if m.EphemeralKey.CurveType() != curveType || m.PopPublicKey.CurveType() != curveType || m.PopProof.CurveType() != curveType {
-		return errors.New("curve mismatch")
+		return common.ErrCurveMismatch
 	}
 	for _, c := range m.CTexts {
 		if c[0].CurveType() != curveType || c[1].CurveType() != curveType {
-			return errors.New("curve mismatch")
+			return common.ErrCurveMismatch
 		}
 	}
 	if m.CType() != ctype {
-		return errors.New("inconsistent ciphertext")
+		return common.ErrInvalidCiphertext
 	}
 	return nil
 }
@@ -336,7 +363,8 @@ func (m MEGaCiphertextPair) DecryptAndCheck(commitment poly.PolynomialCommitment
 	opening[0] = scalar[0]
 	opening[1] = scalar[1]
 	if !commitment.CheckOpening(receiverIndex, opening) {
-		return nil, errors.New("invalid commitment")
+		opening.Zeroize()
+		return nil, common.ErrInvalidCommitment
 	}
 	return opening, nil
 }
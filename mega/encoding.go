@@ -0,0 +1,193 @@
+package mega
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/zk"
+	"github.com/pkg/errors"
+)
+
+var (
+	Ciphertext = ciphertext{}
+)
+
+type ciphertext struct{}
+
+// megaCiphertextWire is the encoding shared by both ciphertext types. Points
+// are tagged with their curve, and CTexts holds one entry per recipient
+// with one scalar for single and two for pair ciphertexts.
+type megaCiphertextWire struct {
+	Version      uint8
+	CurveType    curve.EccCurveType
+	CType        MEGaCiphertextType
+	EphemeralKey []byte
+	PopPublicKey []byte
+	PopProof     *zk.ProofOfDLogEquivalence
+	CTexts       [][][]byte
+}
+
+func (m MEGaCiphertextType) scalarsPerRecipient() int {
+	if m == CiphertextPairs {
+		return 2
+	}
+	return 1
+}
+
+func newCiphertextWire(ctype MEGaCiphertextType, ephemeralKey, popPublicKey curve.EccPoint, popProof *zk.ProofOfDLogEquivalence, ctexts [][]curve.EccScalar) *megaCiphertextWire {
+	encoded := make([][][]byte, len(ctexts), len(ctexts))
+	for i, c := range ctexts {
+		encoded[i] = make([][]byte, len(c), len(c))
+		for j := range c {
+			encoded[i][j] = c[j].Serialize()
+		}
+	}
+	return &megaCiphertextWire{
+		Version:      common.WireVersion,
+		CurveType:    ephemeralKey.CurveType(),
+		CType:        ctype,
+		EphemeralKey: ephemeralKey.SerializeTagged(),
+		PopPublicKey: popPublicKey.SerializeTagged(),
+		PopProof:     popProof,
+		CTexts:       encoded,
+	}
+}
+
+// decode checks the structure of the ciphertext and that every component
+// belongs to its curve.
+func (w *megaCiphertextWire) decode(ctype MEGaCiphertextType) (curve.EccPoint, curve.EccPoint, [][]curve.EccScalar, error) {
+	if w.Version != common.WireVersion {
+		return nil, nil, nil, errors.Wrapf(common.ErrInvalidEncoding, "unsupported ciphertext version %d", w.Version)
+	}
+	if _, ok := curve.Lookup(w.CurveType); !ok {
+		return nil, nil, nil, common.ErrUnknownCurve
+	}
+	if w.CType != ctype {
+		return nil, nil, nil, errors.Wrap(common.ErrInvalidCiphertext, "unexpected ciphertext type")
+	}
+	if w.PopProof == nil || w.PopProof.CurveType() != w.CurveType {
+		return nil, nil, nil, errors.Wrap(common.ErrInvalidCiphertext, "invalid proof of possession")
+	}
+	if len(w.CTexts) == 0 {
+		return nil, nil, nil, errors.Wrap(common.ErrInvalidCiphertext, "ciphertext without recipients")
+	}
+	ephemeralKey, err := curve.Point.DeserializeTagged(w.CurveType, w.EphemeralKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	popPublicKey, err := curve.Point.DeserializeTagged(w.CurveType, w.PopPublicKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ctexts := make([][]curve.EccScalar, len(w.CTexts), len(w.CTexts))
+	for i, c := range w.CTexts {
+		if len(c) != ctype.scalarsPerRecipient() {
+			return nil, nil, nil, errors.Wrap(common.ErrInvalidCiphertext, "unexpected number of ciphertext scalars")
+		}
+		ctexts[i] = make([]curve.EccScalar, len(c), len(c))
+		for j := range c {
+			if ctexts[i][j], err = curve.Scalar.DeserializeCanonical(w.CurveType, c[j]); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+	return ephemeralKey, popPublicKey, ctexts, nil
+}
+
+// Deserialize decodes either ciphertext type.
+func (ciphertext) Deserialize(data []byte) (MEGaCiphertext, error) {
+	var w megaCiphertextWire
+	if err := common.UnmarshalStrict(data, &w); err != nil {
+		return nil, err
+	}
+	switch w.CType {
+	case CiphertextSingle:
+		m := &MEGaCiphertextSingle{}
+		return m, m.fromWire(&w)
+	case CiphertextPairs:
+		m := &MEGaCiphertextPair{}
+		return m, m.fromWire(&w)
+	}
+	return nil, errors.Wrap(common.ErrInvalidCiphertext, "unknown ciphertext type")
+}
+
+func (m MEGaCiphertextSingle) wire() (*megaCiphertextWire, error) {
+	ctexts := make([][]curve.EccScalar, len(m.CTexts), len(m.CTexts))
+	for i, c := range m.CTexts {
+		ctexts[i] = []curve.EccScalar{c}
+	}
+	return newCiphertextWire(CiphertextSingle, m.EphemeralKey, m.PopPublicKey, m.PopProof, ctexts), nil
+}
+
+func (m *MEGaCiphertextSingle) fromWire(w *megaCiphertextWire) error {
+	ephemeralKey, popPublicKey, ctexts, err := w.decode(CiphertextSingle)
+	if err != nil {
+		return err
+	}
+	m.EphemeralKey, m.PopPublicKey, m.PopProof = ephemeralKey, popPublicKey, w.PopProof
+	m.CTexts = make([]curve.EccScalar, len(ctexts), len(ctexts))
+	for i, c := range ctexts {
+		m.CTexts[i] = c[0]
+	}
+	return nil
+}
+
+func (m MEGaCiphertextSingle) Serialize() ([]byte, error) {
+	return m.MarshalCBOR()
+}
+
+func (m MEGaCiphertextSingle) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(m.wire())
+}
+
+func (m *MEGaCiphertextSingle) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, m.fromWire)
+}
+
+func (m MEGaCiphertextSingle) MarshalJSON() ([]byte, error) {
+	return common.MarshalWireJSON(m.wire())
+}
+
+func (m *MEGaCiphertextSingle) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalWireJSON(data, m.fromWire)
+}
+
+func (m MEGaCiphertextPair) wire() (*megaCiphertextWire, error) {
+	ctexts := make([][]curve.EccScalar, len(m.CTexts), len(m.CTexts))
+	for i, c := range m.CTexts {
+		ctexts[i] = []curve.EccScalar{c[0], c[1]}
+	}
+	return newCiphertextWire(CiphertextPairs, m.EphemeralKey, m.PopPublicKey, m.PopProof, ctexts), nil
+}
+
+func (m *MEGaCiphertextPair) fromWire(w *megaCiphertextWire) error {
+	ephemeralKey, popPublicKey, ctexts, err := w.decode(CiphertextPairs)
+	if err != nil {
+		return err
+	}
+	m.EphemeralKey, m.PopPublicKey, m.PopProof = ephemeralKey, popPublicKey, w.PopProof
+	m.CTexts = make([][2]curve.EccScalar, len(ctexts), len(ctexts))
+	for i, c := range ctexts {
+		m.CTexts[i] = [2]curve.EccScalar{c[0], c[1]}
+	}
+	return nil
+}
+
+func (m MEGaCiphertextPair) Serialize() ([]byte, error) {
+	return m.MarshalCBOR()
+}
+
+func (m MEGaCiphertextPair) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(m.wire())
+}
+
+func (m *MEGaCiphertextPair) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, m.fromWire)
+}
+
+func (m MEGaCiphertextPair) MarshalJSON() ([]byte, error) {
+	return common.MarshalWireJSON(m.wire())
+}
+
+func (m *MEGaCiphertextPair) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalWireJSON(data, m.fromWire)
+}
@@ -0,0 +1,86 @@
+package mega
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	seed2 "github.com/PlatONnetwork/tecdsa/seed"
+	"github.com/PlatONnetwork/tecdsa/testutils/wiretest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCiphertextSerializationRoundTrips(t *testing.T) {
+	rng := seed2.FromBytes(genkey(44, 32)).Rng()
+	ad := []byte("assoc_data_test")
+	for _, curveType := range []curve.EccCurveType{curve.K256, curve.P256, curve.ED25519} {
+		sk := PrivateKey.GeneratePrivateKey(curveType, rng)
+		pk := sk.PublicKey()
+		single, err := EncryptCiphertextSingle(seed2.FromRng(rng), []curve.EccScalar{curve.Scalar.Random(curveType, rng)}, []*MEGaPublicKey{pk}, 3, ad)
+		assert.Nil(t, err)
+		pair, err := EncryptCiphertextPair(seed2.FromRng(rng), [][2]curve.EccScalar{{curve.Scalar.Random(curveType, rng), curve.Scalar.Random(curveType, rng)}}, []*MEGaPublicKey{pk}, 3, ad)
+		assert.Nil(t, err)
+
+		for _, ctext := range []interface {
+			MEGaCiphertext
+			Serialize() ([]byte, error)
+		}{single, pair} {
+			bytes, err := ctext.Serialize()
+			assert.Nil(t, err)
+			decoded, err := Ciphertext.Deserialize(bytes)
+			assert.Nil(t, err)
+			assert.Equal(t, ctext.CType(), decoded.CType())
+			assert.Nil(t, decoded.CheckValidity(1, ad, 3))
+			assert.Nil(t, decoded.VerifyIs(ctext.CType(), curveType))
+
+			js, err := json.Marshal(ctext)
+			assert.Nil(t, err)
+			var w megaCiphertextWire
+			assert.Nil(t, json.Unmarshal(js, &w))
+			assert.Equal(t, curveType, w.CurveType)
+		}
+		bytes, err := single.Serialize()
+		assert.Nil(t, err)
+		_, err = Ciphertext.Deserialize(append(bytes, 0))
+		assert.NotNil(t, err)
+		var fromJson MEGaCiphertextSingle
+		js, err := json.Marshal(single)
+		assert.Nil(t, err)
+		assert.Nil(t, json.Unmarshal(js, &fromJson))
+		again, err := fromJson.Serialize()
+		assert.Nil(t, err)
+		assert.Equal(t, bytes, again)
+	}
+}
+
+func TestCiphertextDeserializationRejectsInvalidParts(t *testing.T) {
+	rng := seed2.FromBytes(genkey(45, 32)).Rng()
+	curveType := curve.K256
+	pk := PrivateKey.GeneratePrivateKey(curveType, rng).PublicKey()
+	single, err := EncryptCiphertextSingle(seed2.FromRng(rng), []curve.EccScalar{curve.Scalar.Random(curveType, rng)}, []*MEGaPublicKey{pk}, 0, nil)
+	assert.Nil(t, err)
+	bytes, err := single.Serialize()
+	assert.Nil(t, err)
+
+	invalid := map[string]func(w *megaCiphertextWire){
+		"version":             func(w *megaCiphertextWire) { w.Version = 0 },
+		"type":                func(w *megaCiphertextWire) { w.CType = CiphertextPairs },
+		"curve":               func(w *megaCiphertextWire) { w.CurveType = curve.P256 },
+		"point tag":           func(w *megaCiphertextWire) { w.EphemeralKey[0] = curve.P256.Tag() },
+		"no recipients":       func(w *megaCiphertextWire) { w.CTexts = nil },
+		"no proof":            func(w *megaCiphertextWire) { w.PopProof = nil },
+		"too many scalars":    func(w *megaCiphertextWire) { w.CTexts[0] = append(w.CTexts[0], w.CTexts[0][0]) },
+		"scalar is the order": func(w *megaCiphertextWire) { w.CTexts[0][0] = curve.GroupOrder.Bytes() },
+		"short scalar":        func(w *megaCiphertextWire) { w.CTexts[0][0] = w.CTexts[0][0][1:] },
+		"uncompressed point": func(w *megaCiphertextWire) {
+			w.PopPublicKey = append([]byte{curve.K256.Tag()}, single.PopPublicKey.SerializeUncompressed()...)
+		},
+		"invalid point prefix": func(w *megaCiphertextWire) { w.PopPublicKey[1] = 0x05 },
+	}
+	wiretest.AssertRejected(t, bytes, Ciphertext.Deserialize, invalid)
+	_, err = Ciphertext.Deserialize(wiretest.Reencode(t, bytes, invalid["no proof"]))
+	assert.True(t, errors.Is(err, common.ErrInvalidCiphertext))
+	_, err = Ciphertext.Deserialize(wiretest.Reencode(t, bytes, invalid["version"]))
+	assert.True(t, errors.Is(err, common.ErrInvalidEncoding))
+}
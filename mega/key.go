@@ -99,3 +99,8 @@ func (m MEGaPrivateKey) Serialize() []byte {
 func (m MEGaPrivateKey) SecretScalar() curve.EccScalar {
 	return m.secret
 }
+
+// Zeroize wipes the secret scalar, the key is unusable afterwards.
+func (m *MEGaPrivateKey) Zeroize() {
+	m.secret.Zeroize()
+}
@@ -24,7 +24,7 @@ func genRng() rand.Rand {
 	return rng
 }
 func all() []curve.EccCurveType {
-	return []curve.EccCurveType{curve.K256}
+	return []curve.EccCurveType{curve.K256, curve.P256}
 }
 func TestGenKeypair(t *testing.T) {
 	key := genkey(0x42, 32)
@@ -51,3 +51,13 @@ func TestMegaKeyValidity(t *testing.T) {
 		assert.NotNil(t, VerifyMegaPublicKey(curveType, pkBytes))
 	}
 }
+
+func TestPrivateKeyZeroize(t *testing.T) {
+	for _, curveType := range []curve.EccCurveType{curve.K256, curve.P256} {
+		_, sk, err := GenKeypair(curveType, seed2.FromBytes(genkey(7, 32)))
+		assert.Nil(t, err)
+		assert.Equal(t, 0, sk.SecretScalar().IsZero())
+		sk.Zeroize()
+		assert.Equal(t, 1, sk.SecretScalar().IsZero())
+	}
+}
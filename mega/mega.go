@@ -52,6 +52,16 @@ func VerifyPop(ctype MEGaCiphertextType, ad []byte, dealerIndex common.NodeIndex
 	return popProof.Verify(curve.Point.GeneratorG(curveType), popBase, ephemeralKey, popPublicKey, ad)
 }
 
+// AddPopToBatch adds the check of VerifyPop to batch.
+func AddPopToBatch(batch *zk.BatchVerifier, ctype MEGaCiphertextType, ad []byte, dealerIndex common.NodeIndex, ephemeralKey curve.EccPoint, popPublicKey curve.EccPoint, popProof *zk.ProofOfDLogEquivalence) error {
+	curveType := ephemeralKey.CurveType()
+	popBase, err := ComputePopBase(ctype, curveType, ad, dealerIndex, ephemeralKey)
+	if err != nil {
+		return err
+	}
+	return batch.AddDLogEquivalence(popProof, curve.Point.GeneratorG(curveType), popBase, ephemeralKey, popPublicKey, ad)
+}
+
 /// Compute the ephemeral key and associated Proof Of Possession
 ///
 /// The ephemeral key (here, `v`) is simply an ECDH public key, whose secret key
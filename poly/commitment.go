@@ -1,6 +1,7 @@
 package poly
 
 import (
+	"bytes"
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
 	"github.com/fxamacker/cbor/v2"
@@ -8,9 +9,10 @@ import (
 )
 
 var (
-	SimpleCM   = simpleCommitment{}
-	PedersenCM = pedersenCommitment{}
-	Commitment = commitmentOpening{}
+	SimpleCM     = simpleCommitment{}
+	PedersenCM   = pedersenCommitment{}
+	PolynomialCM = polynomialCommitment{}
+	Commitment   = commitmentOpening{}
 )
 
 type CommitmentOpeningBytes interface {
@@ -33,6 +35,8 @@ type CommitmentOpening interface {
 	ToCommitmentOpeningBytes() CommitmentOpeningBytes
 	Serialize() ([]byte, error)
 	ToString() string
+	// Zeroize wipes the opened scalars.
+	Zeroize()
 }
 type commitmentOpening struct {
 }
@@ -54,22 +58,18 @@ func (commitmentOpening) Deserialize(data []byte) (CommitmentOpening, error) {
 	if err := cbor.Unmarshal(data, &c); err != nil {
 		return nil, err
 	}
+	if _, ok := curve.Lookup(c.CurveType); !ok {
+		return nil, common.ErrUnknownCurve
+	}
 	switch c.CommitType {
 	case Simple:
-		switch c.CurveType {
-		case curve.K256:
-			o := &SimpleCommitmentOpening{curve.Scalar.Zero(c.CurveType)}
-			cbor.Unmarshal(c.Message, &o)
-			return o, nil
-		}
-
+		o := &SimpleCommitmentOpening{curve.Scalar.Zero(c.CurveType)}
+		cbor.Unmarshal(c.Message, &o)
+		return o, nil
 	case Pedersen:
-		switch c.CurveType {
-		case curve.K256:
-			o := &PedersenCommitmentOpening{curve.Scalar.Zero(c.CurveType), curve.Scalar.Zero(c.CurveType)}
-			cbor.Unmarshal(c.Message, &o)
-			return o, nil
-		}
+		o := &PedersenCommitmentOpening{curve.Scalar.Zero(c.CurveType), curve.Scalar.Zero(c.CurveType)}
+		cbor.Unmarshal(c.Message, &o)
+		return o, nil
 	}
 	return nil, errors.New("unknown commitment type")
 }
@@ -94,6 +94,9 @@ func (s SimpleCommitmentOpening) ToString() string {
 func (s SimpleCommitmentOpening) ToCommitmentOpeningBytes() CommitmentOpeningBytes {
 	return &SimpleCommitmentOpeningBytes{curve.Scalar.ToScalarBytes(s[0])}
 }
+func (s SimpleCommitmentOpening) Zeroize() {
+	curve.Scalar.Zeroize(s[:]...)
+}
 
 func (p PedersenCommitmentOpening) Serialize() ([]byte, error) {
 	data, err := cbor.Marshal(p)
@@ -113,6 +116,9 @@ func (PedersenCommitmentOpening) ToString() string {
 func (p PedersenCommitmentOpening) ToCommitmentOpeningBytes() CommitmentOpeningBytes {
 	return &PedersenCommitmentOpeningBytes{curve.Scalar.ToScalarBytes(p[0]), curve.Scalar.ToScalarBytes(p[1])}
 }
+func (p PedersenCommitmentOpening) Zeroize() {
+	curve.Scalar.Zeroize(p[:]...)
+}
 
 type SimpleCommitment struct {
 	points []curve.EccPoint
@@ -160,18 +166,9 @@ func (s *SimpleCommitment) Clone() PolynomialCommitment {
 }
 
 func (s *SimpleCommitment) Serialize() ([]byte, error) {
-	var data []byte
-	var err error
-	switch s.CurveType() {
-	case curve.K256:
-		ps := make([]*curve.Secp256k1Point, len(s.points), len(s.points))
-		for i := range s.points {
-			ps[i] = s.points[i].(*curve.Secp256k1Point)
-		}
-		data, err = cbor.Marshal(ps)
-		if err != nil {
-			return nil, err
-		}
+	data, err := marshalPoints(s.points)
+	if err != nil {
+		return nil, err
 	}
 	c := &polynomialCommitmentCbor{
 		CurveType:      s.CurveType(),
@@ -187,8 +184,8 @@ func (s *SimpleCommitment) StableRepresentation() []byte {
 	commitmentTag := byte('S')
 	r = append(r, commitmentTag)
 	r = append(r, curveType.Tag())
-	for _, point := range s.Points() {
-		r = append(r, point.Serialize()...)
+	for _, point := range curve.Point.BatchSerialize(s.Points()) {
+		r = append(r, point...)
 	}
 	return r
 }
@@ -222,14 +219,14 @@ func (s *SimpleCommitment) ReturnOpeningIfConsistent(index common.NodeIndex, ope
 	if s.CheckOpening(index, opening) {
 		return opening, nil
 	}
-	return nil, errors.New("invalid commitment")
+	return nil, common.ErrInvalidCommitment
 }
 func (s *SimpleCommitment) VerifyIs(ctype PolynomialCommitmentType, curveType curve.EccCurveType) error {
 	if s.CurveType() != curveType {
-		return errors.New("curve type mismatch")
+		return common.ErrCurveMismatch
 	}
 	if s.Type() != ctype {
-		return errors.New("unexpected commitment type")
+		return common.ErrUnexpectedCommitmentType
 	}
 	return nil
 }
@@ -282,20 +279,10 @@ func (s *PedersenCommitment) Clone() PolynomialCommitment {
 }
 
 func (p *PedersenCommitment) Serialize() ([]byte, error) {
-	var data []byte
-	var err error
-	switch p.CurveType() {
-	case curve.K256:
-		ps := make([]*curve.Secp256k1Point, len(p.points), len(p.points))
-		for i := range p.points {
-			ps[i] = p.points[i].(*curve.Secp256k1Point)
-		}
-		data, err = cbor.Marshal(ps)
-		if err != nil {
-			return nil, err
-		}
+	data, err := marshalPoints(p.points)
+	if err != nil {
+		return nil, err
 	}
-
 	c := &polynomialCommitmentCbor{
 		CurveType:      p.CurveType(),
 		CommitmentType: Pedersen,
@@ -310,8 +297,8 @@ func (p *PedersenCommitment) StableRepresentation() []byte {
 	commitmentTag := byte('P')
 	r = append(r, commitmentTag)
 	r = append(r, curveType.Tag())
-	for _, point := range p.Points() {
-		r = append(r, point.Serialize()...)
+	for _, point := range curve.Point.BatchSerialize(p.Points()) {
+		r = append(r, point...)
 	}
 	return r
 }
@@ -345,14 +332,14 @@ func (p *PedersenCommitment) ReturnOpeningIfConsistent(index common.NodeIndex, o
 	if p.CheckOpening(index, opening) {
 		return opening, nil
 	}
-	return nil, errors.New("invalid commitment")
+	return nil, common.ErrInvalidCommitment
 }
 func (p *PedersenCommitment) VerifyIs(ctype PolynomialCommitmentType, curveType curve.EccCurveType) error {
 	if p.CurveType() != curveType {
-		return errors.New("curve type mismatch")
+		return common.ErrCurveMismatch
 	}
 	if p.Type() != ctype {
-		return errors.New("unexpected commitment type")
+		return common.ErrUnexpectedCommitmentType
 	}
 	return nil
 }
@@ -398,27 +385,76 @@ type PolynomialCommitment interface {
 
 type polynomialCommitment struct{}
 
-func (polynomialCommitment) Deserialize(bytes []byte) (PolynomialCommitment, error) {
+// Deserialize decodes either commitment type. Only the encoding Serialize
+// produces is accepted, with the fields in declaration order as before the
+// wire format was versioned, and every point must be tagged with the curve
+// of the commitment.
+func (polynomialCommitment) Deserialize(data []byte) (PolynomialCommitment, error) {
+	c, err := decodeCommitment(data)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := c.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(encoded, data) {
+		return nil, errors.Wrap(common.ErrInvalidEncoding, "non unique commitment encoding")
+	}
+	return c, nil
+}
+
+func decodeCommitment(data []byte) (PolynomialCommitment, error) {
 	var c polynomialCommitmentCbor
-	if err := cbor.Unmarshal(bytes, &c); err != nil {
+	if err := cbor.Unmarshal(data, &c); err != nil {
 		return nil, err
 	}
+	if _, ok := curve.Lookup(c.CurveType); !ok {
+		return nil, common.ErrUnknownCurve
+	}
 	switch c.CommitmentType {
 	case Simple:
-		switch c.CurveType {
-		case curve.K256:
-			var ps []*curve.Secp256k1Point
-			if err := cbor.Unmarshal(c.Message, &ps); err != nil {
-				return nil, err
-			}
-			points := make([]curve.EccPoint, len(ps), len(ps))
-			for i, _ := range points {
-				points[i] = ps[i]
-			}
-			return &SimpleCommitment{points: points}, nil
+		points, err := unmarshalPoints(c.CurveType, c.Message)
+		if err != nil {
+			return nil, err
 		}
+		return &SimpleCommitment{points: points}, nil
 	case Pedersen:
+		points, err := unmarshalPoints(c.CurveType, c.Message)
+		if err != nil {
+			return nil, err
+		}
+		return &PedersenCommitment{points: points}, nil
 	}
-	return nil, errors.New("invalid commitment")
+	return nil, common.ErrInvalidCommitment
+}
+
+// marshalPoints encodes the points as a CBOR array of tagged serializations,
+// the same layout the curve point types produce with MarshalCBOR.
+func marshalPoints(points []curve.EccPoint) ([]byte, error) {
+	affine := curve.Point.BatchToAffine(points)
+	ps := make([][]byte, len(affine), len(affine))
+	for i := range affine {
+		ps[i] = affine[i].SerializeTagged()
+	}
+	return common.MarshalCanonical(ps)
+}
 
+func unmarshalPoints(curveType curve.EccCurveType, data []byte) ([]curve.EccPoint, error) {
+	var ps [][]byte
+	if err := common.UnmarshalStrict(data, &ps); err != nil {
+		return nil, err
+	}
+	if len(ps) == 0 {
+		return nil, errors.New("commitment without points")
+	}
+	points := make([]curve.EccPoint, len(ps), len(ps))
+	for i := range ps {
+		pt, err := curve.Point.DeserializeTagged(curveType, ps[i])
+		if err != nil {
+			return nil, err
+		}
+		points[i] = pt
+	}
+	return points, nil
 }
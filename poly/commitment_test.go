@@ -3,8 +3,10 @@ package poly
 import (
 	crand "crypto/rand"
 	"encoding/hex"
+	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
 	"github.com/PlatONnetwork/tecdsa/rand"
+	"github.com/fxamacker/cbor/v2"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -30,6 +32,43 @@ func TestCommitmentSerialize(t *testing.T) {
 	assert.Equal(t, open.(*SimpleCommitment).Len(), len(s.points))
 }
 
+func TestPedersenCommitmentSerialize(t *testing.T) {
+	for _, curveType := range all() {
+		p := PedersenCommitment{points: []curve.EccPoint{curve.Point.GeneratorG(curveType), curve.Point.GeneratorH(curveType)}}
+		bytes, err := p.Serialize()
+		assert.Nil(t, err)
+		c, err := polynomialCommitment{}.Deserialize(bytes)
+		assert.Nil(t, err)
+		assert.Equal(t, Pedersen, c.Type())
+		assert.Equal(t, 1, p.Equal(c))
+	}
+}
+
+func TestCommitmentDeserializationIsStrict(t *testing.T) {
+	p := PedersenCommitment{points: []curve.EccPoint{curve.Point.GeneratorG(curve.K256), curve.Point.GeneratorH(curve.K256)}}
+	bytes, err := p.Serialize()
+	assert.Nil(t, err)
+	_, err = PolynomialCM.Deserialize(append(bytes, 0))
+	assert.NotNil(t, err)
+
+	var c SimpleCommitment
+	assert.NotNil(t, c.UnmarshalCBOR(bytes))
+	js, err := p.MarshalJSON()
+	assert.Nil(t, err)
+	assert.NotNil(t, c.UnmarshalJSON(js))
+	var decoded PedersenCommitment
+	assert.Nil(t, decoded.UnmarshalJSON(js))
+	assert.Equal(t, 1, p.Equal(&decoded))
+
+	mixed := SimpleCommitment{points: []curve.EccPoint{curve.Point.GeneratorG(curve.K256), curve.Point.GeneratorG(curve.P256)}}
+	bytes, err = mixed.Serialize()
+	assert.Nil(t, err)
+	_, err = PolynomialCM.Deserialize(bytes)
+	assert.NotNil(t, err)
+
+	assert.NotNil(t, c.UnmarshalJSON([]byte(`{"CurveType":1,"CommitmentType":1,"Points":[]}`)))
+}
+
 func TestPolySimpleCommitments(t *testing.T) {
 	key, _ := crand.Prime(crand.Reader, 256)
 	rng := rand.NewChaCha20(key.Bytes())
@@ -57,3 +96,35 @@ func TestPolyPedersenCommitments(t *testing.T) {
 		}
 	}
 }
+
+func TestCommitmentEncodingIsUnchanged(t *testing.T) {
+	// the bytes Serialize produced for the commitments to G, 2G and 3G
+	// before the wire format was versioned
+	legacy := map[PolynomialCommitmentType]string{
+		Simple:   "a369437572766554797065016e436f6d6d69746d656e745479706501674d657373616765835822010279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f8179858220102c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee558220102f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+		Pedersen: "a369437572766554797065016e436f6d6d69746d656e745479706502674d657373616765835822010279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f8179858220102c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee558220102f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+	}
+	var points []curve.EccPoint
+	for i := uint64(1); i <= 3; i++ {
+		points = append(points, curve.Point.MulByG(curve.Scalar.FromUint64(curve.K256, i)))
+	}
+	for ctype, commitment := range map[PolynomialCommitmentType]PolynomialCommitment{Simple: SimpleCM.New(points), Pedersen: PedersenCM.New(points)} {
+		data, err := hex.DecodeString(legacy[ctype])
+		assert.Nil(t, err)
+		encoded, err := commitment.Serialize()
+		assert.Nil(t, err)
+		assert.Equal(t, data, encoded)
+		decoded, err := PolynomialCM.Deserialize(data)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, commitment.Equal(decoded))
+
+		// the same fields in another order are not the encoding
+		var w polynomialCommitmentCbor
+		assert.Nil(t, cbor.Unmarshal(data, &w))
+		canonical, err := common.MarshalCanonical(&w)
+		assert.Nil(t, err)
+		assert.NotEqual(t, data, canonical)
+		_, err = PolynomialCM.Deserialize(canonical)
+		assert.ErrorIs(t, err, common.ErrInvalidEncoding)
+	}
+}
@@ -0,0 +1,122 @@
+package poly
+
+import (
+	"encoding/json"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/pkg/errors"
+)
+
+// MarshalCBOR and UnmarshalCBOR let the commitments be embedded in other
+// CBOR messages with the encoding of Serialize and Deserialize. The JSON
+// form carries the same fields with the points as a plain list.
+
+type polynomialCommitmentJson struct {
+	CurveType      curve.EccCurveType
+	CommitmentType PolynomialCommitmentType
+	Points         [][]byte
+}
+
+func commitmentToJson(c PolynomialCommitment) ([]byte, error) {
+	if c.Len() == 0 {
+		return nil, errors.New("commitment without points")
+	}
+	affine := curve.Point.BatchToAffine(c.Points())
+	points := make([][]byte, len(affine), len(affine))
+	for i := range affine {
+		points[i] = affine[i].SerializeTagged()
+	}
+	return json.Marshal(&polynomialCommitmentJson{
+		CurveType:      c.CurveType(),
+		CommitmentType: c.Type(),
+		Points:         points,
+	})
+}
+
+func commitmentFromJson(data []byte, ctype PolynomialCommitmentType) ([]curve.EccPoint, error) {
+	var c polynomialCommitmentJson
+	if err := common.UnmarshalJSONStrict(data, &c); err != nil {
+		return nil, err
+	}
+	if _, ok := curve.Lookup(c.CurveType); !ok {
+		return nil, common.ErrUnknownCurve
+	}
+	if c.CommitmentType != ctype {
+		return nil, common.ErrUnexpectedCommitmentType
+	}
+	if len(c.Points) == 0 {
+		return nil, errors.New("commitment without points")
+	}
+	points := make([]curve.EccPoint, len(c.Points), len(c.Points))
+	for i := range c.Points {
+		pt, err := curve.Point.DeserializeTagged(c.CurveType, c.Points[i])
+		if err != nil {
+			return nil, err
+		}
+		points[i] = pt
+	}
+	return points, nil
+}
+
+func commitmentFromCbor(data []byte, ctype PolynomialCommitmentType) ([]curve.EccPoint, error) {
+	c, err := PolynomialCM.Deserialize(data)
+	if err != nil {
+		return nil, err
+	}
+	if c.Type() != ctype {
+		return nil, common.ErrUnexpectedCommitmentType
+	}
+	return c.Points(), nil
+}
+
+func (s *SimpleCommitment) MarshalCBOR() ([]byte, error) {
+	return s.Serialize()
+}
+
+func (s *SimpleCommitment) UnmarshalCBOR(data []byte) error {
+	points, err := commitmentFromCbor(data, Simple)
+	if err != nil {
+		return err
+	}
+	s.points = points
+	return nil
+}
+
+func (s *SimpleCommitment) MarshalJSON() ([]byte, error) {
+	return commitmentToJson(s)
+}
+
+func (s *SimpleCommitment) UnmarshalJSON(data []byte) error {
+	points, err := commitmentFromJson(data, Simple)
+	if err != nil {
+		return err
+	}
+	s.points = points
+	return nil
+}
+
+func (p *PedersenCommitment) MarshalCBOR() ([]byte, error) {
+	return p.Serialize()
+}
+
+func (p *PedersenCommitment) UnmarshalCBOR(data []byte) error {
+	points, err := commitmentFromCbor(data, Pedersen)
+	if err != nil {
+		return err
+	}
+	p.points = points
+	return nil
+}
+
+func (p *PedersenCommitment) MarshalJSON() ([]byte, error) {
+	return commitmentToJson(p)
+}
+
+func (p *PedersenCommitment) UnmarshalJSON(data []byte) error {
+	points, err := commitmentFromJson(data, Pedersen)
+	if err != nil {
+		return err
+	}
+	p.points = points
+	return nil
+}
@@ -49,26 +49,14 @@ func (l *LagrangeCoefficients) InterpolatePoint(y []curve.EccPoint) (curve.EccPo
 	if len(y) != len(l.coefficients) {
 		return nil, errors.New("interpolation error")
 	}
-	curveType := l.coefficients[0].CurveType()
-	result := curve.Point.Identity(curveType)
-	for i := 0; i < len(y); i++ {
-		coefficient, sample := l.coefficients[i].Clone(), y[i].Clone()
-		result = result.AddPoints(result, sample.ScalarMul(sample, coefficient))
-	}
-	return result, nil
+	return curve.Point.MultiScalarMul(y, l.coefficients)
 }
 
 func (l *LagrangeCoefficients) InterpolateScalar(y []curve.EccScalar) (curve.EccScalar, error) {
 	if len(y) != len(l.coefficients) {
 		return nil, errors.New("interpolation error")
 	}
-	curveType := l.coefficients[0].CurveType()
-	result := curve.Scalar.Zero(curveType)
-	for i := 0; i < len(y); i++ {
-		coefficient, sample := l.coefficients[i].Clone(), y[i].Clone()
-		result = result.Add(result, sample.Mul(sample, coefficient))
-	}
-	return result, nil
+	return curve.Scalar.InnerProduct(y, l.coefficients)
 }
 func checkForDuplicates(nodeIndex []common.NodeIndex) error {
 	set := make(map[common.NodeIndex]struct{})
@@ -117,8 +105,9 @@ func (l *lagrangeCoefficients) AtValue(value curve.EccScalar, samples []common.N
 		tmp = tmp.Mul(tmp, x.Sub(x, value))
 		numerator[i-1] = numerator[i-1].Mul(numerator[i-1], tmp)
 	}
+	denominators := make([]curve.EccScalar, len(samples), len(samples))
 	for i := 0; i < len(scalars); i++ {
-		lagrange, xi := numerator[i], scalars[i]
+		xi := scalars[i]
 		denom := curve.Scalar.One(curveType)
 		for j := 0; j < len(scalars); j++ {
 			xj := scalars[j].Clone()
@@ -127,14 +116,22 @@ func (l *lagrangeCoefficients) AtValue(value curve.EccScalar, samples []common.N
 				denom = denom.Mul(denom, diff)
 			}
 		}
-
-		inv := denom.Invert(denom)
+		denominators[i] = denom
+	}
+	inverses, err := curve.Scalar.BatchInvert(denominators)
+	if err != nil {
+		return nil, err
+	}
+	for _, inv := range inverses {
 		if inv.IsZero() == 1 {
 			return nil, errors.New("interpolation error")
 		}
-		lagrange = lagrange.Mul(lagrange, inv)
+	}
+	coefficients, err := curve.Scalar.ElementwiseMul(numerator, inverses)
+	if err != nil {
+		return nil, err
 	}
 	return &LagrangeCoefficients{
-		coefficients: numerator,
+		coefficients: coefficients,
 	}, nil
 }
@@ -49,7 +49,7 @@ func (polynomial) RandomWithConstant(constant curve.EccScalar, num int, rng rand
 	}
 	curveType := constant.CurveType()
 	coefficients := make([]curve.EccScalar, num, num)
-	coefficients[0] = constant
+	coefficients[0] = constant.Clone()
 	for i := range coefficients {
 		if i != 0 {
 			coefficients[i] = curve.Scalar.Random(curveType, rng)
@@ -61,6 +61,11 @@ func (polynomial) RandomWithConstant(constant curve.EccScalar, num int, rng rand
 	}, nil
 }
 
+// Zeroize wipes every coefficient of the polynomial.
+func (p *Polynomial) Zeroize() {
+	curve.Scalar.Zeroize(p.coefficients...)
+}
+
 func (p Polynomial) CurveType() curve.EccCurveType {
 	return p.curve
 }
@@ -9,7 +9,7 @@ import (
 )
 
 func all() []curve.EccCurveType {
-	return []curve.EccCurveType{curve.K256}
+	return []curve.EccCurveType{curve.K256, curve.P256}
 }
 
 func newArray(a curve.EccScalar, size int) []curve.EccScalar {
@@ -187,3 +187,21 @@ func TestPolyThresholdSecretSharing(t *testing.T) {
 		}
 	}
 }
+
+func TestPolynomialZeroizeKeepsConstant(t *testing.T) {
+	key, _ := crand.Prime(crand.Reader, 256)
+	rng := rand.NewChaCha20(key.Bytes())
+	for _, curveType := range all() {
+		constant := curve.Scalar.Random(curveType, rng)
+		p, err := Poly.RandomWithConstant(constant, 5, rng)
+		assert.Nil(t, err)
+		p.Zeroize()
+		assert.Equal(t, 1, p.IsZero())
+		assert.Equal(t, 0, constant.IsZero())
+
+		opening := PedersenCommitmentOpening{constant.Clone(), constant.Clone()}
+		opening.Zeroize()
+		assert.Equal(t, 1, opening[0].IsZero())
+		assert.Equal(t, 1, opening[1].IsZero())
+	}
+}
@@ -0,0 +1,467 @@
+package presig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/PlatONnetwork/tecdsa/sign"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrConsumed     = errors.New("quadruple already consumed")
+	ErrNotAvailable = errors.New("quadruple not available")
+	ErrClosed       = errors.New("pool closed")
+)
+
+// Quadruple is the local node's view of the transcripts one signature
+// consumes, built with Round.Random, ReshareOfMasked and Multiply.
+type Quadruple struct {
+	Presignature *sign.Presignature
+	Openings     *sign.PresignatureOpenings
+}
+
+// ID identifies a quadruple by the digest of its kappa transcript, so a
+// kappa is recognised wherever its quadruple comes from.
+func (q *Quadruple) ID() (string, error) {
+	if q == nil || q.Presignature == nil || q.Presignature.Kappa == nil {
+		return "", errors.New("missing kappa transcript")
+	}
+	data, err := q.Presignature.Kappa.Serialize()
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+func (q *Quadruple) verify() error {
+	p := q.Presignature
+	if p.Lambda == nil || p.KeyTimesLambda == nil || p.KappaTimesLambda == nil {
+		return errors.New("missing transcript")
+	}
+	o := q.Openings
+	if o == nil || o.Lambda == nil || o.KeyTimesLambda == nil || o.KappaTimesLambda == nil {
+		return errors.New("missing presignature openings")
+	}
+	return nil
+}
+
+// zeroize wipes the openings of q, including those of a quadruple that
+// failed verification.
+func (q *Quadruple) zeroize() {
+	if q == nil || q.Openings == nil {
+		return
+	}
+	o := q.Openings
+	for _, opening := range []poly.CommitmentOpening{o.Lambda, o.KeyTimesLambda, o.KappaTimesLambda} {
+		if opening != nil {
+			opening.Zeroize()
+		}
+	}
+}
+
+// Generator runs the protocol creating one quadruple with the other nodes.
+type Generator func(ctx context.Context) (*Quadruple, error)
+
+type Config struct {
+	// TargetDepth is the number of unreserved quadruples the pool keeps.
+	TargetDepth int
+	// Workers is the number of quadruples generated at once, one by default.
+	Workers  int
+	Generate Generator
+	// Store records the consumed quadruples. A pool whose quadruples
+	// outlive the process needs a durable one such as FileStore.
+	Store Store
+	// RetryDelay is the pause after a failed generation.
+	RetryDelay time.Duration
+}
+
+// Metrics describes the state of a Pool.
+type Metrics struct {
+	// Depth is the number of quadruples ready to be reserved.
+	Depth       int
+	TargetDepth int
+	Generating  int
+	Reserved    int
+	// OldestAge is the time since the oldest ready quadruple was added.
+	OldestAge time.Duration
+	Generated uint64
+	Consumed  uint64
+	Failures  uint64
+}
+
+type entry struct {
+	id      string
+	q       *Quadruple
+	created time.Time
+}
+
+// Pool keeps quadruples generated ahead of the signing requests. Each
+// request reserves exactly one quadruple, and a quadruple is recorded as
+// consumed in the Store before it is handed out, so a kappa is never used
+// twice, whatever happens to the process afterwards.
+type Pool struct {
+	config Config
+
+	mu        sync.Mutex
+	available []*entry
+	reserved  map[string]*entry
+	// pending holds the quadruples taken by a request while the Store
+	// records their consumption
+	pending map[string]*entry
+	// generating counts the quadruples being generated
+	generating int
+	generated  uint64
+	consumed   uint64
+	failures   uint64
+	// changed is closed and replaced whenever the pool changes
+	changed chan struct{}
+	closed  bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewPool(config Config) (*Pool, error) {
+	if config.TargetDepth < 0 {
+		return nil, errors.New("invalid target depth")
+	}
+	if config.Workers == 0 {
+		config.Workers = 1
+	}
+	if config.Workers < 0 {
+		return nil, errors.New("invalid number of workers")
+	}
+	if config.Store == nil {
+		return nil, errors.New("missing store")
+	}
+	return &Pool{
+		config:   config,
+		reserved: make(map[string]*entry),
+		pending:  make(map[string]*entry),
+		changed:  make(chan struct{}),
+	}, nil
+}
+
+// Start generates quadruples in the background until ctx is done or the
+// pool is closed.
+func (p *Pool) Start(ctx context.Context) error {
+	if p.config.Generate == nil {
+		return errors.New("missing generator")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrClosed
+	}
+	if p.cancel != nil {
+		return errors.New("pool already started")
+	}
+	ctx, p.cancel = context.WithCancel(ctx)
+	for i := 0; i < p.config.Workers; i++ {
+		p.wg.Add(1)
+		go p.work(ctx)
+	}
+	return nil
+}
+
+// Close stops the generation and waits for it. Pending Reserve calls
+// return ErrClosed and the unreserved quadruples are wiped.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	if p.cancel != nil {
+		p.cancel()
+	}
+	for _, e := range p.available {
+		e.q.zeroize()
+	}
+	p.available = nil
+	p.notify()
+	p.mu.Unlock()
+	p.wg.Wait()
+}
+
+func (p *Pool) work(ctx context.Context) {
+	defer p.wg.Done()
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return
+		}
+		if len(p.available)+p.generating >= p.config.TargetDepth {
+			changed := p.changed
+			p.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return
+			case <-changed:
+			}
+			continue
+		}
+		p.generating++
+		p.mu.Unlock()
+
+		q, err := p.config.Generate(ctx)
+		if err != nil {
+			p.mu.Lock()
+			p.generating--
+			p.failures++
+			p.notify()
+			p.mu.Unlock()
+		} else {
+			err = p.add(q, true)
+		}
+		if err != nil && ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.config.RetryDelay):
+			}
+		}
+	}
+}
+
+// Add adds a quadruple generated outside of the pool. Quadruples the Store
+// knows as consumed are refused with ErrConsumed. A refused quadruple is
+// wiped, unless the pool already holds it.
+func (p *Pool) Add(q *Quadruple) error {
+	return p.add(q, false)
+}
+
+func (p *Pool) add(q *Quadruple, generated bool) error {
+	id, err := q.ID()
+	if err == nil {
+		err = q.verify()
+	}
+	var consumed bool
+	if err == nil {
+		_, consumed, err = p.config.Store.Consumer(id)
+	}
+	if err == nil && consumed {
+		err = errors.Wrapf(ErrConsumed, "quadruple %s", id)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.notify()
+	if generated {
+		p.generating--
+	}
+	if err == nil && p.closed {
+		err = ErrClosed
+	}
+	if err == nil && p.known(id) {
+		err = errors.Errorf("quadruple %s already in the pool", id)
+	}
+	if err != nil {
+		if generated {
+			p.failures++
+		}
+		if !p.holds(q) {
+			q.zeroize()
+		}
+		return err
+	}
+	p.available = append(p.available, &entry{id: id, q: q, created: time.Now()})
+	p.generated++
+	return nil
+}
+
+func (p *Pool) known(id string) bool {
+	return p.find(func(e *entry) bool { return e.id == id })
+}
+
+// holds reports whether q itself is in the pool, so that refusing it again
+// does not wipe it.
+func (p *Pool) holds(q *Quadruple) bool {
+	return p.find(func(e *entry) bool { return e.q == q })
+}
+
+func (p *Pool) find(match func(e *entry) bool) bool {
+	for _, e := range p.available {
+		if match(e) {
+			return true
+		}
+	}
+	for _, entries := range []map[string]*entry{p.reserved, p.pending} {
+		for _, e := range entries {
+			if match(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Reserve returns the quadruple of request, taking the oldest one ready
+// and waiting for one if the pool is empty. Calling it again for the same
+// request returns the same quadruple until Release.
+func (p *Pool) Reserve(ctx context.Context, request string) (*Quadruple, error) {
+	for {
+		p.mu.Lock()
+		if q, ok, err := p.reservation(ctx, request); ok || err != nil {
+			p.mu.Unlock()
+			return q, err
+		}
+		if len(p.available) > 0 {
+			e := p.take(0, request)
+			p.mu.Unlock()
+			q, err := p.consume(e, request)
+			if errors.Is(err, ErrConsumed) {
+				continue
+			}
+			return q, err
+		}
+		changed := p.changed
+		p.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// ReserveID reserves the quadruple id for request, for nodes that agree on
+// the quadruple of each request rather than taking their oldest one. It
+// fails with ErrNotAvailable if the pool does not hold the quadruple.
+func (p *Pool) ReserveID(request, id string) (*Quadruple, error) {
+	p.mu.Lock()
+	q, ok, err := p.reservation(context.Background(), request)
+	if err != nil {
+		p.mu.Unlock()
+		return nil, err
+	}
+	if ok {
+		other := p.reserved[request].id != id
+		p.mu.Unlock()
+		if other {
+			return nil, errors.Errorf("request %s reserved another quadruple", request)
+		}
+		return q, nil
+	}
+	for i, e := range p.available {
+		if e.id == id {
+			e = p.take(i, request)
+			p.mu.Unlock()
+			return p.consume(e, request)
+		}
+	}
+	p.mu.Unlock()
+	return nil, errors.Wrapf(ErrNotAvailable, "quadruple %s", id)
+}
+
+// reservation returns the quadruple request already reserved. It fails if
+// the request consumed a quadruple the pool no longer holds, typically
+// before a restart. It is called with the lock held. While another call
+// is reserving for the request it waits for that call's outcome, since the
+// Store may already hold the record the other call is writing.
+func (p *Pool) reservation(ctx context.Context, request string) (*Quadruple, bool, error) {
+	for {
+		if p.closed {
+			return nil, false, ErrClosed
+		}
+		if _, busy := p.pending[request]; !busy {
+			break
+		}
+		changed := p.changed
+		p.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			p.mu.Lock()
+			return nil, false, ctx.Err()
+		case <-changed:
+		}
+		p.mu.Lock()
+	}
+	if e, ok := p.reserved[request]; ok {
+		return e.q, true, nil
+	}
+	id, ok, err := p.config.Store.Consumed(request)
+	if err != nil {
+		return nil, false, err
+	}
+	if ok {
+		return nil, false, errors.Wrapf(ErrConsumed, "request %s consumed quadruple %s", request, id)
+	}
+	return nil, false, nil
+}
+
+// take moves available[i] to the pending reservations of request. It is
+// called with the lock held.
+func (p *Pool) take(i int, request string) *entry {
+	e := p.available[i]
+	p.available = append(p.available[:i], p.available[i+1:]...)
+	p.pending[request] = e
+	p.notify()
+	return e
+}
+
+// consume records the quadruple taken by request as consumed and reserves
+// it. The Store is written without the lock, so the pool stays usable
+// while the record reaches the disk. The quadruple leaves the pool even if
+// the Store fails, since the failure may come after the record did.
+func (p *Pool) consume(e *entry, request string) (*Quadruple, error) {
+	err := p.config.Store.Consume(e.id, request)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.notify()
+	delete(p.pending, request)
+	if err == nil && p.closed {
+		err = ErrClosed
+	}
+	if err != nil {
+		e.q.zeroize()
+		return nil, err
+	}
+	p.reserved[request] = e
+	p.consumed++
+	return e.q, nil
+}
+
+// Release forgets the quadruple of request once its signature is done or
+// abandoned, wiping its openings. The quadruple stays consumed.
+func (p *Pool) Release(request string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e, ok := p.reserved[request]; ok {
+		e.q.zeroize()
+		delete(p.reserved, request)
+	}
+}
+
+func (p *Pool) Metrics() Metrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m := Metrics{
+		Depth:       len(p.available),
+		TargetDepth: p.config.TargetDepth,
+		Generating:  p.generating,
+		Reserved:    len(p.reserved),
+		Generated:   p.generated,
+		Consumed:    p.consumed,
+		Failures:    p.failures,
+	}
+	if len(p.available) > 0 {
+		m.OldestAge = time.Since(p.available[0].created)
+	}
+	return m
+}
+
+// notify wakes everything waiting for a change of the pool. It is called
+// with the lock held.
+func (p *Pool) notify() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
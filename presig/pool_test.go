@@ -0,0 +1,348 @@
+package presig
+
+import (
+	"context"
+	"fmt"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/PlatONnetwork/tecdsa/sign"
+	"github.com/PlatONnetwork/tecdsa/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newGenerator returns a generator of quadruples whose transcripts are all
+// the same fresh random transcript: the pool only looks at kappa.
+func newGenerator(t *testing.T) Generator {
+	setup := testutils.NewProtocolSetup(curve.K256, 3, 2, testutils.RandomSeed())
+	var mu sync.Mutex
+	return func(ctx context.Context) (*Quadruple, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		round, err := testutils.Round.Random(setup, 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		return &Quadruple{
+			Presignature: &sign.Presignature{
+				Kappa:            round.Transcript,
+				Lambda:           round.Transcript,
+				KeyTimesLambda:   round.Transcript,
+				KappaTimesLambda: round.Transcript,
+			},
+			Openings: &sign.PresignatureOpenings{
+				Lambda:           round.Openings[0],
+				KeyTimesLambda:   round.Openings[0],
+				KappaTimesLambda: round.Openings[0],
+			},
+		}, nil
+	}
+}
+
+func quadrupleID(t *testing.T, q *Quadruple) string {
+	id, err := q.ID()
+	require.NoError(t, err)
+	return id
+}
+
+func TestPoolKeepsTargetDepthAndReservesOneQuadruplePerRequest(t *testing.T) {
+	pool, err := NewPool(Config{TargetDepth: 3, Workers: 2, Generate: newGenerator(t), Store: NewMemoryStore()})
+	require.NoError(t, err)
+	defer pool.Close()
+	require.NoError(t, pool.Start(context.Background()))
+	assert.Error(t, pool.Start(context.Background()))
+
+	assert.Eventually(t, func() bool { return pool.Metrics().Depth == 3 }, 10*time.Second, time.Millisecond)
+	m := pool.Metrics()
+	assert.Equal(t, 3, m.TargetDepth)
+	assert.Equal(t, uint64(3), m.Generated)
+	assert.Positive(t, m.OldestAge)
+
+	a, err := pool.Reserve(context.Background(), "a")
+	require.NoError(t, err)
+	again, err := pool.Reserve(context.Background(), "a")
+	require.NoError(t, err)
+	assert.Same(t, a, again)
+	b, err := pool.Reserve(context.Background(), "b")
+	require.NoError(t, err)
+	assert.NotEqual(t, quadrupleID(t, a), quadrupleID(t, b))
+
+	// the reserved quadruples are replaced
+	assert.Eventually(t, func() bool { return pool.Metrics().Depth == 3 }, 10*time.Second, time.Millisecond)
+	m = pool.Metrics()
+	assert.Equal(t, 2, m.Reserved)
+	assert.Equal(t, uint64(2), m.Consumed)
+	assert.Equal(t, uint64(5), m.Generated)
+
+	// concurrent requests never share a quadruple
+	var wg sync.WaitGroup
+	ids := make([]string, 10)
+	for i := range ids {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			q, err := pool.Reserve(context.Background(), fmt.Sprintf("request-%d", i))
+			if assert.NoError(t, err) {
+				ids[i] = quadrupleID(t, q)
+			}
+		}(i)
+	}
+	wg.Wait()
+	seen := map[string]bool{quadrupleID(t, a): true, quadrupleID(t, b): true}
+	for _, id := range ids {
+		assert.False(t, seen[id])
+		seen[id] = true
+	}
+
+	pool.Release("a")
+	assert.Equal(t, 11, pool.Metrics().Reserved)
+	_, err = pool.Reserve(context.Background(), "a")
+	assert.ErrorIs(t, err, ErrConsumed)
+}
+
+func TestPoolNeverReusesAConsumedKappa(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "consumed")
+	generate := newGenerator(t)
+	q, err := generate(context.Background())
+	require.NoError(t, err)
+	other, err := generate(context.Background())
+	require.NoError(t, err)
+
+	store, err := OpenFileStore(path)
+	require.NoError(t, err)
+	pool, err := NewPool(Config{Store: store})
+	require.NoError(t, err)
+	require.NoError(t, pool.Add(q))
+	assert.Error(t, pool.Add(q))
+	require.NoError(t, pool.Add(other))
+	reserved, err := pool.ReserveID("r1", quadrupleID(t, q))
+	require.NoError(t, err)
+	assert.Same(t, q, reserved)
+	_, err = pool.ReserveID("r1", quadrupleID(t, other))
+	assert.Error(t, err)
+	pool.Close()
+	require.NoError(t, store.Close())
+
+	// after a restart the quadruple is still consumed, by the same request
+	store, err = OpenFileStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	pool, err = NewPool(Config{Store: store})
+	require.NoError(t, err)
+	defer pool.Close()
+	q, err = generate(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, pool.Add(q))
+	assert.ErrorIs(t, pool.Add(reserved), ErrConsumed)
+	_, err = pool.Reserve(context.Background(), "r1")
+	assert.ErrorIs(t, err, ErrConsumed)
+	_, err = pool.ReserveID("r2", quadrupleID(t, reserved))
+	assert.ErrorIs(t, err, ErrNotAvailable)
+	assert.Equal(t, 1, pool.Metrics().Depth)
+}
+
+func TestPoolReserveWaitsForAQuadruple(t *testing.T) {
+	generate := newGenerator(t)
+	pool, err := NewPool(Config{Store: NewMemoryStore()})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pool.Reserve(ctx, "a")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	reserved := make(chan *Quadruple)
+	go func() {
+		q, err := pool.Reserve(context.Background(), "a")
+		assert.NoError(t, err)
+		reserved <- q
+	}()
+	q, err := generate(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, pool.Add(q))
+	assert.Same(t, q, <-reserved)
+
+	closed := make(chan error)
+	go func() {
+		_, err := pool.Reserve(context.Background(), "b")
+		closed <- err
+	}()
+	pool.Close()
+	assert.ErrorIs(t, <-closed, ErrClosed)
+	q, err = generate(context.Background())
+	require.NoError(t, err)
+	assert.ErrorIs(t, pool.Add(q), ErrClosed)
+}
+
+func TestPoolRetriesFailedGenerations(t *testing.T) {
+	generate := newGenerator(t)
+	var calls int32
+	pool, err := NewPool(Config{
+		TargetDepth: 2,
+		Generate: func(ctx context.Context) (*Quadruple, error) {
+			if atomic.AddInt32(&calls, 1) <= 2 {
+				return nil, fmt.Errorf("generation failed")
+			}
+			return generate(ctx)
+		},
+		Store:      NewMemoryStore(),
+		RetryDelay: time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer pool.Close()
+	require.NoError(t, pool.Start(context.Background()))
+
+	assert.Eventually(t, func() bool { return pool.Metrics().Depth == 2 }, 10*time.Second, time.Millisecond)
+	m := pool.Metrics()
+	assert.Equal(t, uint64(2), m.Failures)
+	assert.Equal(t, uint64(2), m.Generated)
+	assert.Equal(t, 0, m.Generating)
+}
+
+// slowStore holds every Consume until release is closed, before or, with
+// written, after recording the consumption.
+type slowStore struct {
+	*MemoryStore
+	written   bool
+	consuming chan struct{}
+	release   chan struct{}
+}
+
+func (s *slowStore) Consume(quadruple, request string) error {
+	if s.written {
+		if err := s.MemoryStore.Consume(quadruple, request); err != nil {
+			return err
+		}
+	}
+	s.consuming <- struct{}{}
+	<-s.release
+	if s.written {
+		return nil
+	}
+	return s.MemoryStore.Consume(quadruple, request)
+}
+
+func TestPoolWritesTheStoreWithoutBlockingIt(t *testing.T) {
+	generate := newGenerator(t)
+	store := &slowStore{MemoryStore: NewMemoryStore(), consuming: make(chan struct{}), release: make(chan struct{})}
+	pool, err := NewPool(Config{Store: store})
+	require.NoError(t, err)
+	defer pool.Close()
+	q, err := generate(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, pool.Add(q))
+
+	reserved := make(chan *Quadruple, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			q, err := pool.Reserve(context.Background(), "a")
+			assert.NoError(t, err)
+			reserved <- q
+		}()
+	}
+	<-store.consuming
+	// while the record is written the pool answers, and the quadruple being
+	// consumed is neither available nor addable again
+	m := pool.Metrics()
+	assert.Equal(t, 0, m.Depth)
+	assert.Equal(t, 0, m.Reserved)
+	assert.Error(t, pool.Add(q))
+	other, err := generate(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, pool.Add(other))
+	assert.Equal(t, 1, pool.Metrics().Depth)
+
+	close(store.release)
+	assert.Same(t, q, <-reserved)
+	assert.Same(t, q, <-reserved)
+	m = pool.Metrics()
+	assert.Equal(t, 1, m.Depth)
+	assert.Equal(t, 1, m.Reserved)
+	assert.Equal(t, uint64(1), m.Consumed)
+}
+
+func TestPoolWipesRefusedQuadruples(t *testing.T) {
+	generate := newGenerator(t)
+	wiped := func(q *Quadruple) bool {
+		for _, s := range q.Openings.Lambda.(poly.PedersenCommitmentOpening) {
+			if s.IsZero() != 1 {
+				return false
+			}
+		}
+		return true
+	}
+	store := NewMemoryStore()
+	pool, err := NewPool(Config{Store: store})
+	require.NoError(t, err)
+
+	q, err := generate(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, pool.Add(q))
+	// a copy of a quadruple of the pool is wiped, the pooled one is not
+	clone := func(o poly.CommitmentOpening) poly.CommitmentOpening {
+		p := o.(poly.PedersenCommitmentOpening)
+		return poly.PedersenCommitmentOpening{p[0].Clone(), p[1].Clone()}
+	}
+	duplicate := *q
+	duplicate.Openings = &sign.PresignatureOpenings{
+		Lambda:           clone(q.Openings.Lambda),
+		KeyTimesLambda:   clone(q.Openings.KeyTimesLambda),
+		KappaTimesLambda: clone(q.Openings.KappaTimesLambda),
+	}
+	assert.Error(t, pool.Add(&duplicate))
+	assert.True(t, wiped(&duplicate))
+	assert.Error(t, pool.Add(q))
+	assert.False(t, wiped(q))
+
+	consumed, err := generate(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, store.Consume(quadrupleID(t, consumed), "r"))
+	assert.ErrorIs(t, pool.Add(consumed), ErrConsumed)
+	assert.True(t, wiped(consumed))
+
+	pool.Close()
+	late, err := generate(context.Background())
+	require.NoError(t, err)
+	assert.ErrorIs(t, pool.Add(late), ErrClosed)
+	assert.True(t, wiped(late))
+	assert.True(t, wiped(q))
+}
+
+func TestPoolReturnsTheSameQuadrupleToConcurrentCallsOfARequest(t *testing.T) {
+	generate := newGenerator(t)
+	// the record is in the Store while the first call still waits for it
+	store := &slowStore{MemoryStore: NewMemoryStore(), written: true, consuming: make(chan struct{}), release: make(chan struct{})}
+	pool, err := NewPool(Config{Store: store})
+	require.NoError(t, err)
+	defer pool.Close()
+	q, err := generate(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, pool.Add(q))
+
+	reserved := make(chan *Quadruple, 3)
+	reserve := func(reserve func() (*Quadruple, error)) {
+		q, err := reserve()
+		assert.NoError(t, err)
+		reserved <- q
+	}
+	go reserve(func() (*Quadruple, error) { return pool.Reserve(context.Background(), "a") })
+	<-store.consuming
+	go reserve(func() (*Quadruple, error) { return pool.Reserve(context.Background(), "a") })
+	go reserve(func() (*Quadruple, error) { return pool.ReserveID("a", quadrupleID(t, q)) })
+	// the later calls wait for the first one rather than fail
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pool.Reserve(ctx, "a")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(store.release)
+	for i := 0; i < 3; i++ {
+		assert.Same(t, q, <-reserved)
+	}
+	assert.Equal(t, uint64(1), pool.Metrics().Consumed)
+}
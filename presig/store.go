@@ -0,0 +1,223 @@
+package presig
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// Store durably records which signing request consumed which quadruple.
+// A quadruple recorded here is never handed out again, even after a
+// restart, since signing twice with the same kappa leaks the key.
+type Store interface {
+	// Consume records that quadruple is used by request. It must be durable
+	// when it returns, and fail with ErrConsumed if the quadruple was
+	// consumed before.
+	Consume(quadruple, request string) error
+	// Consumer returns the request that consumed quadruple, if any.
+	Consumer(quadruple string) (string, bool, error)
+	// Consumed returns the quadruple consumed by request, if any.
+	Consumed(request string) (string, bool, error)
+}
+
+type consumption struct {
+	Quadruple string
+	Request   string
+}
+
+type consumptions struct {
+	byQuadruple map[string]string
+	byRequest   map[string]string
+}
+
+func newConsumptions() consumptions {
+	return consumptions{byQuadruple: make(map[string]string), byRequest: make(map[string]string)}
+}
+
+func (c consumptions) check(quadruple, request string) error {
+	if _, ok := c.byQuadruple[quadruple]; ok {
+		return errors.Wrapf(ErrConsumed, "quadruple %s", quadruple)
+	}
+	if _, ok := c.byRequest[request]; ok {
+		return errors.Errorf("request %s already consumed a quadruple", request)
+	}
+	return nil
+}
+
+func (c consumptions) add(quadruple, request string) {
+	c.byQuadruple[quadruple] = request
+	c.byRequest[request] = quadruple
+}
+
+// MemoryStore keeps the consumptions in memory, for tests and for nodes
+// whose pool does not outlive the process.
+type MemoryStore struct {
+	mu sync.Mutex
+	c  consumptions
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{c: newConsumptions()}
+}
+
+func (s *MemoryStore) Consume(quadruple, request string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.c.check(quadruple, request); err != nil {
+		return err
+	}
+	s.c.add(quadruple, request)
+	return nil
+}
+
+func (s *MemoryStore) Consumer(quadruple string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	request, ok := s.c.byQuadruple[quadruple]
+	return request, ok, nil
+}
+
+func (s *MemoryStore) Consumed(request string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	quadruple, ok := s.c.byRequest[request]
+	return quadruple, ok, nil
+}
+
+// FileStore appends every consumption as a JSON line to a file and syncs
+// it before Consume returns. A record whose write or sync fails is cut off
+// the file again. A torn last line, left by a crash during a write, is
+// discarded when the file is opened again: its Consume never returned, so
+// its quadruple was never handed out.
+type FileStore struct {
+	mu   sync.Mutex
+	file storeFile
+	// broken is set when a failed record could not be cut off; the file
+	// then takes no more records
+	broken error
+	c      consumptions
+}
+
+func OpenFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileStore{file: file, c: newConsumptions()}
+	if err := s.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileStore) load() error {
+	data, err := io.ReadAll(s.file)
+	if err != nil {
+		return err
+	}
+	// offset is the end of the valid records; only the last line may be
+	// torn
+	offset := 0
+	for line := 1; offset < len(data); line++ {
+		end := len(data)
+		if i := bytes.IndexByte(data[offset:], '\n'); i >= 0 {
+			end = offset + i + 1
+		}
+		var c consumption
+		valid := data[end-1] == '\n' && json.Unmarshal(data[offset:end-1], &c) == nil && c.Quadruple != ""
+		if !valid && end == len(data) {
+			break
+		}
+		if !valid {
+			return errors.Errorf("invalid consumption record on line %d", line)
+		}
+		s.c.add(c.Quadruple, c.Request)
+		offset = end
+	}
+	if offset == len(data) {
+		return nil
+	}
+	return s.cut(int64(offset))
+}
+
+// storeFile is the part of *os.File a FileStore uses.
+type storeFile interface {
+	io.ReadWriteSeeker
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+// cut truncates the file to offset and appends from there.
+func (s *FileStore) cut(offset int64) error {
+	if err := s.file.Truncate(offset); err != nil {
+		return err
+	}
+	_, err := s.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (s *FileStore) Consume(quadruple, request string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return ErrClosed
+	}
+	if s.broken != nil {
+		return s.broken
+	}
+	if err := s.c.check(quadruple, request); err != nil {
+		return err
+	}
+	record, err := json.Marshal(&consumption{Quadruple: quadruple, Request: request})
+	if err != nil {
+		return err
+	}
+	offset, err := s.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	// the consumption counts as soon as it may have reached the file
+	s.c.add(quadruple, request)
+	_, err = s.file.Write(append(record, '\n'))
+	if err == nil {
+		err = s.file.Sync()
+	}
+	if err != nil {
+		// a torn record would corrupt the next one appended
+		if cerr := s.cut(offset); cerr != nil {
+			s.broken = errors.Wrap(cerr, "failed to cut off a failed consumption record")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) Consumer(quadruple string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	request, ok := s.c.byQuadruple[quadruple]
+	return request, ok, nil
+}
+
+func (s *FileStore) Consumed(request string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	quadruple, ok := s.c.byRequest[request]
+	return quadruple, ok, nil
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,132 @@
+package presig
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoresRefuseASecondConsumption(t *testing.T) {
+	file, err := OpenFileStore(filepath.Join(t.TempDir(), "consumed"))
+	require.NoError(t, err)
+	defer file.Close()
+	for _, store := range []Store{NewMemoryStore(), file} {
+		require.NoError(t, store.Consume("q1", "r1"))
+		assert.ErrorIs(t, store.Consume("q1", "r2"), ErrConsumed)
+		assert.Error(t, store.Consume("q2", "r1"))
+		require.NoError(t, store.Consume("q2", "r2"))
+
+		request, ok, err := store.Consumer("q1")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "r1", request)
+		quadruple, ok, err := store.Consumed("r2")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "q2", quadruple)
+		_, ok, err = store.Consumer("q3")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	}
+}
+
+func TestFileStoreSurvivesATornRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "consumed")
+	store, err := OpenFileStore(path)
+	require.NoError(t, err)
+	require.NoError(t, store.Consume("q1", "r1"))
+	require.NoError(t, store.Close())
+	assert.ErrorIs(t, store.Consume("q2", "r2"), ErrClosed)
+
+	// a crash in the middle of the second record
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"Quadruple":"q2","Req`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	store, err = OpenFileStore(path)
+	require.NoError(t, err)
+	_, ok, err := store.Consumer("q1")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	_, ok, err = store.Consumer("q2")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	require.NoError(t, store.Consume("q2", "r2"))
+	require.NoError(t, store.Close())
+
+	store, err = OpenFileStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	_, ok, err = store.Consumer("q2")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// only the last line may be torn, even if it ends with a newline
+	require.NoError(t, os.WriteFile(path, []byte("garbage\n{\"Quadruple\":\"q1\",\"Request\":\"r1\"}\n"), 0600))
+	_, err = OpenFileStore(path)
+	assert.Error(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("{\"Quadruple\":\"q1\",\"Request\":\"r1\"}\ngarbage\n"), 0600))
+	torn, err := OpenFileStore(path)
+	require.NoError(t, err)
+	require.NoError(t, torn.Close())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"Quadruple\":\"q1\",\"Request\":\"r1\"}\n", string(data))
+}
+
+// failingFile fails the next Sync after fail is set, and every Truncate
+// while stuck is set.
+type failingFile struct {
+	*os.File
+	fail, stuck bool
+}
+
+func (f *failingFile) Truncate(size int64) error {
+	if f.stuck {
+		return errors.New("truncate failed")
+	}
+	return f.File.Truncate(size)
+}
+
+func (f *failingFile) Sync() error {
+	if f.fail {
+		f.fail = false
+		return errors.New("sync failed")
+	}
+	return f.File.Sync()
+}
+
+func TestFileStoreCutsOffFailedRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "consumed")
+	store, err := OpenFileStore(path)
+	require.NoError(t, err)
+	file := &failingFile{File: store.file.(*os.File)}
+	store.file = file
+	require.NoError(t, store.Consume("q1", "r1"))
+	file.fail = true
+	assert.Error(t, store.Consume("q2", "r2"))
+	// the failed consumption still counts in memory
+	assert.ErrorIs(t, store.Consume("q2", "r3"), ErrConsumed)
+	require.NoError(t, store.Consume("q3", "r3"))
+	require.NoError(t, store.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"Quadruple\":\"q1\",\"Request\":\"r1\"}\n{\"Quadruple\":\"q3\",\"Request\":\"r3\"}\n", string(data))
+
+	// a record that cannot be cut off stops the store
+	store, err = OpenFileStore(path)
+	require.NoError(t, err)
+	defer store.Close()
+	store.file = &failingFile{File: store.file.(*os.File), fail: true, stuck: true}
+	assert.Error(t, store.Consume("q4", "r4"))
+	assert.Error(t, store.Consume("q5", "r5"))
+	_, ok, err := store.Consumer("q5")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
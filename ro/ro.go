@@ -62,8 +62,12 @@ func (r *RandomOracle) AddPoint(name string, pt curve.EccPoint) error {
 }
 
 func (r *RandomOracle) AddPoints(name string, pts []curve.EccPoint) error {
+	encoded := curve.Point.BatchSerialize(pts)
 	for i, pt := range pts {
-		if err := r.AddPoint(fmt.Sprintf("%s[%d]", name, i), pt); err != nil {
+		input := make([]byte, 0, 1+len(encoded[i]))
+		input = append(input, pt.CurveType().Tag())
+		input = append(input, encoded[i]...)
+		if err := r.AddInput(fmt.Sprintf("%s[%d]", name, i), input, Point); err != nil {
 			return err
 		}
 	}
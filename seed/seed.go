@@ -45,3 +45,10 @@ func (s Seed) Derive(domainSeparator string) *Seed {
 func (s Seed) Rng() rand.Rand {
 	return rand.NewChaCha20(s.value[:])
 }
+
+// Zeroize wipes the seed value. Seeds derived from it are not affected.
+func (s *Seed) Zeroize() {
+	for i := range s.value {
+		s.value[i] = 0
+	}
+}
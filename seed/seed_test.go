@@ -25,3 +25,9 @@ func TestSeedOutput(t *testing.T) {
 	testSeedOutput(t, FromRng(rng), "2e7af894bb91c48e2b72be9627dbc960d7800ef7569c8f6f0f3d9873c7337c9a")
 	testSeedOutput(t, FromRng(rng), "2bb9a6469fff531083abd8f85c3d7ffa78090f725546a9633a35c0c4582c9b5c")
 }
+
+func TestSeedZeroize(t *testing.T) {
+	s := FromBytes([]byte("secret"))
+	s.Zeroize()
+	assert.Equal(t, [seedLen]byte{}, s.value)
+}
@@ -2,24 +2,36 @@ package seed
 
 import (
 	"crypto/sha256"
+	"crypto/sha512"
 	"github.com/pkg/errors"
+	"hash"
 )
 
-const (
-	maxLen = 255 * 32
-)
-
+// ExpandMessageXmd is expand_message_xmd of RFC 9380 section 5.3.1
+// instantiated with SHA-256.
 func ExpandMessageXmd(msg []byte, domainSeparator []byte, length int) ([]byte, error) {
+	return expandMessageXmd(sha256.New, msg, domainSeparator, length)
+}
+
+// ExpandMessageXmdSha512 is expand_message_xmd instantiated with SHA-512.
+func ExpandMessageXmdSha512(msg []byte, domainSeparator []byte, length int) ([]byte, error) {
+	return expandMessageXmd(sha512.New, msg, domainSeparator, length)
+}
+
+func expandMessageXmd(newHash func() hash.Hash, msg []byte, domainSeparator []byte, length int) ([]byte, error) {
+	state := newHash()
+	outputLen := state.Size()
+	maxLen := 255 * outputLen
 	if length > maxLen {
 		return nil, errors.Errorf("Requested XMD output length %d too large (max: %d)", length, maxLen)
 	}
-	ell := (length-1)/32 + 1
+	ell := (length-1)/outputLen + 1
 
 	xmd := func(dst []byte) []byte {
-		out := make([]byte, 0, ell*32)
-		empty := [64]byte{}
-		state := sha256.New()
-		state.Write(empty[:])
+		out := make([]byte, 0, ell*outputLen)
+		empty := make([]byte, state.BlockSize())
+		state.Reset()
+		state.Write(empty)
 		state.Write(msg)
 		state.Write([]byte{byte(length / 256), byte(length % 256), 0})
 		state.Write(dst)
@@ -33,12 +45,12 @@ func ExpandMessageXmd(msg []byte, domainSeparator []byte, length int) ([]byte, e
 		out = append(out, state.Sum(nil)...)
 
 		for i := 2; i <= ell; i++ {
-			var tmp [32]byte
-			for j := 0; j < 32; j++ {
-				tmp[j] = b0[j] ^ out[len(out)-32+j]
+			tmp := make([]byte, outputLen)
+			for j := 0; j < outputLen; j++ {
+				tmp[j] = b0[j] ^ out[len(out)-outputLen+j]
 			}
 			state.Reset()
-			state.Write(tmp[:])
+			state.Write(tmp)
 			state.Write([]byte{byte(i)})
 			state.Write(dst)
 			state.Write([]byte{byte(len(dst))})
@@ -49,7 +61,7 @@ func ExpandMessageXmd(msg []byte, domainSeparator []byte, length int) ([]byte, e
 	}
 	var out []byte
 	if len(domainSeparator) >= 256 {
-		state := sha256.New()
+		state.Reset()
 		state.Write([]byte("H2C-OVERSIZE-DST-"))
 		state.Write(domainSeparator)
 		out = xmd(state.Sum(nil))
@@ -0,0 +1,45 @@
+package seed
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	maxXofLen    = 65535
+	maxXofDstLen = 255
+)
+
+// ExpandMessageXofShake128 is expand_message_xof of RFC 9380 section 5.3.2
+// instantiated with SHAKE128.
+func ExpandMessageXofShake128(msg []byte, domainSeparator []byte, length int) ([]byte, error) {
+	return expandMessageXof(sha3.NewShake128, 128, msg, domainSeparator, length)
+}
+
+// ExpandMessageXofShake256 is expand_message_xof of RFC 9380 section 5.3.2
+// instantiated with SHAKE256.
+func ExpandMessageXofShake256(msg []byte, domainSeparator []byte, length int) ([]byte, error) {
+	return expandMessageXof(sha3.NewShake256, 256, msg, domainSeparator, length)
+}
+
+func expandMessageXof(newXof func() sha3.ShakeHash, securityLevel int, msg []byte, domainSeparator []byte, length int) ([]byte, error) {
+	if length > maxXofLen {
+		return nil, errors.Errorf("Requested XOF output length %d too large (max: %d)", length, maxXofLen)
+	}
+	dst := domainSeparator
+	if len(dst) > maxXofDstLen {
+		dst = make([]byte, (2*securityLevel+7)/8)
+		state := newXof()
+		state.Write([]byte("H2C-OVERSIZE-DST-"))
+		state.Write(domainSeparator)
+		state.Read(dst)
+	}
+	state := newXof()
+	state.Write(msg)
+	state.Write([]byte{byte(length >> 8), byte(length)})
+	state.Write(dst)
+	state.Write([]byte{byte(len(dst))})
+	out := make([]byte, length)
+	state.Read(out)
+	return out, nil
+}
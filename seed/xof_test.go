@@ -0,0 +1,31 @@
+package seed
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func xofCheck(t *testing.T, expand func([]byte, []byte, int) ([]byte, error), msg, dst, want string) {
+	x, err := expand([]byte(msg), []byte(dst), len(want)/2)
+	assert.Nil(t, err)
+	assert.Equal(t, want, hex.EncodeToString(x))
+}
+
+func TestExpandMessageXof(t *testing.T) {
+	longDst := "QUUX-V01-CS02-with-expander-SHAKE128-long-DST-111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111"
+	xofCheck(t, ExpandMessageXofShake128, "", "QUUX-V01-CS02-with-expander-SHAKE128",
+		"86518c9cd86581486e9485aa74ab35ba150d1c75c88e26b7043e44e2acd735a2")
+	xofCheck(t, ExpandMessageXofShake128, "abc", "QUUX-V01-CS02-with-expander-SHAKE128",
+		"c952f0c8e529ca8824acc6a4cab0e782fc3648c563ddb00da7399f2ae35654f4860ec671db2356ba7baa55a34a9d7f79197b60ddae6e64768a37d699a78323496db3878c8d64d909d0f8a7de4927dcab0d3dbbc26cb20a49eceb0530b431cdf47bc8c0fa3e0d88f53b318b6739fbed7d7634974f1b5c386d6230c76260d5337a")
+	xofCheck(t, ExpandMessageXofShake128, "", longDst,
+		"827c6216330a122352312bccc0c8d6e7a146c5257a776dbd9ad9d75cd880fc53")
+	xofCheck(t, ExpandMessageXofShake128, "abc", longDst,
+		"41b7ffa7a301b5c1441495ebb9774e2a53dbbf4e54b9a1af6a20fd41eafd69ef7b9418599c5545b1ee422f363642b01d4a53449313f68da3e49dddb9cd25b97465170537d45dcbdf92391b5bdff344db4bd06311a05bca7dcd360b6caec849c299133e5c9194f4e15e3e23cfaab4003fab776f6ac0bfae9144c6e2e1c62e7d57")
+	xofCheck(t, ExpandMessageXofShake256, "", "QUUX-V01-CS02-with-expander-SHAKE256",
+		"2ffc05c48ed32b95d72e807f6eab9f7530dd1c2f013914c8fed38c5ccc15ad76")
+	xofCheck(t, ExpandMessageXofShake256, "abc", "QUUX-V01-CS02-with-expander-SHAKE256",
+		"a54303e6b172909783353ab05ef08dd435a558c3197db0c132134649708e0b9b4e34fb99b92a9e9e28fc1f1d8860d85897a8e021e6382f3eea10577f968ff6df6c45fe624ce65ca25932f679a42a404bc3681efe03fcd45ef73bb3a8f79ba784f80f55ea8a3c367408f30381299617f50c8cf8fbb21d0f1e1d70b0131a7b6fbe")
+	_, err := ExpandMessageXofShake128([]byte("abc"), []byte("dst"), 65536)
+	assert.NotNil(t, err)
+}
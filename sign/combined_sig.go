@@ -6,7 +6,6 @@ import (
 	"github.com/PlatONnetwork/tecdsa/dealings"
 	"github.com/PlatONnetwork/tecdsa/key"
 	poly2 "github.com/PlatONnetwork/tecdsa/poly"
-	"github.com/pkg/errors"
 	"github.com/tidwall/btree"
 )
 
@@ -17,7 +16,7 @@ type ThresholdEcdsaCombinedSigInternal struct {
 
 func NewThresholdEcdsaCombinedSigInternal(derivationPath *key.DerivationPath, hashedMsg []byte, randomness []byte, keyTranscript *dealings.IDkgTranscriptInternal, presigTranscript *dealings.IDkgTranscriptInternal, reconstructionThreshold int, sigShares *btree.Map[common.NodeIndex, *ThresholdEcdsaSigShareInternal], curveType curve.EccCurveType) (*ThresholdEcdsaCombinedSigInternal, error) {
 	if sigShares.Len() < reconstructionThreshold {
-		return nil, errors.New("insufficient dealings")
+		return nil, &common.ErrInsufficientShares{Have: sigShares.Len(), Need: reconstructionThreshold}
 	}
 	rho, _, _, _, err := DeriveRho(curveType, hashedMsg, randomness, derivationPath, keyTranscript, presigTranscript)
 	if err != nil {
@@ -31,6 +30,7 @@ func NewThresholdEcdsaCombinedSigInternal(derivationPath *key.DerivationPath, ha
 		if count >= reconstructionThreshold {
 			return true
 		}
+		count++
 		xValues = append(xValues, index)
 		if p, ok := sigShare.sigmaNumerator.(poly2.PedersenCommitmentOpening); ok {
 			numeratorSamples = append(numeratorSamples, p[0])
@@ -53,21 +53,50 @@ func NewThresholdEcdsaCombinedSigInternal(derivationPath *key.DerivationPath, ha
 	if denominator, err = coefficients.InterpolateScalar(denominatorSamples); err != nil {
 		return nil, err
 	}
-	sigma := numerator.Mul(numerator, denominator.Invert(denominator))
-	normSigma := sigma
+	sigma := numerator.Times(denominator.Inverse())
 	if sigma.IsHigh() {
-		normSigma = sigma.Negate(sigma)
+		sigma = sigma.Negated()
 	}
 
 	return &ThresholdEcdsaCombinedSigInternal{
 		R: rho,
-		S: normSigma,
+		S: sigma,
 	}, nil
 }
 
+// NewThresholdEcdsaCombinedSigInternalRobust verifies every share against
+// the transcripts and combines the first reconstructionThreshold valid ones
+// in index order. It returns all the signers whose share does not verify.
+// With too few valid shares it fails with ErrInsufficientShares, still
+// naming the misbehaving signers.
+func NewThresholdEcdsaCombinedSigInternalRobust(derivationPath *key.DerivationPath, hashedMsg []byte, randomness []byte, keyTranscript *dealings.IDkgTranscriptInternal, presigTranscript *dealings.IDkgTranscriptInternal, lambda *dealings.IDkgTranscriptInternal, kappaTimesLambda *dealings.IDkgTranscriptInternal, keyTimesLambda *dealings.IDkgTranscriptInternal, reconstructionThreshold int, sigShares *btree.Map[common.NodeIndex, *ThresholdEcdsaSigShareInternal], curveType curve.EccCurveType) (*ThresholdEcdsaCombinedSigInternal, []common.NodeIndex, error) {
+	// a failure of the inputs shared by every share is not the signer's
+	if _, _, _, _, err := DeriveRho(curveType, hashedMsg, randomness, derivationPath, keyTranscript, presigTranscript); err != nil {
+		return nil, nil, err
+	}
+	var valid btree.Map[common.NodeIndex, *ThresholdEcdsaSigShareInternal]
+	var misbehaving []common.NodeIndex
+	sigShares.Scan(func(index common.NodeIndex, sigShare *ThresholdEcdsaSigShareInternal) bool {
+		if sigShare == nil || sigShare.Verify(derivationPath, hashedMsg, randomness, index, keyTranscript, presigTranscript, lambda, kappaTimesLambda, keyTimesLambda, curveType) != nil {
+			misbehaving = append(misbehaving, index)
+			return true
+		}
+		valid.Set(index, sigShare)
+		return true
+	})
+	if valid.Len() < reconstructionThreshold {
+		return nil, misbehaving, &common.ErrInsufficientShares{Have: valid.Len(), Need: reconstructionThreshold}
+	}
+	combined, err := NewThresholdEcdsaCombinedSigInternal(derivationPath, hashedMsg, randomness, keyTranscript, presigTranscript, reconstructionThreshold, &valid, curveType)
+	if err != nil {
+		return nil, misbehaving, err
+	}
+	return combined, misbehaving, nil
+}
+
 func (t ThresholdEcdsaCombinedSigInternal) Verify(derivationPath *key.DerivationPath, hashedMsg []byte, randomness []byte, keyTranscript *dealings.IDkgTranscriptInternal, presigTranscript *dealings.IDkgTranscriptInternal, curveType curve.EccCurveType) error {
 	if t.R.IsZero() == 1 || t.S.IsZero() == 1 {
-		return errors.New("invalid signature")
+		return common.ErrInvalidSignature
 	}
 	msg, err := ConvertHashToInteger(hashedMsg, curveType)
 	if err != nil {
@@ -78,21 +107,20 @@ func (t ThresholdEcdsaCombinedSigInternal) Verify(derivationPath *key.Derivation
 		return err
 	}
 	if t.R.Equal(rho) == 0 || t.S.IsHigh() {
-		return errors.New("invalid signature")
+		return common.ErrInvalidSignature
 	}
 
 	masterPublickKey := keyTranscript.ConstantTerm()
-	tweakg := curve.Point.MulByG(keyTweak)
-	publicKey := tweakg.AddPoints(tweakg, masterPublickKey)
-	sInv := t.S.Clone().Invert(t.S)
-	u1 := msg.Clone().Mul(msg, sInv)
-	u2 := t.R.Clone().Mul(t.R, sInv)
+	publicKey := curve.Point.MulByG(keyTweak).Plus(masterPublickKey)
+	sInv := t.S.Inverse()
+	u1 := msg.Times(sInv)
+	u2 := t.R.Times(sInv)
 	rp := curve.Point.MulPoints(curve.Point.GeneratorG(curveType), u1, publicKey, u2)
 	if rp.IsInfinity() {
-		return errors.New("invalid signature")
+		return common.ErrInvalidSignature
 	}
 	if rp.AffineX().Equal(preSig.AffineX()) == 0 {
-		return errors.New("invalid signature")
+		return common.ErrInvalidSignature
 	}
 	return nil
 }
@@ -0,0 +1,92 @@
+package sign
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	poly2 "github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/pkg/errors"
+)
+
+var (
+	SigShare = thresholdEcdsaSigShare{}
+)
+
+type thresholdEcdsaSigShare struct{}
+
+// thresholdEcdsaSigShareWire is the versioned encoding of a signature share:
+// the openings of the numerator and the denominator of the signature, each
+// a value and a mask.
+type thresholdEcdsaSigShareWire struct {
+	Version          uint8
+	CurveType        curve.EccCurveType
+	NumeratorValue   []byte
+	NumeratorMask    []byte
+	DenominatorValue []byte
+	DenominatorMask  []byte
+}
+
+func (t ThresholdEcdsaSigShareInternal) wire() (*thresholdEcdsaSigShareWire, error) {
+	numerator, ok := t.sigmaNumerator.(poly2.PedersenCommitmentOpening)
+	if !ok {
+		return nil, errors.Wrap(common.ErrUnexpectedCommitmentType, "signature share numerator")
+	}
+	denominator, ok := t.sigmaDenominator.(poly2.PedersenCommitmentOpening)
+	if !ok {
+		return nil, errors.Wrap(common.ErrUnexpectedCommitmentType, "signature share denominator")
+	}
+	return &thresholdEcdsaSigShareWire{
+		Version:          common.WireVersion,
+		CurveType:        numerator[0].CurveType(),
+		NumeratorValue:   numerator[0].Serialize(),
+		NumeratorMask:    numerator[1].Serialize(),
+		DenominatorValue: denominator[0].Serialize(),
+		DenominatorMask:  denominator[1].Serialize(),
+	}, nil
+}
+
+func (t *ThresholdEcdsaSigShareInternal) fromWire(w *thresholdEcdsaSigShareWire) error {
+	if w.Version != common.WireVersion {
+		return errors.Wrapf(common.ErrInvalidEncoding, "unsupported signature share version %d", w.Version)
+	}
+	if _, ok := curve.Lookup(w.CurveType); !ok {
+		return common.ErrUnknownCurve
+	}
+	encoded := [][]byte{w.NumeratorValue, w.NumeratorMask, w.DenominatorValue, w.DenominatorMask}
+	scalars := make([]curve.EccScalar, len(encoded), len(encoded))
+	for i, bytes := range encoded {
+		s, err := curve.Scalar.DeserializeCanonical(w.CurveType, bytes)
+		if err != nil {
+			return err
+		}
+		scalars[i] = s
+	}
+	t.sigmaNumerator = poly2.PedersenCommitmentOpening{scalars[0], scalars[1]}
+	t.sigmaDenominator = poly2.PedersenCommitmentOpening{scalars[2], scalars[3]}
+	return nil
+}
+
+// Deserialize decodes a signature share produced by Serialize. Session.AddShare
+// verifies it before it is combined.
+func (thresholdEcdsaSigShare) Deserialize(data []byte) (*ThresholdEcdsaSigShareInternal, error) {
+	return common.DeserializeWire[ThresholdEcdsaSigShareInternal](data)
+}
+
+func (t ThresholdEcdsaSigShareInternal) Serialize() ([]byte, error) {
+	return t.MarshalCBOR()
+}
+
+func (t ThresholdEcdsaSigShareInternal) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(t.wire())
+}
+
+func (t *ThresholdEcdsaSigShareInternal) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, t.fromWire)
+}
+
+func (t ThresholdEcdsaSigShareInternal) MarshalJSON() ([]byte, error) {
+	return common.MarshalWireJSON(t.wire())
+}
+
+func (t *ThresholdEcdsaSigShareInternal) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalWireJSON(data, t.fromWire)
+}
@@ -0,0 +1,195 @@
+package sign
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/dealings"
+	"github.com/PlatONnetwork/tecdsa/key"
+	"github.com/PlatONnetwork/tecdsa/poly"
+	"github.com/pkg/errors"
+	"github.com/tidwall/btree"
+)
+
+// Presignature bundles the transcripts one signature consumes. Kappa is the
+// unmasked reshare of the nonce, the others are masked.
+type Presignature struct {
+	Kappa            *dealings.IDkgTranscriptInternal
+	Lambda           *dealings.IDkgTranscriptInternal
+	KeyTimesLambda   *dealings.IDkgTranscriptInternal
+	KappaTimesLambda *dealings.IDkgTranscriptInternal
+}
+
+// PresignatureOpenings are a node's shares of the masked transcripts of a
+// Presignature.
+type PresignatureOpenings struct {
+	Lambda           poly.CommitmentOpening
+	KeyTimesLambda   poly.CommitmentOpening
+	KappaTimesLambda poly.CommitmentOpening
+}
+
+// Signature is a standard ECDSA signature with a low S. RecoveryID is the
+// parity of the y coordinate of the nonce point, plus two if its x
+// coordinate exceeds the group order, as used by Ethereum and Bitcoin.
+type Signature struct {
+	R          curve.EccScalar
+	S          curve.EccScalar
+	RecoveryID byte
+}
+
+// Bytes returns r || s, each padded to the size of a scalar.
+func (s Signature) Bytes() []byte {
+	return append(s.R.Serialize(), s.S.Serialize()...)
+}
+
+// Session signs one message with one presignature. Every node creates its
+// share with CreateShare and adds the shares of the others with AddShare;
+// the signature is combined as soon as ReconstructionThreshold valid
+// shares are known. A Session is not safe for concurrent use.
+type Session struct {
+	curveType               curve.EccCurveType
+	reconstructionThreshold int
+	derivationPath          *key.DerivationPath
+	hashedMsg               []byte
+	randomness              []byte
+	key                     *dealings.IDkgTranscriptInternal
+	presig                  *Presignature
+
+	shares    btree.Map[common.NodeIndex, *ThresholdEcdsaSigShareInternal]
+	signature *Signature
+}
+
+func NewSession(curveType curve.EccCurveType, reconstructionThreshold int, keyTranscript *dealings.IDkgTranscriptInternal, presig *Presignature, derivationPath *key.DerivationPath, hashedMsg []byte, randomness []byte) (*Session, error) {
+	if _, ok := curve.Lookup(curveType); !ok {
+		return nil, common.ErrUnknownCurve
+	}
+	if reconstructionThreshold <= 0 {
+		return nil, errors.New("invalid reconstruction threshold")
+	}
+	if keyTranscript == nil || presig == nil || presig.Kappa == nil || presig.Lambda == nil || presig.KeyTimesLambda == nil || presig.KappaTimesLambda == nil {
+		return nil, errors.New("missing transcript")
+	}
+	if derivationPath == nil {
+		return nil, errors.New("missing derivation path")
+	}
+	if len(hashedMsg) != curveType.ScalarBytes() {
+		return nil, errors.New("hashed message has the wrong length")
+	}
+	checks := []struct {
+		transcript     *dealings.IDkgTranscriptInternal
+		commitmentType poly.PolynomialCommitmentType
+	}{
+		{keyTranscript, poly.Simple},
+		{presig.Kappa, poly.Simple},
+		{presig.Lambda, poly.Pedersen},
+		{presig.KeyTimesLambda, poly.Pedersen},
+		{presig.KappaTimesLambda, poly.Pedersen},
+	}
+	for _, c := range checks {
+		if err := c.transcript.CombinedCommitment.VerifyIs(c.commitmentType, curveType); err != nil {
+			return nil, err
+		}
+	}
+	if _, _, _, _, err := DeriveRho(curveType, hashedMsg, randomness, derivationPath, keyTranscript, presig.Kappa); err != nil {
+		return nil, err
+	}
+	return &Session{
+		curveType:               curveType,
+		reconstructionThreshold: reconstructionThreshold,
+		derivationPath:          derivationPath,
+		hashedMsg:               hashedMsg,
+		randomness:              randomness,
+		key:                     keyTranscript,
+		presig:                  presig,
+	}, nil
+}
+
+// CreateShare creates the share of signer from its presignature openings
+// and adds it to the session.
+func (s *Session) CreateShare(signer common.NodeIndex, openings *PresignatureOpenings) (*ThresholdEcdsaSigShareInternal, error) {
+	if openings == nil {
+		return nil, errors.New("missing presignature openings")
+	}
+	share, err := NewThresholdEcdsaSigShareInternal(s.derivationPath, s.hashedMsg, s.randomness, s.key, s.presig.Kappa, openings.Lambda, openings.KappaTimesLambda, openings.KeyTimesLambda, s.curveType)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.AddShare(signer, share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// AddShare verifies the share of signer and combines the signature once
+// enough shares are known. Shares of a signer already known and shares
+// arriving after the signature are ignored.
+func (s *Session) AddShare(signer common.NodeIndex, share *ThresholdEcdsaSigShareInternal) error {
+	if share == nil {
+		return errors.Errorf("empty signature share from node %d", signer)
+	}
+	if s.signature != nil {
+		return nil
+	}
+	if _, ok := s.shares.Get(signer); ok {
+		return nil
+	}
+	if err := share.Verify(s.derivationPath, s.hashedMsg, s.randomness, signer, s.key, s.presig.Kappa, s.presig.Lambda, s.presig.KappaTimesLambda, s.presig.KeyTimesLambda, s.curveType); err != nil {
+		return &common.ErrInvalidSigShare{Signer: signer, Err: err}
+	}
+	s.shares.Set(signer, share)
+	if s.shares.Len() < s.reconstructionThreshold {
+		return nil
+	}
+	combined, err := NewThresholdEcdsaCombinedSigInternal(s.derivationPath, s.hashedMsg, s.randomness, s.key, s.presig.Kappa, s.reconstructionThreshold, &s.shares, s.curveType)
+	if err != nil {
+		return err
+	}
+	signature, err := s.finish(combined)
+	if err != nil {
+		return err
+	}
+	s.signature = signature
+	return nil
+}
+
+// finish verifies the combined signature and derives its recovery id from
+// the nonce point the verification recomputes.
+func (s *Session) finish(combined *ThresholdEcdsaCombinedSigInternal) (*Signature, error) {
+	if err := combined.Verify(s.derivationPath, s.hashedMsg, s.randomness, s.key, s.presig.Kappa, s.curveType); err != nil {
+		return nil, err
+	}
+	publicKey, err := s.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := ConvertHashToInteger(s.hashedMsg, s.curveType)
+	if err != nil {
+		return nil, err
+	}
+	sInv := combined.S.Inverse()
+	nonce := curve.Point.MulPoints(curve.Point.GeneratorG(s.curveType), msg.Times(sInv), publicKey, combined.R.Times(sInv))
+	recoveryID := byte(nonce.AffineY().BigInt().Bit(0))
+	if nonce.AffineX().BigInt().Cmp(combined.R.BigInt()) != 0 {
+		recoveryID |= 2
+	}
+	return &Signature{R: combined.R, S: combined.S, RecoveryID: recoveryID}, nil
+}
+
+// PublicKey returns the key derived for the session's derivation path,
+// which verifies the signature.
+func (s *Session) PublicKey() (curve.EccPoint, error) {
+	keyTweak, _, err := s.derivationPath.DeriveTweak(s.key.ConstantTerm())
+	if err != nil {
+		return nil, err
+	}
+	return curve.Point.MulByG(keyTweak).Plus(s.key.ConstantTerm()), nil
+}
+
+// Signature returns the signature once enough shares were added.
+func (s *Session) Signature() *Signature {
+	return s.signature
+}
+
+// Done reports whether the signature is known.
+func (s *Session) Done() bool {
+	return s.signature != nil
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/PlatONnetwork/tecdsa/dealings"
 	"github.com/PlatONnetwork/tecdsa/key"
 	poly2 "github.com/PlatONnetwork/tecdsa/poly"
-	"github.com/pkg/errors"
 )
 
 type ThresholdEcdsaSigShareInternal struct {
@@ -23,7 +22,7 @@ func NewThresholdEcdsaSigShareInternal(derivationPath *key.DerivationPath, hashe
 	if err != nil {
 		return nil, err
 	}
-	theta := e.Add(e, rho.Clone().Mul(rho, keyTweak))
+	theta := e.Plus(rho.Times(keyTweak))
 	var lambdaValue curve.EccScalar
 	var lambdaMask curve.EccScalar
 	if l, ok := lambda.(poly2.PedersenCommitmentOpening); ok {
@@ -32,19 +31,22 @@ func NewThresholdEcdsaSigShareInternal(derivationPath *key.DerivationPath, hashe
 	}
 	var nu poly2.CommitmentOpening
 	if k, ok := keyTimesLambda.(poly2.PedersenCommitmentOpening); ok {
-		nuValue := theta.Clone().Mul(theta, lambdaValue)
-		nuValue = nuValue.Add(nuValue, rho.Clone().Mul(rho, k[0]))
-		nuMask := theta.Clone().Mul(theta, lambdaMask)
-		nuMask = nuMask.Add(nuMask, rho.Clone().Mul(rho, k[1]))
+		rhoKey := rho.Times(k[0])
+		rhoKeyMask := rho.Times(k[1])
+		nuValue := theta.Times(lambdaValue)
+		nuValue.Add(nuValue, rhoKey)
+		nuMask := theta.Times(lambdaMask)
+		nuMask.Add(nuMask, rhoKeyMask)
+		curve.Scalar.Zeroize(rhoKey, rhoKeyMask)
 		nu = poly2.PedersenCommitmentOpening{nuValue, nuMask}
 	}
 
 	var mu poly2.CommitmentOpening
 	if k, ok := kappaTimesLambda.(poly2.PedersenCommitmentOpening); ok {
-		muValue := randomizer.Clone().Mul(randomizer, lambdaValue)
-		muValue = muValue.Add(muValue, k[0])
-		muMask := randomizer.Clone().Mul(randomizer, lambdaMask)
-		muMask = muMask.Add(muMask, k[1])
+		muValue := randomizer.Times(lambdaValue)
+		muValue.Add(muValue, k[0])
+		muMask := randomizer.Times(lambdaMask)
+		muMask.Add(muMask, k[1])
 		mu = poly2.PedersenCommitmentOpening{muValue, muMask}
 	}
 	return &ThresholdEcdsaSigShareInternal{
@@ -63,24 +65,28 @@ func (t ThresholdEcdsaSigShareInternal) Verify(derivationPath *key.DerivationPat
 	if err != nil {
 		return err
 	}
-	theta := e.Add(e, rho.Clone().Mul(rho, keyTweak))
+	theta := e.Plus(rho.Times(keyTweak))
 
 	lambdaj := lambda.EvaluateAt(signerIndex)
 	kappaTimesLambdaJ := kappaTimesLambda.EvaluateAt(signerIndex)
 	keyTimesLambdaJ := keyTimesLambda.EvaluateAt(signerIndex)
 	sigmaNum := lambdaj.Clone().ScalarMul(lambdaj, theta)
-	sigmaNum = sigmaNum.AddPoints(sigmaNum, keyTimesLambdaJ.Clone().ScalarMul(kappaTimesLambdaJ, rho))
+	sigmaNum = sigmaNum.AddPoints(sigmaNum, keyTimesLambdaJ.Clone().ScalarMul(keyTimesLambdaJ, rho))
 	sigmaDen := lambdaj.Clone().ScalarMul(lambdaj, randomizer)
 	sigmaDen = sigmaDen.AddPoints(sigmaDen, kappaTimesLambdaJ)
-	if k, ok := t.sigmaNumerator.(*poly2.PedersenCommitmentOpening); ok {
-		if sigmaNum.Equal(curve.Point.Pedersen(k[0], k[1])) != 1 {
-			return errors.New("invalid commitment")
-		}
+	k, ok := t.sigmaNumerator.(poly2.PedersenCommitmentOpening)
+	if !ok || k[0].CurveType() != curveType || k[1].CurveType() != curveType {
+		return common.ErrUnexpectedCommitmentType
 	}
-	if k, ok := t.sigmaDenominator.(*poly2.PedersenCommitmentOpening); ok {
-		if sigmaNum.Equal(curve.Point.Pedersen(k[0], k[1])) != 1 {
-			return errors.New("invalid commitment")
-		}
+	if sigmaNum.Equal(curve.Point.Pedersen(k[0], k[1])) != 1 {
+		return common.ErrInvalidCommitment
+	}
+	k, ok = t.sigmaDenominator.(poly2.PedersenCommitmentOpening)
+	if !ok || k[0].CurveType() != curveType || k[1].CurveType() != curveType {
+		return common.ErrUnexpectedCommitmentType
+	}
+	if sigmaDen.Equal(curve.Point.Pedersen(k[0], k[1])) != 1 {
+		return common.ErrInvalidCommitment
 	}
 	return nil
 }
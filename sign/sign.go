@@ -1,12 +1,12 @@
 package sign
 
 import (
+	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
 	"github.com/PlatONnetwork/tecdsa/dealings"
 	"github.com/PlatONnetwork/tecdsa/key"
 	poly2 "github.com/PlatONnetwork/tecdsa/poly"
 	ro2 "github.com/PlatONnetwork/tecdsa/ro"
-	"github.com/pkg/errors"
 )
 
 func EcdsaConversion(pt curve.EccPoint) (curve.EccScalar, error) {
@@ -23,7 +23,7 @@ func DeriveRho(curveType curve.EccCurveType, hashedMsg []byte, randomness []byte
 	if p, ok := presigTranscript.CombinedCommitment.(*dealings.InterpolationCommitment); ok && p.PolynomialCommitment.Type() == poly2.Simple {
 		preSig = p.PolynomialCommitment.(*poly2.SimpleCommitment).ConstantTerm()
 	} else {
-		return nil, nil, nil, nil, errors.New("unexpected commitment type")
+		return nil, nil, nil, nil, common.ErrUnexpectedCommitmentType
 	}
 	keyTweak, _, err := derivationPath.DeriveTweak(keyTranscript.ConstantTerm())
 	if err != nil {
@@ -38,7 +38,7 @@ func DeriveRho(curveType curve.EccCurveType, hashedMsg []byte, randomness []byte
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
-	randomizedPresig := preSig.Clone().AddPoints(preSig, curve.Point.GeneratorG(curveType).ScalarMul(curve.Point.GeneratorG(curveType), randomizer))
+	randomizedPresig := preSig.Plus(curve.Point.MulByG(randomizer))
 	rho, err := EcdsaConversion(randomizedPresig)
 	if err != nil {
 		return nil, nil, nil, nil, err
@@ -50,7 +50,7 @@ func DeriveRho(curveType curve.EccCurveType, hashedMsg []byte, randomness []byte
 /// `derivation_path`.  The algorithm id of the derived key is the same
 /// as the algorithm id of `master_public_key`.
 func DerivePublicKey(master *key.MasterEcdsaPublicKey, derivationPath *key.DerivationPath) (*key.EcdsaPublicKey, error) {
-	pk, err := curve.Point.Deserialize(curve.K256, master.PublicKey)
+	pk, err := curve.Point.Deserialize(master.Curve(), master.PublicKey)
 	if err != nil {
 		return nil, err
 	}
@@ -58,8 +58,7 @@ func DerivePublicKey(master *key.MasterEcdsaPublicKey, derivationPath *key.Deriv
 	if err != nil {
 		return nil, err
 	}
-	tweakg := curve.Point.MulByG(keyTweak)
-	publicKey := tweakg.AddPoints(tweakg, pk)
+	publicKey := curve.Point.MulByG(keyTweak).Plus(pk)
 	return &key.EcdsaPublicKey{
 		PublicKey: publicKey.Serialize(),
 		ChainKey:  chainKey,
@@ -1,10 +1,10 @@
 package testutils
 
 import (
+	"context"
 	"fmt"
 	"github.com/PlatONnetwork/tecdsa/common"
 	complaints2 "github.com/PlatONnetwork/tecdsa/complaints"
-	"github.com/PlatONnetwork/tecdsa/curve"
 	dealings2 "github.com/PlatONnetwork/tecdsa/dealings"
 	"github.com/PlatONnetwork/tecdsa/poly"
 	seed2 "github.com/PlatONnetwork/tecdsa/seed"
@@ -48,20 +48,19 @@ func OpenDealings(setup *ProtocolSetup, dealings *btree.Map[common.NodeIndex, *d
 					if opener == common.NodeIndex(receiver) {
 						continue
 					}
-					if err := dealing.PrivateVerify(curve.K256, sk, pk, setup.Ad, dealerIndex, opener); err != nil {
+					if err := dealing.PrivateVerify(setup.CurveType, sk, pk, setup.Ad, dealerIndex, opener); err != nil {
 						continue
 					}
 
-					var dopening poly.CommitmentOpening
-					dopening, err = dealings2.CommitmentOpening.OpenDealing(dealing, setup.Ad, dealerIndex, opener, setup.Sk[opener], setup.Pk[opener])
+					var dopening *complaints2.IDkgOpeningInternal
+					dopening, err = complaints2.CreateOpening(dealing, dealerIndex, common.NodeIndex(receiver), opener, setup.Sk[opener], setup.Ad)
 					if err != nil {
 						panic("unable to open dealing")
 					}
-					if !dealing.Commitment.CheckOpening(opener, dopening) {
-						err = errors.New("verify dealing opening failed")
+					if err = complaints2.VerifyOpening(dopening, dealing, common.NodeIndex(receiver), opener); err != nil {
 						return false
 					}
-					openingsForThisDealing.Set(opener, dopening)
+					openingsForThisDealing.Set(opener, dopening.Opening)
 				}
 				for openingsForThisDealing.Len() > reconstructionThreshold {
 					index := int(rng.Uint32()) % openingsForThisDealing.Len()
@@ -85,22 +84,34 @@ func OpenDealings(setup *ProtocolSetup, dealings *btree.Map[common.NodeIndex, *d
 
 func CreateDealings(setup *ProtocolSetup, shares []dealings2.SecretShares, numberOfDealers int, numberOfDealingsCorrupted int, transcriptType dealings2.IDkgTranscriptOperationInternal, seed *seed2.Seed) (*btree.Map[common.NodeIndex, *dealings2.IDkgDealingInternal], error) {
 	rng := seed.Rng()
-	var dealings btree.Map[common.NodeIndex, *dealings2.IDkgDealingInternal]
-	for i, share := range shares {
+	dealingSeeds := make([]*seed2.Seed, len(shares), len(shares))
+	for i := range shares {
+		dealingSeeds[i] = seed2.FromRng(rng)
+	}
+	created := make([]*dealings2.IDkgDealingInternal, len(shares), len(shares))
+	err := common.ParallelFor(context.Background(), len(shares), func(i int) error {
 		dealerIndex := common.NodeIndex(i)
-		dealing, err := dealings2.NewIDkgDealingInternal(share, curve.K256, seed2.FromRng(rng), setup.Threshold, setup.Pk, dealerIndex, setup.Ad)
+		dealing, err := dealings2.NewIDkgDealingInternal(shares[i], setup.CurveType, dealingSeeds[i], setup.Threshold, setup.Pk, dealerIndex, setup.Ad)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		TestPublicDealingVerification(setup, dealing, transcriptType, dealerIndex)
 		sks, pks, recipients := setup.ReceiverInfo()
 		for i := 0; i < len(recipients); i++ {
 			sk, pk, recipient := sks[i], pks[i], recipients[i]
-			if err := dealing.PrivateVerify(curve.K256, sk, pk, setup.Ad, dealerIndex, recipient); err != nil {
-				return nil, err
+			if err := dealing.PrivateVerify(setup.CurveType, sk, pk, setup.Ad, dealerIndex, recipient); err != nil {
+				return err
 			}
-			dealings.Set(dealerIndex, dealing)
 		}
+		created[i] = dealing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var dealings btree.Map[common.NodeIndex, *dealings2.IDkgDealingInternal]
+	for i, dealing := range created {
+		dealings.Set(common.NodeIndex(i), dealing)
 	}
 	for dealings.Len() > numberOfDealers {
 		index := int(rng.Uint32()) % dealings.Len()
@@ -131,7 +142,7 @@ func CreateDealings(setup *ProtocolSetup, shares []dealings2.SecretShares, numbe
 		for i := 0; i < len(sks); i++ {
 			sk, pk, recipientIndex := sks[i], pks[i], recipientIndexs[i]
 			_, wasCorrupted := corrupt.Get(recipientIndex)
-			if badDealing.PrivateVerify(curve.K256, sk, pk, setup.Ad, dealerIndex, recipientIndex) != nil {
+			if badDealing.PrivateVerify(setup.CurveType, sk, pk, setup.Ad, dealerIndex, recipientIndex) != nil {
 				if !wasCorrupted {
 					panic("private verify failed")
 				}
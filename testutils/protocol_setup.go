@@ -9,6 +9,7 @@ import (
 )
 
 type ProtocolSetup struct {
+	CurveType     curve.EccCurveType
 	Threshold     int
 	Receivers     int
 	Ad            []byte
@@ -31,6 +32,7 @@ func NewProtocolSetup(curveType curve.EccCurveType, receivers int, threshold int
 	}
 
 	return &ProtocolSetup{
+		CurveType:     curveType,
 		Threshold:     threshold,
 		Receivers:     receivers,
 		Ad:            ad[:],
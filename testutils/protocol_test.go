@@ -1,12 +1,22 @@
 package testutils
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/PlatONnetwork/tecdsa/dealings"
 	"github.com/PlatONnetwork/tecdsa/key"
+	"github.com/PlatONnetwork/tecdsa/poly"
 	"github.com/PlatONnetwork/tecdsa/sign"
+	"github.com/PlatONnetwork/tecdsa/transport"
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tidwall/btree"
+	"sync"
 	"testing"
 	"time"
 )
@@ -88,6 +98,80 @@ func TestShouldReshareTranscriptsWithDynamicThreshold(t *testing.T) {
 	resharedd, err := Round.ReshareOfUnmasked(setup, resharedb, 3, corruptedDealings)
 	assert.Equal(t, 1, resharedc.ConstantTerm().Equal(resharedd.ConstantTerm()))
 }
+func TestShouldGenerateEd25519KeyTranscripts(t *testing.T) {
+	setup := NewProtocolSetup(curve.ED25519, 4, 2, RandomSeed())
+	corruptedDealings := 1
+	random, err := Round.Random(setup, 4, corruptedDealings)
+	assert.Nil(t, err)
+	key, err := Round.ReshareOfMasked(setup, random, 3, corruptedDealings)
+	assert.Nil(t, err)
+	reshared, err := Round.ReshareOfUnmasked(setup, key, 3, corruptedDealings)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, key.ConstantTerm().Equal(reshared.ConstantTerm()))
+
+	// any threshold of the openings recovers the secret key of the public key
+	for _, nodes := range [][]common.NodeIndex{{0, 1}, {1, 3}, {2, 3}} {
+		coefficients, err := poly.Lagrange.AtZero(curve.ED25519, nodes)
+		assert.Nil(t, err)
+		shares := make([]curve.EccScalar, len(nodes))
+		for i, node := range nodes {
+			shares[i] = key.Openings[node].(poly.SimpleCommitmentOpening)[0]
+		}
+		secret, err := coefficients.InterpolateScalar(shares)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, curve.Point.MulByG(secret).Equal(key.ConstantTerm()))
+	}
+	assert.Equal(t, 32, len(key.ConstantTerm().Serialize()))
+}
+
+func TestTranscriptsSurviveSerialization(t *testing.T) {
+	setup := NewProtocolSetup(curve.P256, 4, 2, RandomSeed())
+	random, err := Round.Random(setup, 4, 1)
+	assert.Nil(t, err)
+	masked, err := Round.ReshareOfMasked(setup, random, 3, 1)
+	assert.Nil(t, err)
+	unmasked, err := Round.ReshareOfUnmasked(setup, masked, 3, 1)
+	assert.Nil(t, err)
+	product, err := Round.Multiply(setup, random, unmasked, 3, 1)
+	assert.Nil(t, err)
+
+	operations := []dealings.TranscriptOperationType{dealings.RandomOperation, dealings.ReshareOfMaskedOperation, dealings.ReshareOfUnmaskedOperation, dealings.UnmaskedTimesMaskedOperation}
+	for i, round := range []*ProtocolRound{random, masked, unmasked, product} {
+		assert.Equal(t, operations[i], round.Transcript.OperationType)
+		bytes, err := round.Transcript.Serialize()
+		assert.Nil(t, err)
+		decoded, err := dealings.Transcript.Deserialize(bytes)
+		assert.Nil(t, err)
+		assert.Equal(t, round.Transcript.OperationType, decoded.OperationType)
+		assert.Equal(t, setup.Threshold, decoded.ReconstructionThreshold)
+		assert.Equal(t, round.Transcript.CombinedCommitment.CombinationType(), decoded.CombinedCommitment.CombinationType())
+		assert.Equal(t, round.Transcript.CombinedCommitment.Type(), decoded.CombinedCommitment.Type())
+		assert.Equal(t, 1, round.Transcript.CombinedCommitment.Equal(decoded.CombinedCommitment))
+
+		// a restored transcript opens to the same shares, through complaints
+		// for the receivers of the corrupted dealing
+		openings, err := OpenDealings(setup, round.Dealings, decoded)
+		require.NoError(t, err)
+		require.Equal(t, len(round.Openings), len(openings))
+		for receiver, opening := range openings {
+			assert.True(t, decoded.CombinedCommitment.CheckOpening(common.NodeIndex(receiver), opening))
+			assert.Equal(t, round.Openings[receiver], opening)
+		}
+
+		// Serialize still encodes the bare polynomial commitment
+		plain, err := round.Transcript.CombinedCommitment.Serialize()
+		assert.Nil(t, err)
+		commitment, err := poly.PolynomialCM.Deserialize(plain)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, round.Transcript.CombinedCommitment.Equal(commitment))
+		combined, err := round.Transcript.CombinedCommitment.SerializeCombined()
+		assert.Nil(t, err)
+		restored, err := dealings.CombinedCM.Deserialize(combined)
+		assert.Nil(t, err)
+		assert.IsType(t, round.Transcript.CombinedCommitment, restored)
+	}
+}
+
 func TestShouldMultiplyTranscriptsWithDynamicThreshold(t *testing.T) {
 	setup := NewProtocolSetup(curve.K256, 5, 2, RandomSeed())
 	corruptedDealings := 1
@@ -126,33 +210,35 @@ func TestShouldBasicSigningProtocolWork(t *testing.T) {
 	//testSigSerialization := func(sig *sign.ThresholdEcdsaCombinedSigInternal) error {
 	//	return nil
 	//}
-	nodes := 10
-	threshold := nodes / 3
-	numberOfDealingsCorrupted := threshold
-	start := time.Now()
-	setup, err := NewSignatureProtocolSetup(curve.K256, nodes, threshold, numberOfDealingsCorrupted, RandomSeed())
-	assert.Nil(t, err)
-	t.Log("set up", time.Since(start))
+	for _, curveType := range []curve.EccCurveType{curve.K256, curve.P256} {
+		nodes := 10
+		threshold := nodes / 3
+		numberOfDealingsCorrupted := threshold
+		start := time.Now()
+		setup, err := NewSignatureProtocolSetup(curveType, nodes, threshold, numberOfDealingsCorrupted, RandomSeed())
+		assert.Nil(t, err)
+		t.Log(curveType, "set up", time.Since(start))
 
-	rng := RandomSeed().Rng()
+		rng := RandomSeed().Rng()
 
-	var signedMessage [32]byte
-	rng.FillUint8(signedMessage[:])
-	var randomBeacon [32]byte
-	rng.FillUint8(signedMessage[:])
-	derivationPath := key.NewBip32([]uint32{1, 2, 3})
-	start = time.Now()
-	proto := NewSignatureProtocolExecution(setup, signedMessage[:], randomBeacon[:], derivationPath)
-	start = time.Now()
-	shares, err := proto.GenerateShares()
-	assert.Nil(t, err)
-	t.Log("generate shares", time.Since(start))
-	start = time.Now()
-	sig, err := proto.GenerateSignature(shares)
-	t.Log("generate signature", time.Since(start))
-	start = time.Now()
-	assert.Nil(t, proto.VerifySignature(sig))
-	t.Log("verify signature", time.Since(start))
+		var signedMessage [32]byte
+		rng.FillUint8(signedMessage[:])
+		var randomBeacon [32]byte
+		rng.FillUint8(signedMessage[:])
+		derivationPath := key.NewBip32([]uint32{1, 2, 3})
+		start = time.Now()
+		proto := NewSignatureProtocolExecution(setup, signedMessage[:], randomBeacon[:], derivationPath)
+		start = time.Now()
+		shares, err := proto.GenerateShares()
+		assert.Nil(t, err)
+		t.Log(curveType, "generate shares", time.Since(start))
+		start = time.Now()
+		sig, err := proto.GenerateSignature(shares)
+		t.Log(curveType, "generate signature", time.Since(start))
+		start = time.Now()
+		assert.Nil(t, proto.VerifySignature(sig))
+		t.Log(curveType, "verify signature", time.Since(start))
+	}
 
 	//for i := 4; i <= nodes; i++ {
 	//	shares := RandomSubset(shares, i)
@@ -167,3 +253,195 @@ func TestShouldBasicSigningProtocolWork(t *testing.T) {
 	//	}
 	//}
 }
+
+func TestCombinationInterpolatesTheFirstThresholdShares(t *testing.T) {
+	nodes, threshold := 5, 3
+	setup, err := NewSignatureProtocolSetup(curve.K256, nodes, threshold, 0, RandomSeed())
+	require.NoError(t, err)
+	proto := NewSignatureProtocolExecution(setup, []byte("message"), []byte("beacon"), key.NewBip32([]uint32{1, 2, 3}))
+	shares, err := proto.GenerateShares()
+	require.NoError(t, err)
+
+	// the shares after the first threshold ones are not used, so a wrong
+	// one there does not change the signature
+	share0, _ := shares.Get(0)
+	shares.Set(4, share0)
+	sig, err := proto.GenerateSignature(shares)
+	require.NoError(t, err)
+	assert.Nil(t, proto.VerifySignature(sig))
+
+	shares.Set(1, share0)
+	sig, err = proto.GenerateSignature(shares)
+	require.NoError(t, err)
+	assert.NotNil(t, proto.VerifySignature(sig))
+}
+
+func TestRobustCombinationExcludesBadShares(t *testing.T) {
+	nodes, threshold := 5, 3
+	setup, err := NewSignatureProtocolSetup(curve.K256, nodes, threshold, 0, RandomSeed())
+	assert.Nil(t, err)
+	proto := NewSignatureProtocolExecution(setup, []byte("message"), []byte("beacon"), key.NewBip32([]uint32{1, 2, 3}))
+	shares, err := proto.GenerateShares()
+	assert.Nil(t, err)
+
+	honest, _ := shares.Get(0)
+	sig, misbehaving, err := proto.GenerateSignatureRobust(shares)
+	assert.Nil(t, err)
+	assert.Empty(t, misbehaving)
+	assert.Nil(t, proto.VerifySignature(sig))
+
+	// a bad share after the ones combined is reported as well
+	share4, _ := shares.Get(4)
+	shares.Set(4, honest)
+	sig, misbehaving, err = proto.GenerateSignatureRobust(shares)
+	assert.Nil(t, err)
+	assert.Equal(t, []common.NodeIndex{4}, misbehaving)
+	assert.Nil(t, proto.VerifySignature(sig))
+	shares.Set(4, share4)
+
+	// node 0 sends the share of node 2 and node 1 sends nothing usable
+	share2, _ := shares.Get(2)
+	shares.Set(0, share2)
+	shares.Set(1, nil)
+	sig, misbehaving, err = proto.GenerateSignatureRobust(shares)
+	assert.Nil(t, err)
+	assert.Equal(t, []common.NodeIndex{0, 1}, misbehaving)
+	assert.Nil(t, proto.VerifySignature(sig))
+
+	// without a third valid share the signature cannot be combined
+	shares.Set(3, honest)
+	sig, misbehaving, err = proto.GenerateSignatureRobust(shares)
+	assert.Nil(t, sig)
+	assert.Equal(t, []common.NodeIndex{0, 1, 3}, misbehaving)
+	var insufficient *common.ErrInsufficientShares
+	if assert.True(t, errors.As(err, &insufficient)) {
+		assert.Equal(t, 2, insufficient.Have)
+		assert.Equal(t, 3, insufficient.Need)
+	}
+}
+
+func TestSigningSessionCombinesVerifiedShares(t *testing.T) {
+	nodes, threshold := 4, 2
+	setup, err := NewSignatureProtocolSetup(curve.K256, nodes, threshold, 1, RandomSeed())
+	assert.Nil(t, err)
+	message := sha256.Sum256([]byte("message"))
+	derivationPath := key.NewBip32([]uint32{1, 2, 3})
+	newSession := func() *sign.Session {
+		session, err := sign.NewSession(curve.K256, threshold, setup.Key.Transcript, setup.Presignature(), derivationPath, message[:], []byte("beacon"))
+		assert.Nil(t, err)
+		return session
+	}
+
+	sessions := make([]*sign.Session, nodes)
+	shares := make([]*sign.ThresholdEcdsaSigShareInternal, nodes)
+	for i := range sessions {
+		sessions[i] = newSession()
+		shares[i], err = sessions[i].CreateShare(common.NodeIndex(i), setup.PresignatureOpenings(i))
+		assert.Nil(t, err)
+		assert.False(t, sessions[i].Done())
+	}
+	// a share created from the openings of another node does not verify
+	err = newSession().AddShare(1, shares[0])
+	assert.True(t, errors.Is(err, common.ErrMisbehavingSigner))
+
+	for i, session := range sessions {
+		for j := range shares {
+			assert.Nil(t, session.AddShare(common.NodeIndex(j), shares[j]))
+		}
+		sig := session.Signature()
+		require.NotNil(t, sig, i)
+		assert.Equal(t, 0, sig.R.BigInt().Cmp(sessions[0].Signature().R.BigInt()), i)
+		assert.Equal(t, 0, sig.S.BigInt().Cmp(sessions[0].Signature().S.BigInt()), i)
+	}
+
+	sig := sessions[0].Signature()
+	publicKey, err := sessions[0].PublicKey()
+	assert.Nil(t, err)
+	compact := append([]byte{27 + 4 + sig.RecoveryID}, sig.Bytes()...)
+	recovered, compressed, err := btcec.RecoverCompact(btcec.S256(), compact, message[:])
+	assert.Nil(t, err)
+	assert.True(t, compressed)
+	assert.Equal(t, publicKey.Serialize(), recovered.SerializeCompressed())
+
+	derived, err := setup.PublicKey(derivationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, derived.PublicKey, publicKey.Serialize())
+	// a master key without a curve type is a K256 key
+	derived, err = sign.DerivePublicKey(&key.MasterEcdsaPublicKey{PublicKey: setup.Key.Transcript.ConstantTerm().Serialize()}, derivationPath)
+	assert.Nil(t, err)
+	assert.Equal(t, derived.PublicKey, publicKey.Serialize())
+
+	_, err = sign.NewSession(curve.K256, threshold, setup.Key.Transcript, &sign.Presignature{Kappa: setup.Kappa.Transcript}, derivationPath, message[:], nil)
+	assert.NotNil(t, err)
+	_, err = sign.NewSession(curve.K256, threshold, setup.Lambda.Transcript, setup.Presignature(), derivationPath, message[:], nil)
+	assert.True(t, errors.Is(err, common.ErrUnexpectedCommitmentType))
+}
+
+// sigShareCodec carries signature shares over a transport.Network.
+type sigShareCodec struct{}
+
+func (sigShareCodec) Encode(payload interface{}) ([]byte, error) {
+	share, ok := payload.(*sign.ThresholdEcdsaSigShareInternal)
+	if !ok {
+		return nil, errors.New("not a signature share")
+	}
+	return share.Serialize()
+}
+
+func (sigShareCodec) Decode(data []byte) (interface{}, error) {
+	return sign.SigShare.Deserialize(data)
+}
+
+func TestSigningOverASimulatedNetwork(t *testing.T) {
+	nodes, threshold := 4, 2
+	setup, err := NewSignatureProtocolSetup(curve.K256, nodes, threshold, 1, RandomSeed())
+	require.NoError(t, err)
+	message := sha256.Sum256([]byte("message"))
+	derivationPath := key.NewBip32([]uint32{1, 2, 3})
+	network, err := transport.NewSimulatedNetwork(transport.SimulatedConfig{
+		Nodes:      nodes,
+		MaxLatency: 2 * time.Millisecond,
+		Reorder:    true,
+		Codec:      sigShareCodec{},
+	})
+	require.NoError(t, err)
+	defer network.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	signatures := make([]*sign.Signature, nodes)
+	var wg sync.WaitGroup
+	for i := 0; i < nodes; i++ {
+		node, err := network.Node(common.NodeIndex(i))
+		require.NoError(t, err)
+		session, err := sign.NewSession(curve.K256, threshold, setup.Key.Transcript, setup.Presignature(), derivationPath, message[:], []byte("beacon"))
+		require.NoError(t, err)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			share, err := session.CreateShare(common.NodeIndex(i), setup.PresignatureOpenings(i))
+			if !assert.Nil(t, err) || !assert.Nil(t, node.Broadcast(ctx, share)) {
+				return
+			}
+			for !session.Done() {
+				e, err := node.Receive(ctx)
+				if !assert.Nil(t, err) {
+					return
+				}
+				assert.Nil(t, session.AddShare(e.From, e.Payload.(*sign.ThresholdEcdsaSigShareInternal)))
+			}
+			signatures[i] = session.Signature()
+		}(i)
+	}
+	wg.Wait()
+
+	publicKey, err := setup.PublicKey(derivationPath)
+	require.NoError(t, err)
+	pk, err := btcec.ParsePubKey(publicKey.PublicKey, btcec.S256())
+	require.NoError(t, err)
+	for i, sig := range signatures {
+		require.NotNil(t, sig, i)
+		assert.True(t, ecdsa.Verify(pk.ToECDSA(), message[:], sig.R.BigInt(), sig.S.BigInt()), i)
+		assert.Equal(t, sig.Bytes(), signatures[0].Bytes(), i)
+	}
+}
@@ -2,6 +2,7 @@ package testutils
 
 import (
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/sha256"
 	"github.com/PlatONnetwork/tecdsa/common"
 	"github.com/PlatONnetwork/tecdsa/curve"
@@ -69,7 +70,24 @@ func NewSignatureProtocolSetup(curveType curve.EccCurveType, numberOfDealers int
 }
 
 func (s SignatureProtocolSetup) PublicKey(path *key.DerivationPath) (*key.EcdsaPublicKey, error) {
-	return sign.DerivePublicKey(&key.MasterEcdsaPublicKey{PublicKey: s.Key.Transcript.ConstantTerm().Serialize()}, path)
+	return sign.DerivePublicKey(&key.MasterEcdsaPublicKey{CurveType: s.Setup.CurveType, PublicKey: s.Key.Transcript.ConstantTerm().Serialize()}, path)
+}
+
+func (s SignatureProtocolSetup) Presignature() *sign.Presignature {
+	return &sign.Presignature{
+		Kappa:            s.Kappa.Transcript,
+		Lambda:           s.Lambda.Transcript,
+		KeyTimesLambda:   s.KeyTimesLambda.Transcript,
+		KappaTimesLambda: s.KappaTimesLambda.Transcript,
+	}
+}
+
+func (s SignatureProtocolSetup) PresignatureOpenings(nodeIndex int) *sign.PresignatureOpenings {
+	return &sign.PresignatureOpenings{
+		Lambda:           s.Lambda.Openings[nodeIndex],
+		KeyTimesLambda:   s.KeyTimesLambda.Openings[nodeIndex],
+		KappaTimesLambda: s.KappaTimesLambda.Openings[nodeIndex],
+	}
 }
 
 type SignatureProtocolExecution struct {
@@ -93,11 +111,11 @@ func NewSignatureProtocolExecution(setup *SignatureProtocolSetup, signedMessage
 func (s SignatureProtocolExecution) GenerateShares() (*btree.Map[common.NodeIndex, *sign.ThresholdEcdsaSigShareInternal], error) {
 	var shares btree.Map[common.NodeIndex, *sign.ThresholdEcdsaSigShareInternal]
 	for nodeIndex := 0; nodeIndex < s.Setup.Setup.Receivers; nodeIndex++ {
-		share, err := sign.NewThresholdEcdsaSigShareInternal(s.DerivationPath, s.HashedMessage, s.RandomBeacon, s.Setup.Key.Transcript, s.Setup.Kappa.Transcript, s.Setup.Lambda.Openings[nodeIndex], s.Setup.KappaTimesLambda.Openings[nodeIndex], s.Setup.KeyTimesLambda.Openings[nodeIndex], curve.K256)
+		share, err := sign.NewThresholdEcdsaSigShareInternal(s.DerivationPath, s.HashedMessage, s.RandomBeacon, s.Setup.Key.Transcript, s.Setup.Kappa.Transcript, s.Setup.Lambda.Openings[nodeIndex], s.Setup.KappaTimesLambda.Openings[nodeIndex], s.Setup.KeyTimesLambda.Openings[nodeIndex], s.Setup.Setup.CurveType)
 		if err != nil {
 			return nil, err
 		}
-		if err := share.Verify(s.DerivationPath, s.HashedMessage, s.RandomBeacon, common.NodeIndex(nodeIndex), s.Setup.Key.Transcript, s.Setup.Kappa.Transcript, s.Setup.Lambda.Transcript, s.Setup.KappaTimesLambda.Transcript, s.Setup.KeyTimesLambda.Transcript, curve.K256); err != nil {
+		if err := share.Verify(s.DerivationPath, s.HashedMessage, s.RandomBeacon, common.NodeIndex(nodeIndex), s.Setup.Key.Transcript, s.Setup.Kappa.Transcript, s.Setup.Lambda.Transcript, s.Setup.KappaTimesLambda.Transcript, s.Setup.KeyTimesLambda.Transcript, s.Setup.Setup.CurveType); err != nil {
 			return nil, err
 		}
 		shares.Set(common.NodeIndex(nodeIndex), share)
@@ -105,20 +123,32 @@ func (s SignatureProtocolExecution) GenerateShares() (*btree.Map[common.NodeInde
 	return &shares, nil
 }
 func (s SignatureProtocolExecution) GenerateSignature(shares *btree.Map[common.NodeIndex, *sign.ThresholdEcdsaSigShareInternal]) (*sign.ThresholdEcdsaCombinedSigInternal, error) {
-	return sign.NewThresholdEcdsaCombinedSigInternal(s.DerivationPath, s.HashedMessage, s.RandomBeacon, s.Setup.Key.Transcript, s.Setup.Kappa.Transcript, s.Setup.Setup.Threshold, shares, curve.K256)
+	return sign.NewThresholdEcdsaCombinedSigInternal(s.DerivationPath, s.HashedMessage, s.RandomBeacon, s.Setup.Key.Transcript, s.Setup.Kappa.Transcript, s.Setup.Setup.Threshold, shares, s.Setup.Setup.CurveType)
+}
+
+func (s SignatureProtocolExecution) GenerateSignatureRobust(shares *btree.Map[common.NodeIndex, *sign.ThresholdEcdsaSigShareInternal]) (*sign.ThresholdEcdsaCombinedSigInternal, []common.NodeIndex, error) {
+	return sign.NewThresholdEcdsaCombinedSigInternalRobust(s.DerivationPath, s.HashedMessage, s.RandomBeacon, s.Setup.Key.Transcript, s.Setup.Kappa.Transcript, s.Setup.Lambda.Transcript, s.Setup.KappaTimesLambda.Transcript, s.Setup.KeyTimesLambda.Transcript, s.Setup.Setup.Threshold, shares, s.Setup.Setup.CurveType)
 }
 
 func (s SignatureProtocolExecution) VerifySignature(sig *sign.ThresholdEcdsaCombinedSigInternal) error {
-	if err := sig.Verify(s.DerivationPath, s.HashedMessage, s.RandomBeacon, s.Setup.Key.Transcript, s.Setup.Kappa.Transcript, curve.K256); err != nil {
+	if err := sig.Verify(s.DerivationPath, s.HashedMessage, s.RandomBeacon, s.Setup.Key.Transcript, s.Setup.Kappa.Transcript, s.Setup.Setup.CurveType); err != nil {
 		return err
 	}
 	pk, err := s.Setup.PublicKey(s.DerivationPath)
 	if err != nil {
 		return err
 	}
-	publicKey, _ := curve.Point.Deserialize(curve.K256, pk.PublicKey)
+	curveType := s.Setup.Setup.CurveType
+	publicKey, _ := curve.Point.Deserialize(curveType, pk.PublicKey)
+	var ec elliptic.Curve
+	switch curveType {
+	case curve.K256:
+		ec = btcec.S256()
+	case curve.P256:
+		ec = elliptic.P256()
+	}
 	if !ecdsa.Verify(&ecdsa.PublicKey{
-		Curve: btcec.S256(),
+		Curve: ec,
 		X:     publicKey.AffineX().BigInt(),
 		Y:     publicKey.AffineY().BigInt(),
 	}, s.HashedMessage, sig.R.BigInt(), sig.S.BigInt()) {
@@ -66,16 +66,16 @@ func CorruptDealingForAllRecipients(dealing *dealings.IDkgDealingInternal, seed
 }
 
 func TestPublicDealingVerification(setup *ProtocolSetup, dealing *dealings.IDkgDealingInternal, transcriptType dealings.IDkgTranscriptOperationInternal, dealerIndex common.NodeIndex) {
-	if err := dealing.PubliclyVerify(curve.K256, transcriptType, setup.Threshold, dealerIndex, setup.Receivers, setup.Ad); err != nil {
+	if err := dealing.PubliclyVerify(setup.CurveType, transcriptType, setup.Threshold, dealerIndex, setup.Receivers, setup.Ad); err != nil {
 		panic("created a publicly invalid dealing")
 	}
-	if dealing.PubliclyVerify(curve.K256, transcriptType, setup.Threshold, dealerIndex+1, setup.Receivers, setup.Ad) == nil {
+	if dealing.PubliclyVerify(setup.CurveType, transcriptType, setup.Threshold, dealerIndex+1, setup.Receivers, setup.Ad) == nil {
 		panic("created a publicly invalid dealing")
 	}
-	if dealing.PubliclyVerify(curve.K256, transcriptType, setup.Threshold, dealerIndex+1, setup.Receivers+1, setup.Ad) == nil {
+	if dealing.PubliclyVerify(setup.CurveType, transcriptType, setup.Threshold, dealerIndex+1, setup.Receivers+1, setup.Ad) == nil {
 		panic("created a publicly invalid dealing")
 	}
-	if dealing.PubliclyVerify(curve.K256, transcriptType, setup.Threshold, dealerIndex+1, setup.Receivers+1, []byte("wrong ad")) == nil {
+	if dealing.PubliclyVerify(setup.CurveType, transcriptType, setup.Threshold, dealerIndex+1, setup.Receivers+1, []byte("wrong ad")) == nil {
 		panic("created a publicly invalid dealing")
 	}
 }
@@ -0,0 +1,31 @@
+package wiretest
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// Reencode decodes the canonical CBOR in data into its wire struct W,
+// applies modify and encodes the result again. The tests use it to build
+// encodings that are well formed but carry invalid contents.
+func Reencode[W any](t *testing.T, data []byte, modify func(w *W)) []byte {
+	t.Helper()
+	var w W
+	require.NoError(t, common.UnmarshalStrict(data, &w))
+	modify(&w)
+	modified, err := common.MarshalCanonical(&w)
+	require.NoError(t, err)
+	return modified
+}
+
+// AssertRejected checks that deserialize rejects data after each of the
+// named modifications of its wire struct.
+func AssertRejected[W any, T any](t *testing.T, data []byte, deserialize func([]byte) (T, error), invalid map[string]func(w *W)) {
+	t.Helper()
+	for name, modify := range invalid {
+		_, err := deserialize(Reencode(t, data, modify))
+		assert.Error(t, err, name)
+	}
+}
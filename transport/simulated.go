@@ -0,0 +1,324 @@
+package transport
+
+import (
+	"context"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/rand"
+	"github.com/PlatONnetwork/tecdsa/seed"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// SimulatedConfig configures a SimulatedNetwork. The latencies and drops
+// are drawn from Seed, so a single goroutine sending the same messages sees
+// the same fate for each of them.
+type SimulatedConfig struct {
+	Nodes int
+	// The latency of each message is drawn uniformly from
+	// [MinLatency, MaxLatency].
+	MinLatency time.Duration
+	MaxLatency time.Duration
+	// Reorder lets messages between two nodes overtake each other. Without
+	// it every link delivers in the order messages were sent.
+	Reorder bool
+	// DropRate is the probability that a message is lost.
+	DropRate float64
+	Seed     *seed.Seed
+	// Codec encodes payloads on Send and decodes them on Receive. Defaults
+	// to CBOR.
+	Codec Codec
+}
+
+// SimulatedStats counts what happened to the messages sent so far.
+type SimulatedStats struct {
+	Sent, Delivered, Dropped, Held int
+}
+
+type link struct {
+	from, to common.NodeIndex
+}
+
+// linkState orders the messages of a link when reordering is off.
+type linkState struct {
+	last  time.Time
+	sent  uint64
+	next  uint64
+	ready map[uint64]*Envelope
+}
+
+type inbox struct {
+	queue  []*Envelope
+	notify chan struct{}
+}
+
+// SimulatedNetwork connects nodes in memory, with the latency, reordering,
+// losses and partitions of a real network. Payloads travel encoded, as
+// they would between machines.
+type SimulatedNetwork struct {
+	config SimulatedConfig
+
+	mu      sync.Mutex
+	rng     rand.Rand
+	links   map[link]*linkState
+	inboxes []*inbox
+	// groups assigns each node to a side of the partition, nil when the
+	// network is whole. Messages between sides are held until Heal.
+	groups []int
+	held   []*Envelope
+	stats  SimulatedStats
+	closed chan struct{}
+}
+
+func NewSimulatedNetwork(config SimulatedConfig) (*SimulatedNetwork, error) {
+	if config.Nodes <= 0 {
+		return nil, errors.New("invalid number of nodes")
+	}
+	if config.MinLatency < 0 || config.MaxLatency < config.MinLatency {
+		return nil, errors.New("invalid latency")
+	}
+	if config.DropRate < 0 || config.DropRate > 1 {
+		return nil, errors.New("invalid drop rate")
+	}
+	if config.Seed == nil {
+		config.Seed = seed.FromBytes(nil)
+	}
+	if config.Codec == nil {
+		config.Codec = CBOR
+	}
+	inboxes := make([]*inbox, config.Nodes, config.Nodes)
+	for i := range inboxes {
+		inboxes[i] = &inbox{notify: make(chan struct{}, 1)}
+	}
+	return &SimulatedNetwork{
+		config:  config,
+		rng:     config.Seed.Derive("ic-crypto-tecdsa-simulated-network").Rng(),
+		links:   make(map[link]*linkState),
+		inboxes: inboxes,
+		closed:  make(chan struct{}),
+	}, nil
+}
+
+// Node returns the endpoint of node index.
+func (s *SimulatedNetwork) Node(index common.NodeIndex) (Network, error) {
+	if int(index) >= s.config.Nodes {
+		return nil, errors.Wrapf(ErrUnknownNode, "node %d", index)
+	}
+	return &simulatedEndpoint{network: s, self: index}, nil
+}
+
+// SetDropRate changes the probability that a message is lost.
+func (s *SimulatedNetwork) SetDropRate(rate float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config.DropRate = rate
+}
+
+// Partition splits the network into the given groups, plus one more group
+// of the nodes not listed. Messages from one group to another, including
+// those in flight, are held until Heal.
+func (s *SimulatedNetwork) Partition(groups ...[]common.NodeIndex) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups = make([]int, s.config.Nodes, s.config.Nodes)
+	for i, group := range groups {
+		for _, node := range group {
+			if int(node) < s.config.Nodes {
+				s.groups[node] = i + 1
+			}
+		}
+	}
+}
+
+// Heal ends the partition and delivers the held messages.
+func (s *SimulatedNetwork) Heal() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups = nil
+	held := s.held
+	s.held = nil
+	s.stats.Held -= len(held)
+	for _, e := range held {
+		s.push(e)
+	}
+}
+
+func (s *SimulatedNetwork) Stats() SimulatedStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Close stops the network. Pending Receive calls return ErrClosed and the
+// messages in flight are discarded.
+func (s *SimulatedNetwork) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.closed:
+	default:
+		close(s.closed)
+	}
+}
+
+func (s *SimulatedNetwork) isClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *SimulatedNetwork) send(from, to common.NodeIndex, payload interface{}) error {
+	if int(to) >= s.config.Nodes {
+		return errors.Wrapf(ErrUnknownNode, "node %d", to)
+	}
+	data, err := s.config.Codec.Encode(payload)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isClosed() {
+		return ErrClosed
+	}
+	s.stats.Sent++
+	if s.config.DropRate > 0 && float64(s.rng.Uint32()) < s.config.DropRate*(1<<32) {
+		s.stats.Dropped++
+		return nil
+	}
+	delay := s.config.MinLatency
+	if spread := s.config.MaxLatency - s.config.MinLatency; spread > 0 {
+		delay += time.Duration(s.rng.Uint64() % uint64(spread+1))
+	}
+	e := &Envelope{From: from, To: to, Payload: data}
+	if s.config.Reorder {
+		time.AfterFunc(delay, func() { s.deliver(e, nil, 0) })
+		return nil
+	}
+	l, ok := s.links[link{from, to}]
+	if !ok {
+		l = &linkState{ready: make(map[uint64]*Envelope)}
+		s.links[link{from, to}] = l
+	}
+	now := time.Now()
+	at := now.Add(delay)
+	if at.Before(l.last) {
+		at = l.last
+	}
+	l.last = at
+	seq := l.sent
+	l.sent++
+	time.AfterFunc(at.Sub(now), func() { s.deliver(e, l, seq) })
+	return nil
+}
+
+// deliver is called when the latency of a message has elapsed. On an
+// ordered link the message waits for those sent before it.
+func (s *SimulatedNetwork) deliver(e *Envelope, l *linkState, seq uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isClosed() {
+		return
+	}
+	if l == nil {
+		s.route(e)
+		return
+	}
+	l.ready[seq] = e
+	for {
+		next, ok := l.ready[l.next]
+		if !ok {
+			return
+		}
+		delete(l.ready, l.next)
+		l.next++
+		s.route(next)
+	}
+}
+
+func (s *SimulatedNetwork) route(e *Envelope) {
+	if s.groups != nil && s.groups[e.From] != s.groups[e.To] {
+		s.held = append(s.held, e)
+		s.stats.Held++
+		return
+	}
+	s.push(e)
+}
+
+func (s *SimulatedNetwork) push(e *Envelope) {
+	in := s.inboxes[e.To]
+	in.queue = append(in.queue, e)
+	s.stats.Delivered++
+	select {
+	case in.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (s *SimulatedNetwork) receive(ctx context.Context, self common.NodeIndex) (*Envelope, error) {
+	in := s.inboxes[self]
+	for {
+		s.mu.Lock()
+		if s.isClosed() {
+			s.mu.Unlock()
+			return nil, ErrClosed
+		}
+		if len(in.queue) > 0 {
+			e := in.queue[0]
+			in.queue[0] = nil
+			in.queue = in.queue[1:]
+			s.mu.Unlock()
+			payload, err := s.config.Codec.Decode(e.Payload.([]byte))
+			if err != nil {
+				return nil, errors.Wrapf(ErrUndecodable, "message from node %d: %v", e.From, err)
+			}
+			return &Envelope{From: e.From, To: e.To, Payload: payload}, nil
+		}
+		s.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.closed:
+			return nil, ErrClosed
+		case <-in.notify:
+		}
+	}
+}
+
+type simulatedEndpoint struct {
+	network *SimulatedNetwork
+	self    common.NodeIndex
+}
+
+func (n *simulatedEndpoint) Self() common.NodeIndex {
+	return n.self
+}
+
+func (n *simulatedEndpoint) Nodes() int {
+	return n.network.config.Nodes
+}
+
+func (n *simulatedEndpoint) Send(ctx context.Context, to common.NodeIndex, payload interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return n.network.send(n.self, to, payload)
+}
+
+func (n *simulatedEndpoint) Broadcast(ctx context.Context, payload interface{}) error {
+	for to := 0; to < n.Nodes(); to++ {
+		if common.NodeIndex(to) == n.self {
+			continue
+		}
+		if err := n.Send(ctx, common.NodeIndex(to), payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *simulatedEndpoint) Receive(ctx context.Context) (*Envelope, error) {
+	return n.network.receive(ctx, n.self)
+}
@@ -0,0 +1,150 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newTestNetwork(t *testing.T, config SimulatedConfig) (*SimulatedNetwork, []Network) {
+	network, err := NewSimulatedNetwork(config)
+	assert.Nil(t, err)
+	nodes := make([]Network, config.Nodes, config.Nodes)
+	for i := range nodes {
+		nodes[i], err = network.Node(common.NodeIndex(i))
+		assert.Nil(t, err)
+	}
+	return network, nodes
+}
+
+func receiveAll(t *testing.T, node Network, count int) []*Envelope {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var received []*Envelope
+	for len(received) < count {
+		e, err := node.Receive(ctx)
+		if !assert.Nil(t, err) {
+			break
+		}
+		received = append(received, e)
+	}
+	return received
+}
+
+func TestSimulatedNetworkKeepsLinksInOrderUnlessReordering(t *testing.T) {
+	for _, reorder := range []bool{false, true} {
+		network, nodes := newTestNetwork(t, SimulatedConfig{Nodes: 2, MaxLatency: 5 * time.Millisecond, Reorder: reorder})
+		const count = 100
+		for i := 0; i < count; i++ {
+			assert.Nil(t, nodes[0].Send(context.Background(), 1, i))
+		}
+		inOrder := true
+		for i, e := range receiveAll(t, nodes[1], count) {
+			assert.Equal(t, common.NodeIndex(0), e.From)
+			assert.Equal(t, common.NodeIndex(1), e.To)
+			inOrder = inOrder && e.Payload == uint64(i)
+		}
+		assert.Equal(t, !reorder, inOrder)
+		assert.Equal(t, SimulatedStats{Sent: count, Delivered: count}, network.Stats())
+		network.Close()
+	}
+}
+
+func TestSimulatedNetworkDropsMessages(t *testing.T) {
+	network, nodes := newTestNetwork(t, SimulatedConfig{Nodes: 3, DropRate: 1})
+	assert.Nil(t, nodes[0].Broadcast(context.Background(), "lost"))
+	network.SetDropRate(0.5)
+	for i := 0; i < 200; i++ {
+		assert.Nil(t, nodes[0].Send(context.Background(), 1, i))
+	}
+	stats := network.Stats()
+	assert.Equal(t, 202, stats.Sent)
+	assert.InDelta(t, 102, stats.Dropped, 30)
+	receiveAll(t, nodes[1], stats.Sent-stats.Dropped)
+	assert.Equal(t, stats.Sent, network.Stats().Delivered+stats.Dropped)
+
+	network.SetDropRate(0)
+	assert.Nil(t, nodes[0].Send(context.Background(), 2, "kept"))
+	assert.Equal(t, "kept", receiveAll(t, nodes[2], 1)[0].Payload)
+}
+
+func TestSimulatedNetworkHoldsMessagesAcrossPartitions(t *testing.T) {
+	network, nodes := newTestNetwork(t, SimulatedConfig{Nodes: 4})
+	network.Partition([]common.NodeIndex{0, 1})
+	assert.Nil(t, nodes[0].Broadcast(context.Background(), "before heal"))
+	assert.Equal(t, common.NodeIndex(0), receiveAll(t, nodes[1], 1)[0].From)
+	// nodes 2 and 3 form the other side
+	assert.Nil(t, nodes[2].Send(context.Background(), 3, "same side"))
+	assert.Equal(t, "same side", receiveAll(t, nodes[3], 1)[0].Payload)
+	assert.Eventually(t, func() bool { return network.Stats().Held == 2 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	_, err := nodes[2].Receive(ctx)
+	cancel()
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	network.Heal()
+	assert.Equal(t, "before heal", receiveAll(t, nodes[2], 1)[0].Payload)
+	assert.Equal(t, "before heal", receiveAll(t, nodes[3], 1)[0].Payload)
+	assert.Equal(t, SimulatedStats{Sent: 4, Delivered: 4}, network.Stats())
+}
+
+func TestSimulatedNetworkClose(t *testing.T) {
+	network, nodes := newTestNetwork(t, SimulatedConfig{Nodes: 2, MinLatency: time.Hour, MaxLatency: time.Hour})
+	assert.Nil(t, nodes[0].Send(context.Background(), 1, "in flight"))
+	assert.True(t, errors.Is(nodes[0].Send(context.Background(), 2, "nobody"), ErrUnknownNode))
+	_, err := network.Node(2)
+	assert.True(t, errors.Is(err, ErrUnknownNode))
+
+	done := make(chan error)
+	go func() {
+		_, err := nodes[1].Receive(context.Background())
+		done <- err
+	}()
+	network.Close()
+	assert.Equal(t, ErrClosed, <-done)
+	assert.Equal(t, ErrClosed, nodes[0].Send(context.Background(), 1, "late"))
+
+	_, err = NewSimulatedNetwork(SimulatedConfig{Nodes: 2, MinLatency: 2, MaxLatency: 1})
+	assert.NotNil(t, err)
+	_, err = NewSimulatedNetwork(SimulatedConfig{Nodes: 2, DropRate: 2})
+	assert.NotNil(t, err)
+}
+
+// failingCodec refuses to encode nil and to decode the encoding of "bad".
+type failingCodec struct{}
+
+func (failingCodec) Encode(payload interface{}) ([]byte, error) {
+	if payload == nil {
+		return nil, errors.New("nothing to encode")
+	}
+	return CBOR.Encode(payload)
+}
+
+func (failingCodec) Decode(data []byte) (interface{}, error) {
+	payload, err := CBOR.Decode(data)
+	if err == nil && payload == "bad" {
+		return nil, errors.New("bad payload")
+	}
+	return payload, err
+}
+
+func TestSimulatedNetworkEncodesPayloads(t *testing.T) {
+	network, nodes := newTestNetwork(t, SimulatedConfig{Nodes: 2, MaxLatency: time.Millisecond, Codec: failingCodec{}})
+	defer network.Close()
+	// the receiver gets a copy, not the memory of the sender
+	sent := []string{"a", "b"}
+	assert.Nil(t, nodes[0].Send(context.Background(), 1, sent))
+	sent[0] = "changed"
+	assert.Equal(t, []interface{}{"a", "b"}, receiveAll(t, nodes[1], 1)[0].Payload)
+
+	assert.NotNil(t, nodes[0].Send(context.Background(), 1, nil))
+	assert.Nil(t, nodes[0].Send(context.Background(), 1, "bad"))
+	_, err := nodes[1].Receive(context.Background())
+	assert.True(t, errors.Is(err, ErrUndecodable))
+	assert.Nil(t, nodes[0].Send(context.Background(), 1, "good"))
+	assert.Equal(t, "good", receiveAll(t, nodes[1], 1)[0].Payload)
+}
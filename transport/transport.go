@@ -0,0 +1,64 @@
+package transport
+
+import (
+	"context"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrClosed      = errors.New("network closed")
+	ErrUnknownNode = errors.New("unknown node")
+	// ErrUndecodable is returned by Receive for a message whose payload
+	// the codec rejects. The network stays usable.
+	ErrUndecodable = errors.New("undecodable payload")
+)
+
+// Envelope is a message as it is received. From is set by the network, not
+// by the sender, so a node cannot send in the name of another.
+type Envelope struct {
+	From    common.NodeIndex
+	To      common.NodeIndex
+	Payload interface{}
+}
+
+// Network is the view one node has of the network connecting the nodes
+// 0..Nodes()-1. Payloads are encoded with a Codec when they are sent and
+// decoded when they are received, so sender and receiver never share them.
+type Network interface {
+	// Self returns the index of the local node.
+	Self() common.NodeIndex
+	Nodes() int
+	// Send sends payload to one node.
+	Send(ctx context.Context, to common.NodeIndex, payload interface{}) error
+	// Broadcast sends payload to every node but the local one.
+	Broadcast(ctx context.Context, payload interface{}) error
+	// Receive waits for the next message addressed to the local node.
+	Receive(ctx context.Context) (*Envelope, error)
+}
+
+// Codec converts payloads to and from the bytes a network carries.
+type Codec interface {
+	Encode(payload interface{}) ([]byte, error)
+	Decode(data []byte) (interface{}, error)
+}
+
+var (
+	// CBOR encodes payloads as canonical CBOR and decodes them into the
+	// generic values of package cbor, e.g. uint64 for a positive int.
+	CBOR = cborCodec{}
+)
+
+type cborCodec struct{}
+
+func (cborCodec) Encode(payload interface{}) ([]byte, error) {
+	return common.MarshalCanonical(payload)
+}
+
+func (cborCodec) Decode(data []byte) (interface{}, error) {
+	var payload interface{}
+	if err := common.UnmarshalStrict(data, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
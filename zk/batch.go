@@ -0,0 +1,131 @@
+package zk
+
+import (
+	"fmt"
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	ro2 "github.com/PlatONnetwork/tecdsa/ro"
+)
+
+const BatchVerificationDst = "ic-crypto-tecdsa-zk-batch-verify"
+
+// equation is a verification equation sum(scalars[i] * points[i]) == 0.
+// Every proof in commitment form is checked by one or two of them.
+type equation struct {
+	points  []curve.EccPoint
+	scalars []curve.EccScalar
+}
+
+func (e equation) holds() bool {
+	sum, err := curve.Point.MultiScalarMul(e.points, e.scalars)
+	return err == nil && sum.IsInfinity()
+}
+
+func verifyEquations(equations []equation) error {
+	for _, e := range equations {
+		if !e.holds() {
+			return common.ErrProofVerification
+		}
+	}
+	return nil
+}
+
+// BatchVerifier checks many proofs with one multi-scalar multiplication.
+// The verification equations of the added proofs are weighted with
+// coefficients derived from all the proofs and summed; the sum is zero
+// with overwhelming probability only if every equation holds. A failing
+// batch does not tell which proof is wrong, so callers then verify the
+// proofs one by one.
+type BatchVerifier struct {
+	curveType curve.EccCurveType
+	equations []equation
+	// the challenges and responses of the proofs, which bind their
+	// instances and commitments, to derive the coefficients from
+	bound []curve.EccScalar
+}
+
+func NewBatchVerifier(curveType curve.EccCurveType) *BatchVerifier {
+	return &BatchVerifier{curveType: curveType}
+}
+
+func (b *BatchVerifier) Len() int {
+	return len(b.equations)
+}
+
+func (b *BatchVerifier) add(equations []equation, bound ...curve.EccScalar) error {
+	for _, e := range equations {
+		for i := range e.points {
+			if e.points[i].CurveType() != b.curveType || e.scalars[i].CurveType() != b.curveType {
+				return common.ErrCurveMismatch
+			}
+		}
+	}
+	b.equations = append(b.equations, equations...)
+	b.bound = append(b.bound, bound...)
+	return nil
+}
+
+// Append adds the proofs of other, e.g. a batch built on another goroutine.
+func (b *BatchVerifier) Append(other *BatchVerifier) error {
+	if other.curveType != b.curveType {
+		return common.ErrCurveMismatch
+	}
+	b.equations = append(b.equations, other.equations...)
+	b.bound = append(b.bound, other.bound...)
+	return nil
+}
+
+func (b *BatchVerifier) AddDLogEquivalence(proof *ProofOfDLogEquivalence, g, h, gx, hx curve.EccPoint, associatedData []byte) error {
+	equations, challenge, err := proof.equations(g, h, gx, hx, associatedData)
+	if err != nil {
+		return err
+	}
+	return b.add(equations, challenge, proof.response)
+}
+
+func (b *BatchVerifier) AddEqualOpenings(proof *ProofOfEqualOpenings, pedersen, simple curve.EccPoint, associatedData []byte) error {
+	equations, challenge, err := proof.equations(pedersen, simple, associatedData)
+	if err != nil {
+		return err
+	}
+	return b.add(equations, challenge, proof.response)
+}
+
+func (b *BatchVerifier) AddProduct(proof *ProofOfProduct, lhsCom, rhsCom, productCom curve.EccPoint, associatedData []byte) error {
+	equations, challenge, err := proof.equations(lhsCom, rhsCom, productCom, associatedData)
+	if err != nil {
+		return err
+	}
+	return b.add(equations, challenge, proof.response1, proof.response2)
+}
+
+// Verify checks sum(w_k * equation_k) == 0. An empty batch verifies.
+func (b *BatchVerifier) Verify() error {
+	if len(b.equations) == 0 {
+		return nil
+	}
+	ro := ro2.NewRandomOracle(BatchVerificationDst)
+	for i, s := range b.bound {
+		ro.AddScalar(fmt.Sprintf("bound[%d]", i), s)
+	}
+	weights, err := ro.OutputScalars(b.curveType, len(b.equations))
+	if err != nil {
+		return err
+	}
+	var points []curve.EccPoint
+	var scalars []curve.EccScalar
+	for k, e := range b.equations {
+		for i := range e.points {
+			points = append(points, e.points[i])
+			scalars = append(scalars, e.scalars[i].Times(weights[k]))
+		}
+	}
+	sum, err := curve.Point.MultiScalarMul(points, scalars)
+	if err != nil {
+		return err
+	}
+	if !sum.IsInfinity() {
+		return common.ErrProofVerification
+	}
+	return nil
+}
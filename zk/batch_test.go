@@ -0,0 +1,53 @@
+package zk
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	seed2 "github.com/PlatONnetwork/tecdsa/seed"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBatchVerifierChecksAllProofsAtOnce(t *testing.T) {
+	rng := rng()
+	ad := []byte("ad")
+	for _, curveType := range []curve.EccCurveType{curve.K256, curve.P256, curve.ED25519} {
+		g := curve.Point.GeneratorG(curveType)
+		h := curve.Point.GeneratorH(curveType)
+		x := curve.Scalar.Random(curveType, rng)
+		y := curve.Scalar.Random(curveType, rng)
+		product := x.Times(y)
+		productMasking := curve.Scalar.Random(curveType, rng)
+
+		dlog, err := ProofOfDLogEquivalenceIns.Create(seed2.FromRng(rng), x, g, h, ad)
+		assert.Nil(t, err)
+		opening, err := ProofOfEqualOpeningsIns.Create(seed2.FromRng(rng), x, y, ad)
+		assert.Nil(t, err)
+		proof, err := ProofOfProductIns.Create(seed2.FromRng(rng), x, y, x, product, productMasking, ad)
+		assert.Nil(t, err)
+
+		build := func(productCom curve.EccPoint) *BatchVerifier {
+			batch := NewBatchVerifier(curveType)
+			assert.Nil(t, batch.AddDLogEquivalence(dlog, g, h, g.Times(x), h.Times(x), ad))
+			assert.Nil(t, batch.AddEqualOpenings(opening, curve.Point.Pedersen(x, y), curve.Point.MulByG(x), ad))
+			other := NewBatchVerifier(curveType)
+			assert.Nil(t, other.AddProduct(proof, curve.Point.MulByG(x), curve.Point.Pedersen(y, x), productCom, ad))
+			assert.Nil(t, batch.Append(other))
+			return batch
+		}
+		batch := build(curve.Point.Pedersen(product, productMasking))
+		assert.Equal(t, 5, batch.Len())
+		assert.Nil(t, batch.Verify())
+
+		// one wrong statement fails the whole batch
+		batch = build(curve.Point.Pedersen(product, x))
+		assert.ErrorIs(t, batch.Verify(), common.ErrProofVerification)
+
+		assert.Nil(t, NewBatchVerifier(curveType).Verify())
+		otherCurve := curve.K256
+		if curveType == curve.K256 {
+			otherCurve = curve.P256
+		}
+		assert.ErrorIs(t, NewBatchVerifier(otherCurve).Append(batch), common.ErrCurveMismatch)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"github.com/PlatONnetwork/tecdsa/curve"
 	ro2 "github.com/PlatONnetwork/tecdsa/ro"
 	"github.com/PlatONnetwork/tecdsa/seed"
-	"github.com/pkg/errors"
 )
 
 var (
@@ -13,9 +12,14 @@ var (
 
 type proofOfDLogEquivalenceInstance struct {
 }
+
+// ProofOfDLogEquivalence is in commitment form: it holds the prover's
+// commitments instead of the challenge, so that the verification equations
+// of many proofs can be checked together with a BatchVerifier.
 type ProofOfDLogEquivalence struct {
-	challenge curve.EccScalar
-	response  curve.EccScalar
+	commitment1 curve.EccPoint
+	commitment2 curve.EccPoint
+	response    curve.EccScalar
 }
 
 type ProofOfDLogEquivalenceInstance struct {
@@ -48,14 +52,6 @@ func (proofOfDLogEquivalenceInstance) FromCommitments(g curve.EccPoint, h curve.
 	}, nil
 }
 
-func (p *ProofOfDLogEquivalenceInstance) RecoverCommitments(proof *ProofOfDLogEquivalence) (curve.EccPoint, curve.EccPoint, error) {
-	gz := p.g.Clone().ScalarMul(p.g, proof.response)
-	hz := p.h.Clone().ScalarMul(p.h, proof.response)
-	gr := gz.SubPoints(gz, p.gx.Clone().ScalarMul(p.gx, proof.challenge))
-	hr := hz.SubPoints(hz, p.hx.Clone().ScalarMul(p.hx, proof.challenge))
-	return gr, hr, nil
-}
-
 func (p *ProofOfDLogEquivalenceInstance) HashToChallenge(c1 curve.EccPoint, c2 curve.EccPoint, associatedData []byte) (curve.EccScalar, error) {
 	ro := ro2.NewRandomOracle(ProofOfDlogEquivDst)
 	ro.AddBytesString("associated_data", associatedData)
@@ -85,48 +81,56 @@ func (p *proofOfDLogEquivalenceInstance) Create(seed *seed.Seed, x curve.EccScal
 	rG := g.Clone().ScalarMul(g, r)
 	rH := h.Clone().ScalarMul(h, r)
 	challenge, err := instance.HashToChallenge(rG, rH, associatedData)
+	if err != nil {
+		return nil, err
+	}
 	response := x.Clone().Mul(x, challenge)
 	response = response.Add(response, r)
 	return &ProofOfDLogEquivalence{
-		challenge: challenge,
-		response:  response,
+		commitment1: rG,
+		commitment2: rH,
+		response:    response,
 	}, nil
 }
 
 /*
- * gz = s * G
- * hz = s * H
- * gr = gz - gx * m = (x * m + r) * G - x * m * G = r*G
- * hr = hz - hx * m = (x * m + r) * H - x * m * H = r*H
- * m' = H(ad, g, h, gx, hx, gr, hr)
- * m == m`
+ * m = H(ad, g, h, gx, hx, gr, hr)
+ * s * G - m * gx - gr = (x * m + r) * G - x * m * G - r * G = 0
+ * s * H - m * hx - hr = (x * m + r) * H - x * m * H - r * H = 0
  */
-func (p *ProofOfDLogEquivalence) Verify(g, h, gx, hx curve.EccPoint, associatedData []byte) error {
+func (p *ProofOfDLogEquivalence) equations(g, h, gx, hx curve.EccPoint, associatedData []byte) ([]equation, curve.EccScalar, error) {
 	instance, err := ProofOfDLogEquivalenceIns.FromCommitments(g, h, gx, hx)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	rG, rH, err := instance.RecoverCommitments(p)
+	challenge, err := instance.HashToChallenge(p.commitment1, p.commitment2, associatedData)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	challenge, err := instance.HashToChallenge(rG, rH, associatedData)
+	negChallenge := challenge.Negated()
+	minusOne := curve.Scalar.One(instance.curveType).Negated()
+	return []equation{
+		{points: []curve.EccPoint{g, gx, p.commitment1}, scalars: []curve.EccScalar{p.response, negChallenge, minusOne}},
+		{points: []curve.EccPoint{h, hx, p.commitment2}, scalars: []curve.EccScalar{p.response, negChallenge, minusOne}},
+	}, challenge, nil
+}
+
+func (p *ProofOfDLogEquivalence) Verify(g, h, gx, hx curve.EccPoint, associatedData []byte) error {
+	equations, _, err := p.equations(g, h, gx, hx, associatedData)
 	if err != nil {
 		return err
 	}
-	if challenge.Equal(p.challenge) == 0 {
-		return errors.New("invalid proof")
-	}
-	return nil
+	return verifyEquations(equations)
 }
 
 func (p *ProofOfDLogEquivalence) CurveType() curve.EccCurveType {
-	return p.challenge.CurveType()
+	return p.response.CurveType()
 }
 
 func (p ProofOfDLogEquivalence) Clone() *ProofOfDLogEquivalence {
 	return &ProofOfDLogEquivalence{
-		challenge: p.challenge.Clone(),
-		response:  p.response.Clone(),
+		commitment1: p.commitment1.Clone(),
+		commitment2: p.commitment2.Clone(),
+		response:    p.response.Clone(),
 	}
 }
@@ -0,0 +1,230 @@
+package zk
+
+import (
+	"github.com/PlatONnetwork/tecdsa/common"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	"github.com/pkg/errors"
+)
+
+// The proofs are encoded as canonical CBOR maps (or JSON objects) holding
+// the wire version, the curve, the prover's commitments as tagged points
+// and the big endian responses. Decoding is strict: other versions,
+// unknown curves, points of another curve, scalars of the wrong length or
+// not below the group order, trailing bytes and non canonical encodings
+// are rejected.
+
+type proofOfDLogEquivalenceWire struct {
+	Version     uint8
+	CurveType   curve.EccCurveType
+	Commitment1 []byte
+	Commitment2 []byte
+	Response    []byte
+}
+
+type proofOfEqualOpeningsWire struct {
+	Version    uint8
+	CurveType  curve.EccCurveType
+	Commitment []byte
+	Response   []byte
+}
+
+type proofOfProductWire struct {
+	Version     uint8
+	CurveType   curve.EccCurveType
+	Commitment1 []byte
+	Commitment2 []byte
+	Response1   []byte
+	Response2   []byte
+}
+
+func checkHeader(version uint8, curveType curve.EccCurveType) error {
+	if version != common.WireVersion {
+		return errors.Wrapf(common.ErrInvalidEncoding, "unsupported proof version %d", version)
+	}
+	if _, ok := curve.Lookup(curveType); !ok {
+		return common.ErrUnknownCurve
+	}
+	return nil
+}
+
+func decodePoints(curveType curve.EccCurveType, encoded ...[]byte) ([]curve.EccPoint, error) {
+	points := make([]curve.EccPoint, len(encoded), len(encoded))
+	for i, bytes := range encoded {
+		pt, err := curve.Point.DeserializeTagged(curveType, bytes)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = pt
+	}
+	return points, nil
+}
+
+func decodeScalars(curveType curve.EccCurveType, encoded ...[]byte) ([]curve.EccScalar, error) {
+	scalars := make([]curve.EccScalar, len(encoded), len(encoded))
+	for i, bytes := range encoded {
+		s, err := curve.Scalar.DeserializeCanonical(curveType, bytes)
+		if err != nil {
+			return nil, err
+		}
+		scalars[i] = s
+	}
+	return scalars, nil
+}
+
+func (p ProofOfDLogEquivalence) wire() (*proofOfDLogEquivalenceWire, error) {
+	return &proofOfDLogEquivalenceWire{
+		Version:     common.WireVersion,
+		CurveType:   p.response.CurveType(),
+		Commitment1: p.commitment1.SerializeTagged(),
+		Commitment2: p.commitment2.SerializeTagged(),
+		Response:    p.response.Serialize(),
+	}, nil
+}
+
+func (p *ProofOfDLogEquivalence) fromWire(w *proofOfDLogEquivalenceWire) error {
+	if err := checkHeader(w.Version, w.CurveType); err != nil {
+		return err
+	}
+	points, err := decodePoints(w.CurveType, w.Commitment1, w.Commitment2)
+	if err != nil {
+		return err
+	}
+	scalars, err := decodeScalars(w.CurveType, w.Response)
+	if err != nil {
+		return err
+	}
+	p.commitment1, p.commitment2, p.response = points[0], points[1], scalars[0]
+	return nil
+}
+
+func (proofOfDLogEquivalenceInstance) Deserialize(data []byte) (*ProofOfDLogEquivalence, error) {
+	return common.DeserializeWire[ProofOfDLogEquivalence](data)
+}
+
+func (p ProofOfDLogEquivalence) Serialize() ([]byte, error) {
+	return p.MarshalCBOR()
+}
+
+func (p ProofOfDLogEquivalence) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(p.wire())
+}
+
+func (p *ProofOfDLogEquivalence) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, p.fromWire)
+}
+
+func (p ProofOfDLogEquivalence) MarshalJSON() ([]byte, error) {
+	return common.MarshalWireJSON(p.wire())
+}
+
+func (p *ProofOfDLogEquivalence) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalWireJSON(data, p.fromWire)
+}
+
+func (p ProofOfEqualOpenings) wire() (*proofOfEqualOpeningsWire, error) {
+	return &proofOfEqualOpeningsWire{
+		Version:    common.WireVersion,
+		CurveType:  p.response.CurveType(),
+		Commitment: p.commitment.SerializeTagged(),
+		Response:   p.response.Serialize(),
+	}, nil
+}
+
+func (p *ProofOfEqualOpenings) fromWire(w *proofOfEqualOpeningsWire) error {
+	if err := checkHeader(w.Version, w.CurveType); err != nil {
+		return err
+	}
+	points, err := decodePoints(w.CurveType, w.Commitment)
+	if err != nil {
+		return err
+	}
+	scalars, err := decodeScalars(w.CurveType, w.Response)
+	if err != nil {
+		return err
+	}
+	p.commitment, p.response = points[0], scalars[0]
+	return nil
+}
+
+func (proofOfEqualOpeningsInstance) Deserialize(data []byte) (*ProofOfEqualOpenings, error) {
+	return common.DeserializeWire[ProofOfEqualOpenings](data)
+}
+
+func (p *ProofOfEqualOpenings) CurveType() curve.EccCurveType {
+	return p.response.CurveType()
+}
+
+func (p ProofOfEqualOpenings) Serialize() ([]byte, error) {
+	return p.MarshalCBOR()
+}
+
+func (p ProofOfEqualOpenings) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(p.wire())
+}
+
+func (p *ProofOfEqualOpenings) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, p.fromWire)
+}
+
+func (p ProofOfEqualOpenings) MarshalJSON() ([]byte, error) {
+	return common.MarshalWireJSON(p.wire())
+}
+
+func (p *ProofOfEqualOpenings) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalWireJSON(data, p.fromWire)
+}
+
+func (p ProofOfProduct) wire() (*proofOfProductWire, error) {
+	return &proofOfProductWire{
+		Version:     common.WireVersion,
+		CurveType:   p.response1.CurveType(),
+		Commitment1: p.commitment1.SerializeTagged(),
+		Commitment2: p.commitment2.SerializeTagged(),
+		Response1:   p.response1.Serialize(),
+		Response2:   p.response2.Serialize(),
+	}, nil
+}
+
+func (p *ProofOfProduct) fromWire(w *proofOfProductWire) error {
+	if err := checkHeader(w.Version, w.CurveType); err != nil {
+		return err
+	}
+	points, err := decodePoints(w.CurveType, w.Commitment1, w.Commitment2)
+	if err != nil {
+		return err
+	}
+	scalars, err := decodeScalars(w.CurveType, w.Response1, w.Response2)
+	if err != nil {
+		return err
+	}
+	p.commitment1, p.commitment2, p.response1, p.response2 = points[0], points[1], scalars[0], scalars[1]
+	return nil
+}
+
+func (proofOfProductInstance) Deserialize(data []byte) (*ProofOfProduct, error) {
+	return common.DeserializeWire[ProofOfProduct](data)
+}
+
+func (p *ProofOfProduct) CurveType() curve.EccCurveType {
+	return p.response1.CurveType()
+}
+
+func (p ProofOfProduct) Serialize() ([]byte, error) {
+	return p.MarshalCBOR()
+}
+
+func (p ProofOfProduct) MarshalCBOR() ([]byte, error) {
+	return common.MarshalWire(p.wire())
+}
+
+func (p *ProofOfProduct) UnmarshalCBOR(data []byte) error {
+	return common.UnmarshalWire(data, p.fromWire)
+}
+
+func (p ProofOfProduct) MarshalJSON() ([]byte, error) {
+	return common.MarshalWireJSON(p.wire())
+}
+
+func (p *ProofOfProduct) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalWireJSON(data, p.fromWire)
+}
@@ -0,0 +1,77 @@
+package zk
+
+import (
+	"encoding/json"
+	"github.com/PlatONnetwork/tecdsa/curve"
+	seed2 "github.com/PlatONnetwork/tecdsa/seed"
+	"github.com/PlatONnetwork/tecdsa/testutils/wiretest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestProofSerializationRoundTrips(t *testing.T) {
+	rng := rng()
+	ad := []byte("ad")
+	for _, curveType := range []curve.EccCurveType{curve.K256, curve.P256, curve.ED25519} {
+		x := curve.Scalar.Random(curveType, rng)
+		y := curve.Scalar.Random(curveType, rng)
+		g := curve.Point.GeneratorG(curveType)
+		h := curve.Point.GeneratorH(curveType)
+
+		dlog, err := ProofOfDLogEquivalenceIns.Create(seed2.FromRng(rng), x, g, h, ad)
+		assert.Nil(t, err)
+		bytes, err := dlog.Serialize()
+		assert.Nil(t, err)
+		decodedDlog, err := ProofOfDLogEquivalenceIns.Deserialize(bytes)
+		assert.Nil(t, err)
+		assert.Nil(t, decodedDlog.Verify(g, h, g.Times(x), h.Times(x), ad))
+
+		opening, err := ProofOfEqualOpeningsIns.Create(seed2.FromRng(rng), x, y, ad)
+		assert.Nil(t, err)
+		bytes, err = opening.Serialize()
+		assert.Nil(t, err)
+		decodedOpening, err := ProofOfEqualOpeningsIns.Deserialize(bytes)
+		assert.Nil(t, err)
+		assert.Nil(t, decodedOpening.Verify(curve.Point.Pedersen(x, y), curve.Point.MulByG(x), ad))
+
+		product := x.Times(y)
+		productMasking := curve.Scalar.Random(curveType, rng)
+		proof, err := ProofOfProductIns.Create(seed2.FromRng(rng), x, y, x, product, productMasking, ad)
+		assert.Nil(t, err)
+		bytes, err = proof.Serialize()
+		assert.Nil(t, err)
+		decodedProduct, err := ProofOfProductIns.Deserialize(bytes)
+		assert.Nil(t, err)
+		assert.Nil(t, decodedProduct.Verify(curve.Point.MulByG(x), curve.Point.Pedersen(y, x), curve.Point.Pedersen(product, productMasking), ad))
+
+		js, err := json.Marshal(proof)
+		assert.Nil(t, err)
+		var fromJson ProofOfProduct
+		assert.Nil(t, json.Unmarshal(js, &fromJson))
+		again, err := fromJson.Serialize()
+		assert.Nil(t, err)
+		assert.Equal(t, bytes, again)
+	}
+}
+
+func TestProofDeserializationIsStrict(t *testing.T) {
+	rng := rng()
+	x := curve.Scalar.Random(curve.K256, rng)
+	proof, err := ProofOfDLogEquivalenceIns.Create(seed2.FromRng(rng), x, curve.Point.GeneratorG(curve.K256), curve.Point.GeneratorH(curve.K256), nil)
+	assert.Nil(t, err)
+	bytes, err := proof.Serialize()
+	assert.Nil(t, err)
+	_, err = ProofOfDLogEquivalenceIns.Deserialize(append(bytes, 0))
+	assert.NotNil(t, err)
+
+	invalid := map[string]func(w *proofOfDLogEquivalenceWire){
+		"version":        func(w *proofOfDLogEquivalenceWire) { w.Version++ },
+		"unknown curve":  func(w *proofOfDLogEquivalenceWire) { w.CurveType = curve.EccCurveType(0) },
+		"order":          func(w *proofOfDLogEquivalenceWire) { w.Response = curve.GroupOrder.Bytes() },
+		"long scalar":    func(w *proofOfDLogEquivalenceWire) { w.Response = append([]byte{0}, w.Response...) },
+		"missing scalar": func(w *proofOfDLogEquivalenceWire) { w.Response = nil },
+		"missing point":  func(w *proofOfDLogEquivalenceWire) { w.Commitment1 = nil },
+		"point tag":      func(w *proofOfDLogEquivalenceWire) { w.Commitment2[0] = curve.P256.Tag() },
+	}
+	wiretest.AssertRejected(t, bytes, ProofOfDLogEquivalenceIns.Deserialize, invalid)
+}
@@ -4,7 +4,6 @@ import (
 	"github.com/PlatONnetwork/tecdsa/curve"
 	ro2 "github.com/PlatONnetwork/tecdsa/ro"
 	"github.com/PlatONnetwork/tecdsa/seed"
-	"github.com/pkg/errors"
 )
 
 const (
@@ -17,9 +16,10 @@ var (
 	ProofOfEqualOpeningsIns = proofOfEqualOpeningsInstance{}
 )
 
+// ProofOfEqualOpenings is in commitment form, like ProofOfDLogEquivalence.
 type ProofOfEqualOpenings struct {
-	challenge curve.EccScalar
-	response  curve.EccScalar
+	commitment curve.EccPoint
+	response   curve.EccScalar
 }
 
 type ProofOfEqualOpeningsInstance struct {
@@ -69,7 +69,7 @@ func (p proofOfEqualOpeningsInstance) Create(seed *seed.Seed, secret curve.EccSc
 	 */
 	instance := p.FromWitness(secret, masking)
 	r := curve.Scalar.Random(instance.curveType, seed.Rng())
-	rcom := instance.h.Clone().ScalarMul(instance.h, r)
+	rcom := curve.Point.MulByH(r)
 	challenge, err := instance.HashToChallenge(rcom, associatedData)
 	if err != nil {
 		return nil, err
@@ -77,28 +77,11 @@ func (p proofOfEqualOpeningsInstance) Create(seed *seed.Seed, secret curve.EccSc
 	response := masking.Clone().Mul(masking, challenge)
 	response = response.Add(response, r)
 	return &ProofOfEqualOpenings{
-		challenge: challenge,
-		response:  response,
+		commitment: rcom,
+		response:   response,
 	}, nil
 }
 
-func (p *ProofOfEqualOpeningsInstance) RecoverCommitments(proof *ProofOfEqualOpenings) curve.EccPoint {
-	/*
-	 * a = g^s · h^m, b = g^s
-	 * com = h^r
-	 * challenge = H(com,ad)
-	 * response = (m · challenge) + r
-	 * amb = a-b
-	 * camb = amb * challenge
-	 * hc = h^response
-	 * com = h^((m · challenge) + r) - (g^s · h^m-g^s)^challenge
-	 */
-	amb := p.a.Clone().SubPoints(p.a, p.b)
-	camb := amb.Clone().ScalarMul(amb, proof.challenge)
-	hc := p.h.Clone().ScalarMul(p.h, proof.response)
-	return hc.SubPoints(hc, camb)
-}
-
 func (p *ProofOfEqualOpeningsInstance) HashToChallenge(commitment curve.EccPoint, ad []byte) (curve.EccScalar, error) {
 	ro := ro2.NewRandomOracle(ProofOfEqualOpeningsDst)
 	ro.AddBytesString("associated_data", ad)
@@ -110,22 +93,34 @@ func (p *ProofOfEqualOpeningsInstance) HashToChallenge(commitment curve.EccPoint
 	return ro.OutputScalar(p.curveType)
 }
 
-func (p *ProofOfEqualOpenings) Verify(pedersen curve.EccPoint, simple curve.EccPoint, associatedData []byte) error {
+func (p *ProofOfEqualOpenings) equations(pedersen curve.EccPoint, simple curve.EccPoint, associatedData []byte) ([]equation, curve.EccScalar, error) {
+	/*
+	 * a = g^s · h^m, b = g^s
+	 * challenge = H(com,ad)
+	 * h^response - (a-b)^challenge - com = h^((m · challenge) + r) - h^(m · challenge) - h^r = 0
+	 */
 	instance := ProofOfEqualOpeningsIns.FromCommitments(pedersen, simple)
-	rcom := instance.RecoverCommitments(p)
-	challenge, err := instance.HashToChallenge(rcom, associatedData)
+	challenge, err := instance.HashToChallenge(p.commitment, associatedData)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	if p.challenge.Equal(challenge) == 0 {
-		return errors.New("invalid proof")
+	minusOne := curve.Scalar.One(instance.curveType).Negated()
+	return []equation{
+		{points: []curve.EccPoint{instance.h, instance.a, instance.b, p.commitment}, scalars: []curve.EccScalar{p.response, challenge.Negated(), challenge, minusOne}},
+	}, challenge, nil
+}
+
+func (p *ProofOfEqualOpenings) Verify(pedersen curve.EccPoint, simple curve.EccPoint, associatedData []byte) error {
+	equations, _, err := p.equations(pedersen, simple, associatedData)
+	if err != nil {
+		return err
 	}
-	return err
+	return verifyEquations(equations)
 }
 
 func (p *ProofOfEqualOpenings) Clone() *ProofOfEqualOpenings {
 	return &ProofOfEqualOpenings{
-		challenge: p.challenge.Clone(),
-		response:  p.response.Clone(),
+		commitment: p.commitment.Clone(),
+		response:   p.response.Clone(),
 	}
 }
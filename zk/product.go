@@ -4,7 +4,6 @@ import (
 	"github.com/PlatONnetwork/tecdsa/curve"
 	ro2 "github.com/PlatONnetwork/tecdsa/ro"
 	"github.com/PlatONnetwork/tecdsa/seed"
-	"github.com/pkg/errors"
 )
 
 var (
@@ -12,10 +11,13 @@ var (
 )
 
 type proofOfProductInstance struct{}
+
+// ProofOfProduct is in commitment form, like ProofOfDLogEquivalence.
 type ProofOfProduct struct {
-	challenge curve.EccScalar
-	response1 curve.EccScalar
-	response2 curve.EccScalar
+	commitment1 curve.EccPoint
+	commitment2 curve.EccPoint
+	response1   curve.EccScalar
+	response2   curve.EccScalar
 }
 
 type ProofOfProductInstance struct {
@@ -31,9 +33,9 @@ func (proofOfProductInstance) FromWitness(lhs curve.EccScalar, rhs curve.EccScal
 	curveType := lhs.CurveType()
 	g := curve.Point.GeneratorG(curveType)
 	h := curve.Point.GeneratorH(curveType)
-	lhsCom := g.Clone().ScalarMul(g, lhs)
-	rhsCom := curve.Point.MulPoints(g, rhs, h, rhsMasking)
-	productCom := curve.Point.MulPoints(g, product, h, productMasking)
+	lhsCom := curve.Point.MulByG(lhs)
+	rhsCom := curve.Point.Pedersen(rhs, rhsMasking)
+	productCom := curve.Point.Pedersen(product, productMasking)
 	return &ProofOfProductInstance{
 		curveType:  curveType,
 		g:          g,
@@ -57,19 +59,12 @@ func (proofOfProductInstance) FromCommitments(lhsCom curve.EccPoint, rhsCom curv
 		productCom: productCom,
 	}
 }
-func (p *ProofOfProductInstance) RecoverCommitment(proof *ProofOfProduct) (curve.EccPoint, curve.EccPoint) {
-	r1Com := curve.Point.MulByG(proof.response1)
-	r1Com = r1Com.SubPoints(r1Com, p.lhsCom.Clone().ScalarMul(p.lhsCom, proof.challenge))
-	r2Com := curve.Point.MulPoints(p.rhsCom, proof.response1, p.h, proof.response2)
-	r2Com = r2Com.SubPoints(r2Com, p.productCom.Clone().ScalarMul(p.productCom, proof.challenge))
-	return r1Com, r2Com
-}
 
 func (p *proofOfProductInstance) Create(seed *seed.Seed, lhs curve.EccScalar, rhs curve.EccScalar, rhsMasking curve.EccScalar, product curve.EccScalar, productMasking curve.EccScalar, associatedData []byte) (*ProofOfProduct, error) {
 	instance := ProofOfProductIns.FromWitness(lhs, rhs, rhsMasking, product, productMasking)
 	rng := seed.Rng()
 	r1 := curve.Scalar.Random(lhs.CurveType(), rng)
-	r1Com := instance.g.Clone().ScalarMul(instance.g, r1)
+	r1Com := curve.Point.MulByG(r1)
 	r2 := curve.Scalar.Random(lhs.CurveType(), rng)
 	r2Com := curve.Point.MulPoints(instance.rhsCom, r1, instance.h, r2)
 	challenge, err := instance.HashToChallenge(r1Com, r2Com, associatedData)
@@ -82,10 +77,11 @@ func (p *proofOfProductInstance) Create(seed *seed.Seed, lhs curve.EccScalar, rh
 	response2 = response2.Mul(response2, challenge)
 	response2 = response2.Add(response2, r2)
 	return &ProofOfProduct{
-		challenge: challenge,
-		response1: response1,
-		response2: response2,
-	}, err
+		commitment1: r1Com,
+		commitment2: r2Com,
+		response1:   response1,
+		response2:   response2,
+	}, nil
 }
 
 func (p ProofOfProductInstance) HashToChallenge(c1 curve.EccPoint, c2 curve.EccPoint, associatedData []byte) (curve.EccScalar, error) {
@@ -101,22 +97,38 @@ func (p ProofOfProductInstance) HashToChallenge(c1 curve.EccPoint, c2 curve.EccP
 	return ro.OutputScalar(p.curveType)
 }
 
-func (p *ProofOfProduct) Verify(lhsCom curve.EccPoint, rhsCom curve.EccPoint, productCom curve.EccPoint, associatedData []byte) error {
+/*
+ * challenge = H(ad, g, h, lhs, rhs, product, r1Com, r2Com)
+ * response1 * G - challenge * lhs - r1Com = 0
+ * response1 * rhs + response2 * H - challenge * product - r2Com = 0
+ */
+func (p *ProofOfProduct) equations(lhsCom curve.EccPoint, rhsCom curve.EccPoint, productCom curve.EccPoint, associatedData []byte) ([]equation, curve.EccScalar, error) {
 	instance := ProofOfProductIns.FromCommitments(lhsCom, rhsCom, productCom)
-	r1Com, r2Com := instance.RecoverCommitment(p)
-	challenge, err := instance.HashToChallenge(r1Com, r2Com, associatedData)
+	challenge, err := instance.HashToChallenge(p.commitment1, p.commitment2, associatedData)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
-	if p.challenge.Equal(challenge) == 0 {
-		return errors.New("invalid proof")
+	negChallenge := challenge.Negated()
+	minusOne := curve.Scalar.One(instance.curveType).Negated()
+	return []equation{
+		{points: []curve.EccPoint{instance.g, lhsCom, p.commitment1}, scalars: []curve.EccScalar{p.response1, negChallenge, minusOne}},
+		{points: []curve.EccPoint{rhsCom, instance.h, productCom, p.commitment2}, scalars: []curve.EccScalar{p.response1, p.response2, negChallenge, minusOne}},
+	}, challenge, nil
+}
+
+func (p *ProofOfProduct) Verify(lhsCom curve.EccPoint, rhsCom curve.EccPoint, productCom curve.EccPoint, associatedData []byte) error {
+	equations, _, err := p.equations(lhsCom, rhsCom, productCom, associatedData)
+	if err != nil {
+		return err
 	}
-	return nil
+	return verifyEquations(equations)
 }
+
 func (p *ProofOfProduct) Clone() *ProofOfProduct {
 	return &ProofOfProduct{
-		challenge: p.challenge,
-		response1: p.response1,
-		response2: p.response2,
+		commitment1: p.commitment1.Clone(),
+		commitment2: p.commitment2.Clone(),
+		response1:   p.response1.Clone(),
+		response2:   p.response2.Clone(),
 	}
 }